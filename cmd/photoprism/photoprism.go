@@ -29,7 +29,10 @@ func main() {
 		commands.CopyCommand,
 		commands.ConvertCommand,
 		commands.ThumbsCommand,
+		commands.BlurHashCommand,
 		commands.MigrateCommand,
+		commands.UsersCommand,
+		commands.TokensCommand,
 		commands.ConfigCommand,
 		commands.VersionCommand,
 	}
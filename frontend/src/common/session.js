@@ -148,10 +148,10 @@ export default class Session {
         }
     }
 
-    login(email, password) {
+    login(user, password) {
         this.deleteToken();
 
-        return Api.post("session", {email: email, password: password}).then(
+        return Api.post("session", {user: user, password: password}).then(
             (result) => {
                 this.setConfig(result.data.config);
                 this.setToken(result.data.token);
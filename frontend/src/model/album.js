@@ -20,6 +20,9 @@ class Album extends Abstract {
             AlbumRadius: 0,
             AlbumOrder: "",
             AlbumTemplate: "",
+            AlbumFilter: "",
+            OwnerUUID: "",
+            AlbumVisibility: "",
             Links: [],
             CreatedAt: "",
             UpdatedAt: "",
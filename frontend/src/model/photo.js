@@ -10,6 +10,8 @@ class Photo extends Abstract {
             PhotoUUID: "",
             PhotoPath: "",
             PhotoName: "",
+            OwnerUUID: "",
+            PhotoVisibility: "",
             PhotoTitle: "",
             PhotoFavorite: false,
             PhotoPrivate: false,
@@ -0,0 +1,65 @@
+/*
+Package acl contains user roles and the permissions they grant.
+
+Additional information can be found in our Developer Guide:
+
+https://github.com/photoprism/photoprism/wiki
+*/
+package acl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Role represents a user role, roles with a higher level include the permissions of lower levels.
+type Role string
+
+const (
+	RoleAdmin  Role = "admin"  // Manages users, accounts and settings
+	RoleEditor Role = "editor" // Uploads, imports and edits photos, albums and labels
+	RoleViewer Role = "viewer" // Browses and downloads photos
+	RoleGuest  Role = "guest"  // Browses photos
+)
+
+var roleLevels = map[Role]int{
+	RoleGuest:  1,
+	RoleViewer: 2,
+	RoleEditor: 3,
+	RoleAdmin:  4,
+}
+
+// Roles contains all valid roles, starting with the highest level.
+var Roles = []Role{RoleAdmin, RoleEditor, RoleViewer, RoleGuest}
+
+// ParseRole returns the role with the given name.
+func ParseRole(s string) (Role, error) {
+	r := Role(strings.ToLower(strings.TrimSpace(s)))
+
+	if !r.Valid() {
+		return "", fmt.Errorf("invalid role \"%s\"", s)
+	}
+
+	return r, nil
+}
+
+// Valid returns true if the role exists.
+func (r Role) Valid() bool {
+	_, ok := roleLevels[r]
+
+	return ok
+}
+
+// Allows returns true if the role grants the permissions of the required role.
+func (r Role) Allows(required Role) bool {
+	if !r.Valid() {
+		return false
+	}
+
+	return roleLevels[r] >= roleLevels[required]
+}
+
+// String returns the role name.
+func (r Role) String() string {
+	return string(r)
+}
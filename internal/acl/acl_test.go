@@ -0,0 +1,45 @@
+package acl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRole(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		r, err := ParseRole(" Editor ")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, RoleEditor, r)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ParseRole("owner")
+		assert.EqualError(t, err, "invalid role \"owner\"")
+	})
+}
+
+func TestRole_Allows(t *testing.T) {
+	t.Run("admin", func(t *testing.T) {
+		for _, r := range Roles {
+			assert.True(t, RoleAdmin.Allows(r))
+		}
+	})
+	t.Run("viewer", func(t *testing.T) {
+		assert.True(t, RoleViewer.Allows(RoleGuest))
+		assert.True(t, RoleViewer.Allows(RoleViewer))
+		assert.False(t, RoleViewer.Allows(RoleEditor))
+		assert.False(t, RoleViewer.Allows(RoleAdmin))
+	})
+	t.Run("guest", func(t *testing.T) {
+		assert.True(t, RoleGuest.Allows(RoleGuest))
+		assert.False(t, RoleGuest.Allows(RoleViewer))
+	})
+	t.Run("invalid", func(t *testing.T) {
+		assert.False(t, Role("").Allows(RoleGuest))
+		assert.False(t, Role("owner").Allows(RoleGuest))
+	})
+}
@@ -0,0 +1,84 @@
+package acl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Scope limits what an API token may be used for, sessions have all scopes.
+type Scope string
+
+const (
+	ScopePhotosRead  Scope = "photos:read"  // Browses and downloads photos
+	ScopePhotosWrite Scope = "photos:write" // Edits photos, labels and folders
+	ScopeUpload      Scope = "upload"       // Uploads files
+	ScopeImport      Scope = "import"       // Starts import and indexing
+	ScopeAlbums      Scope = "albums"       // Manages albums
+	ScopeAdmin       Scope = "admin"        // Includes all other scopes
+)
+
+// AllScopes contains all valid scopes.
+var AllScopes = Scopes{ScopePhotosRead, ScopePhotosWrite, ScopeUpload, ScopeImport, ScopeAlbums, ScopeAdmin}
+
+// Valid returns true if the scope exists.
+func (s Scope) Valid() bool {
+	for _, scope := range AllScopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String returns the scope name.
+func (s Scope) String() string {
+	return string(s)
+}
+
+// Scopes represents the scopes granted to an API token.
+type Scopes []Scope
+
+// ParseScopes returns the scopes in a comma or space separated list.
+func ParseScopes(s string) (result Scopes, err error) {
+	for _, name := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool { return r == ',' || r == ' ' }) {
+		scope := Scope(name)
+
+		if !scope.Valid() {
+			return result, fmt.Errorf("invalid scope \"%s\"", name)
+		}
+
+		if !result.Contains(scope) {
+			result = append(result, scope)
+		}
+	}
+
+	return result, nil
+}
+
+// Contains returns true if the scope is in the list.
+func (s Scopes) Contains(scope Scope) bool {
+	for _, m := range s {
+		if m == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Allows returns true if the required scope is granted, admin grants all scopes.
+func (s Scopes) Allows(required Scope) bool {
+	return s.Contains(ScopeAdmin) || s.Contains(required)
+}
+
+// String returns a comma separated list of scopes.
+func (s Scopes) String() string {
+	names := make([]string, len(s))
+
+	for i, scope := range s {
+		names[i] = string(scope)
+	}
+
+	return strings.Join(names, ",")
+}
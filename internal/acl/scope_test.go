@@ -0,0 +1,35 @@
+package acl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseScopes(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		scopes, err := ParseScopes("photos:read, Upload,upload")
+
+		assert.Nil(t, err)
+		assert.Equal(t, Scopes{ScopePhotosRead, ScopeUpload}, scopes)
+		assert.Equal(t, "photos:read,upload", scopes.String())
+	})
+	t.Run("empty", func(t *testing.T) {
+		scopes, err := ParseScopes("")
+
+		assert.Nil(t, err)
+		assert.Empty(t, scopes)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ParseScopes("photos:read,everything")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestScopes_Allows(t *testing.T) {
+	assert.True(t, Scopes{ScopePhotosRead}.Allows(ScopePhotosRead))
+	assert.False(t, Scopes{ScopePhotosRead}.Allows(ScopeUpload))
+	assert.True(t, Scopes{ScopeAdmin}.Allows(ScopeUpload))
+	assert.False(t, Scopes{}.Allows(ScopePhotosRead))
+}
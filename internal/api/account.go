@@ -18,11 +18,6 @@ import (
 // GET /api/v1/accounts
 func GetAccounts(router *gin.RouterGroup, conf *config.Config) {
 	router.GET("/accounts", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		var f form.AccountSearch
 
 		q := query.New(conf.Db())
@@ -53,11 +48,6 @@ func GetAccounts(router *gin.RouterGroup, conf *config.Config) {
 //   id: string Account ID as returned by the API
 func GetAccount(router *gin.RouterGroup, conf *config.Config) {
 	router.GET("/accounts/:id", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		q := query.New(conf.Db())
 		id := ParseUint(c.Param("id"))
 
@@ -75,11 +65,6 @@ func GetAccount(router *gin.RouterGroup, conf *config.Config) {
 //   id: string Account ID as returned by the API
 func GetAccountDirs(router *gin.RouterGroup, conf *config.Config) {
 	router.GET("/accounts/:id/dirs", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		q := query.New(conf.Db())
 		id := ParseUint(c.Param("id"))
 
@@ -108,11 +93,6 @@ func GetAccountDirs(router *gin.RouterGroup, conf *config.Config) {
 //   id: string Account ID as returned by the API
 func ShareWithAccount(router *gin.RouterGroup, conf *config.Config) {
 	router.POST("/accounts/:id/share", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		q := query.New(conf.Db())
 		id := ParseUint(c.Param("id"))
 
@@ -154,11 +134,6 @@ func ShareWithAccount(router *gin.RouterGroup, conf *config.Config) {
 // POST /api/v1/accounts
 func CreateAccount(router *gin.RouterGroup, conf *config.Config) {
 	router.POST("/accounts", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		var f form.Account
 
 		if err := c.BindJSON(&f); err != nil {
@@ -194,11 +169,6 @@ func CreateAccount(router *gin.RouterGroup, conf *config.Config) {
 //   id: string Account ID as returned by the API
 func UpdateAccount(router *gin.RouterGroup, conf *config.Config) {
 	router.PUT("/accounts/:id", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		id := ParseUint(c.Param("id"))
 
 		q := query.New(conf.Db())
@@ -249,11 +219,6 @@ func UpdateAccount(router *gin.RouterGroup, conf *config.Config) {
 //   id: string Account ID as returned by the API
 func DeleteAccount(router *gin.RouterGroup, conf *config.Config) {
 	router.DELETE("/accounts/:id", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		id := ParseUint(c.Param("id"))
 		q := query.New(conf.Db())
 
@@ -28,14 +28,9 @@ import (
 // GET /api/v1/albums
 func GetAlbums(router *gin.RouterGroup, conf *config.Config) {
 	router.GET("/albums", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		var f form.AlbumSearch
 
-		q := query.New(conf.Db())
+		q := userQuery(c, conf)
 		err := c.MustBindWith(&f, binding.Form)
 
 		if err != nil {
@@ -60,14 +55,19 @@ func GetAlbums(router *gin.RouterGroup, conf *config.Config) {
 func GetAlbum(router *gin.RouterGroup, conf *config.Config) {
 	router.GET("/albums/:uuid", func(c *gin.Context) {
 		id := c.Param("uuid")
-		q := query.New(conf.Db())
+		q := userQuery(c, conf)
 		m, err := q.AlbumByUUID(id)
 
-		if err != nil {
+		if err != nil || !q.AlbumVisible(m.AlbumUUID) {
 			c.AbortWithStatusJSON(http.StatusNotFound, ErrAlbumNotFound)
 			return
 		}
 
+		// Share links must only be visible to users who may change them.
+		if !canEdit(CurrentUser(c), m.OwnerUUID) {
+			m.Links = nil
+		}
+
 		c.JSON(http.StatusOK, m)
 	})
 }
@@ -75,11 +75,6 @@ func GetAlbum(router *gin.RouterGroup, conf *config.Config) {
 // POST /api/v1/albums
 func CreateAlbum(router *gin.RouterGroup, conf *config.Config) {
 	router.POST("/albums", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		var f form.Album
 
 		if err := c.BindJSON(&f); err != nil {
@@ -87,9 +82,19 @@ func CreateAlbum(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
+		if !validAlbumFilter(c, f.AlbumFilter) {
+			return
+		}
+
 		q := query.New(conf.Db())
 		m := entity.NewAlbum(f.AlbumName)
 		m.AlbumFavorite = f.AlbumFavorite
+		m.AlbumFilter = strings.TrimSpace(f.AlbumFilter)
+
+		// New albums are private until shared.
+		if m.OwnerUUID = currentUserUUID(c); m.OwnerUUID != "" {
+			m.AlbumVisibility = entity.VisibilityPrivate
+		}
 
 		log.Debugf("create album: %+v %+v", f, m)
 
@@ -112,11 +117,6 @@ func CreateAlbum(router *gin.RouterGroup, conf *config.Config) {
 // PUT /api/v1/albums/:uuid
 func UpdateAlbum(router *gin.RouterGroup, conf *config.Config) {
 	router.PUT("/albums/:uuid", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		var f form.Album
 
 		if err := c.BindJSON(&f); err != nil {
@@ -125,16 +125,20 @@ func UpdateAlbum(router *gin.RouterGroup, conf *config.Config) {
 		}
 
 		id := c.Param("uuid")
-		q := query.New(conf.Db())
+		q := userQuery(c, conf)
 
-		m, err := q.AlbumByUUID(id)
+		m, ok := editableAlbum(c, q, id)
 
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusNotFound, ErrAlbumNotFound)
+		if !ok {
+			return
+		}
+
+		if !validAlbumFilter(c, f.AlbumFilter) {
 			return
 		}
 
 		m.Rename(f.AlbumName)
+		m.AlbumFilter = strings.TrimSpace(f.AlbumFilter)
 		conf.Db().Save(&m)
 
 		event.Publish("config.updated", event.Data(conf.ClientConfig()))
@@ -149,18 +153,12 @@ func UpdateAlbum(router *gin.RouterGroup, conf *config.Config) {
 // DELETE /api/v1/albums/:uuid
 func DeleteAlbum(router *gin.RouterGroup, conf *config.Config) {
 	router.DELETE("/albums/:uuid", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		id := c.Param("uuid")
-		q := query.New(conf.Db())
+		q := userQuery(c, conf)
 
-		m, err := q.AlbumByUUID(id)
+		m, ok := editableAlbum(c, q, id)
 
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusNotFound, ErrAlbumNotFound)
+		if !ok {
 			return
 		}
 
@@ -181,18 +179,12 @@ func DeleteAlbum(router *gin.RouterGroup, conf *config.Config) {
 //   uuid: string Album UUID
 func LikeAlbum(router *gin.RouterGroup, conf *config.Config) {
 	router.POST("/albums/:uuid/like", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		id := c.Param("uuid")
-		q := query.New(conf.Db())
+		q := userQuery(c, conf)
 
-		album, err := q.AlbumByUUID(id)
+		album, ok := editableAlbum(c, q, id)
 
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusNotFound, ErrAlbumNotFound)
+		if !ok {
 			return
 		}
 
@@ -212,17 +204,11 @@ func LikeAlbum(router *gin.RouterGroup, conf *config.Config) {
 //   uuid: string Album UUID
 func DislikeAlbum(router *gin.RouterGroup, conf *config.Config) {
 	router.DELETE("/albums/:uuid/like", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		id := c.Param("uuid")
-		q := query.New(conf.Db())
-		album, err := q.AlbumByUUID(id)
+		q := userQuery(c, conf)
+		album, ok := editableAlbum(c, q, id)
 
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusNotFound, ErrAlbumNotFound)
+		if !ok {
 			return
 		}
 
@@ -239,11 +225,6 @@ func DislikeAlbum(router *gin.RouterGroup, conf *config.Config) {
 // POST /api/v1/albums/:uuid/photos
 func AddPhotosToAlbum(router *gin.RouterGroup, conf *config.Config) {
 	router.POST("/albums/:uuid/photos", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		var f form.Selection
 
 		if err := c.BindJSON(&f); err != nil {
@@ -252,11 +233,13 @@ func AddPhotosToAlbum(router *gin.RouterGroup, conf *config.Config) {
 		}
 
 		uuid := c.Param("uuid")
-		q := query.New(conf.Db())
-		a, err := q.AlbumByUUID(uuid)
+		q := userQuery(c, conf)
+		a, ok := editableAlbum(c, q, uuid)
 
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusNotFound, ErrAlbumNotFound)
+		if !ok {
+			return
+		} else if a.IsSmart() {
+			c.AbortWithStatusJSON(http.StatusBadRequest, ErrSmartAlbum)
 			return
 		}
 
@@ -290,11 +273,6 @@ func AddPhotosToAlbum(router *gin.RouterGroup, conf *config.Config) {
 // DELETE /api/v1/albums/:uuid/photos
 func RemovePhotosFromAlbum(router *gin.RouterGroup, conf *config.Config) {
 	router.DELETE("/albums/:uuid/photos", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		var f form.Selection
 
 		if err := c.BindJSON(&f); err != nil {
@@ -308,11 +286,13 @@ func RemovePhotosFromAlbum(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		q := query.New(conf.Db())
-		a, err := q.AlbumByUUID(c.Param("uuid"))
+		q := userQuery(c, conf)
+		a, ok := editableAlbum(c, q, c.Param("uuid"))
 
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusNotFound, ErrAlbumNotFound)
+		if !ok {
+			return
+		} else if a.IsSmart() {
+			c.AbortWithStatusJSON(http.StatusBadRequest, ErrSmartAlbum)
 			return
 		}
 
@@ -333,10 +313,15 @@ func DownloadAlbum(router *gin.RouterGroup, conf *config.Config) {
 	router.GET("/albums/:uuid/download", func(c *gin.Context) {
 		start := time.Now()
 
-		q := query.New(conf.Db())
+		q := downloadQuery(c, conf)
+
+		if q == nil {
+			return
+		}
+
 		a, err := q.AlbumByUUID(c.Param("uuid"))
 
-		if err != nil {
+		if err != nil || !q.AlbumVisible(a.AlbumUUID) {
 			c.AbortWithStatusJSON(http.StatusNotFound, ErrAlbumNotFound)
 			return
 		}
@@ -381,6 +366,14 @@ func DownloadAlbum(router *gin.RouterGroup, conf *config.Config) {
 			fileAlias := f.DownloadFileName()
 
 			if fs.FileExists(fileName) {
+				if fileName, err = watermarkFile(c, conf, fileName, f.FileHash); err != nil {
+					log.Errorf("album: %s", err)
+					c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": txt.UcFirst("failed to create zip file")})
+					return
+				}
+
+				fileAlias = watermarkShareName(fileAlias, fileName)
+
 				if err := addFileToZip(zipWriter, fileName, fileAlias); err != nil {
 					log.Error(err)
 					c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": txt.UcFirst("failed to create zip file")})
@@ -407,6 +400,10 @@ func DownloadAlbum(router *gin.RouterGroup, conf *config.Config) {
 
 		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", zipBaseName))
 
+		if link := CurrentLink(c); link != nil {
+			linkAccessed(conf, link, true)
+		}
+
 		c.File(zipFileName)
 
 		if err := os.Remove(zipFileName); err != nil {
@@ -434,14 +431,33 @@ func AlbumThumbnail(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		q := query.New(conf.Db())
+		q, viewer := mediaQuery(c, conf)
 
+		if viewer == "" {
+			c.Data(http.StatusUnauthorized, "image/svg+xml", albumIconSvg)
+			return
+		}
+
+		if !q.AlbumVisible(uuid) {
+			c.Data(http.StatusNotFound, "image/svg+xml", albumIconSvg)
+			return
+		}
+
+		// Covers depend on the photos visible to the user and may be watermarked.
 		gc := conf.Cache()
-		cacheKey := fmt.Sprintf("album-thumbnail:%s:%s", uuid, typeName)
+		wm := watermarkTag(c, conf)
+		cacheKey := fmt.Sprintf("album-thumbnail:%s:%s:%s:%s", uuid, typeName, viewer, wm)
 
 		if cacheData, ok := gc.Get(cacheKey); ok {
 			log.Debugf("album: %s cache hit [%s]", cacheKey, time.Since(start))
-			c.Data(http.StatusOK, "image/jpeg", cacheData.([]byte))
+
+			cached := cacheData.(cachedThumb)
+
+			if notModified(c, cached.ETag, cacheControlCovers) {
+				return
+			}
+
+			serveData(c, "image/jpeg", cached.Data, cached.ModTime)
 			return
 		}
 
@@ -465,21 +481,44 @@ func AlbumThumbnail(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
+		tag := entityTag(f.FileHash, typeName, wm)
+
+		if notModified(c, tag, cacheControlCovers) {
+			return
+		}
+
 		// Use original file if thumb size exceeds limit, see https://github.com/photoprism/photoprism/issues/157
 		if thumbType.ExceedsLimit() && c.Query("download") == "" {
 			log.Debugf("album: using original, thumbnail size exceeds limit (width %d, height %d)", thumbType.Width, thumbType.Height)
 
+			if fileName, err = watermarkFile(c, conf, fileName, f.FileHash); err != nil {
+				log.Errorf("album: %s", err)
+				c.Data(http.StatusInternalServerError, "image/svg+xml", brokenIconSvg)
+				return
+			}
+
 			c.File(fileName)
 
 			return
 		}
 
 		if thumbnail, err := thumb.FromFile(fileName, f.FileHash, conf.ThumbnailsPath(), thumbType.Width, thumbType.Height, thumbType.Options...); err == nil {
+			if thumbnail, err = watermarkFile(c, conf, thumbnail, f.FileHash); err != nil {
+				log.Errorf("album: %s", err)
+				c.Data(http.StatusInternalServerError, "image/svg+xml", brokenIconSvg)
+				return
+			}
+
 			if c.Query("download") != "" {
 				c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", f.ShareFileName()))
 			}
 
 			thumbData, err := ioutil.ReadFile(thumbnail)
+			modTime := time.Now()
+
+			if info, err := os.Stat(thumbnail); err == nil {
+				modTime = info.ModTime()
+			}
 
 			if err != nil {
 				log.Errorf("album: %s", err)
@@ -487,14 +526,64 @@ func AlbumThumbnail(router *gin.RouterGroup, conf *config.Config) {
 				return
 			}
 
-			gc.Set(cacheKey, thumbData, time.Hour)
+			gc.Set(cacheKey, cachedThumb{ETag: tag, Data: thumbData, ModTime: modTime}, time.Hour)
 
 			log.Debugf("album: %s cached [%s]", cacheKey, time.Since(start))
 
-			c.Data(http.StatusOK, "image/jpeg", thumbData)
+			serveData(c, "image/jpeg", thumbData, modTime)
 		} else {
 			log.Errorf("album: %s", err)
 			c.Data(http.StatusBadRequest, "image/svg+xml", photoIconSvg)
 		}
 	})
 }
+
+// albumFilterOptions contains the search options supported by smart album filters in addition to search filters.
+var albumFilterOptions = map[string]bool{"before": true, "after": true, "order": true}
+
+// validAlbumFilter returns true if the smart album filter is empty or a valid search query,
+// otherwise a bad request error is sent.
+func validAlbumFilter(c *gin.Context, filter string) bool {
+	f := form.PhotoSearch{Query: filter}
+
+	err := f.ParseFilter()
+
+	if err == nil {
+		err = albumFilterError(filter)
+	}
+
+	if queryErr, ok := err.(*form.QueryError); ok {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(queryErr.Error()), "position": queryErr.Pos})
+		return false
+	} else if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+		return false
+	}
+
+	return true
+}
+
+// albumFilterError returns an error if a valid smart album filter contains search options that aren't supported.
+func albumFilterError(filter string) error {
+	expr, err := form.ParseExpr(filter)
+
+	if err != nil || expr == nil {
+		return err
+	}
+
+	terms, ok := expr.(form.And)
+
+	if !ok {
+		terms = form.And{expr}
+	}
+
+	for _, e := range terms {
+		if t, ok := e.(*form.Term); ok && t.Key != "" && !albumFilterOptions[t.Key] {
+			if _, ok := form.SearchFilters[t.Key]; !ok {
+				return &form.QueryError{Pos: t.Pos + 1, Message: fmt.Sprintf("%s can't be used in smart albums", t.Key)}
+			}
+		}
+	}
+
+	return nil
+}
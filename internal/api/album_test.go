@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/photoprism/photoprism/internal/form"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -138,3 +139,22 @@ func TestAlbumThumbnail(t *testing.T) {
 		assert.Equal(t, http.StatusOK, result.Code)
 	})
 }
+
+func TestAlbumFilterError(t *testing.T) {
+	t.Run("filters and options", func(t *testing.T) {
+		assert.Nil(t, albumFilterError("label:beach country:es before:2020-01-01 order:oldest"))
+	})
+	t.Run("empty", func(t *testing.T) {
+		assert.Nil(t, albumFilterError(""))
+	})
+	t.Run("unsupported option", func(t *testing.T) {
+		err := albumFilterError("label:beach count:5")
+
+		if queryErr, ok := err.(*form.QueryError); !ok {
+			t.Fatalf("query error expected: %v", err)
+		} else {
+			assert.Equal(t, 13, queryErr.Pos)
+			assert.Equal(t, "count can't be used in smart albums", queryErr.Message)
+		}
+	})
+}
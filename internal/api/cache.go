@@ -0,0 +1,78 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Media responses depend on the session, which decides what is visible and whether images are watermarked,
+// so they may only be stored by the browser. Shared caches like reverse proxies can't store them, but clients
+// revalidate with the entity tags and get 304 responses without body.
+const (
+	// Thumbnails never change for a given file hash and type.
+	cacheControlThumbs = "private, max-age=2592000"
+	// Originals should not be stored by shared caches.
+	cacheControlOriginals = "private, max-age=2592000"
+	// Album and label covers change when their photos change.
+	cacheControlCovers = "private, max-age=3600"
+	// The preview image changes daily and is watermarked for visitors without session.
+	cacheControlPreview = "private, max-age=3600"
+	// Facet counts change when photos are indexed or edited.
+	cacheControlFacets = "private, max-age=300"
+)
+
+// cachedThumb represents a thumbnail kept in the in-memory cache.
+type cachedThumb struct {
+	ETag    string
+	Data    []byte
+	ModTime time.Time
+}
+
+// entityTag returns a strong entity tag for a file hash and optional variant names like the thumbnail type.
+func entityTag(fileHash string, variants ...string) string {
+	for _, v := range variants {
+		if v != "" {
+			fileHash += "-" + v
+		}
+	}
+
+	return fmt.Sprintf("%q", fileHash)
+}
+
+// etagMatch returns true if the If-None-Match header value matches the entity tag, using weak comparison.
+func etagMatch(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// notModified sets the ETag and Cache-Control headers and returns true if the client copy is still fresh,
+// in which case a 304 Not Modified response has been sent already.
+func notModified(c *gin.Context, etag, cacheControl string) bool {
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", cacheControl)
+
+	if match := c.GetHeader("If-None-Match"); match != "" && etagMatch(match, etag) {
+		c.AbortWithStatus(http.StatusNotModified)
+		return true
+	}
+
+	return false
+}
+
+// serveData sends in-memory content with support for conditional and range requests.
+func serveData(c *gin.Context, contentType string, data []byte, modTime time.Time) {
+	c.Header("Content-Type", contentType)
+	http.ServeContent(c.Writer, c.Request, "", modTime, bytes.NewReader(data))
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntityTag(t *testing.T) {
+	assert.Equal(t, `"abc"`, entityTag("abc"))
+	assert.Equal(t, `"abc-tile_224-webp"`, entityTag("abc", "tile_224", "webp", ""))
+}
+
+func TestEtagMatch(t *testing.T) {
+	assert.True(t, etagMatch(`"abc"`, `"abc"`))
+	assert.True(t, etagMatch(`"xyz", W/"abc"`, `"abc"`))
+	assert.True(t, etagMatch(`*`, `"abc"`))
+	assert.False(t, etagMatch(`"abc-jpg"`, `"abc"`))
+}
+
+func TestNotModified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	app := gin.New()
+	app.GET("/media", func(c *gin.Context) {
+		if notModified(c, entityTag("abc", "fit_720"), cacheControlThumbs) {
+			return
+		}
+
+		serveData(c, "image/jpeg", []byte("0123456789"), time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC))
+	})
+
+	t.Run("full", func(t *testing.T) {
+		result := PerformRequest(app, "GET", "/media")
+
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Equal(t, `"abc-fit_720"`, result.Header().Get("ETag"))
+		assert.Equal(t, cacheControlThumbs, result.Header().Get("Cache-Control"))
+		assert.NotEmpty(t, result.Header().Get("Last-Modified"))
+		assert.Equal(t, "0123456789", result.Body.String())
+	})
+	t.Run("not modified", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/media", nil)
+		req.Header.Set("If-None-Match", `"abc-fit_720"`)
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+	t.Run("range", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/media", nil)
+		req.Header.Set("Range", "bytes=2-5")
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusPartialContent, w.Code)
+		assert.Equal(t, "2345", w.Body.String())
+		assert.Equal(t, "bytes 2-5/10", w.Header().Get("Content-Range"))
+	})
+}
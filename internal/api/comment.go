@@ -0,0 +1,319 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// GET /api/v1/photos/:uuid/comments
+//
+// Parameters:
+//   uuid: string PhotoUUID as returned by the API
+func GetPhotoComments(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/photos/:uuid/comments", func(c *gin.Context) {
+		q := userQuery(c, conf)
+		m, err := q.PhotoByUUID(c.Param("uuid"))
+
+		if err != nil || !q.PhotoVisible(m.PhotoUUID) {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrPhotoNotFound)
+			return
+		}
+
+		f := form.CommentSearch{Photo: m.PhotoUUID, Hidden: canEdit(CurrentUser(c), m.OwnerUUID)}
+
+		sendComments(c, q, f)
+	})
+}
+
+// GET /api/v1/albums/:uuid/comments
+//
+// Parameters:
+//   uuid: string Album UUID
+func GetAlbumComments(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/albums/:uuid/comments", func(c *gin.Context) {
+		q := userQuery(c, conf)
+		m, err := q.AlbumByUUID(c.Param("uuid"))
+
+		if err != nil || !q.AlbumVisible(m.AlbumUUID) {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrAlbumNotFound)
+			return
+		}
+
+		f := form.CommentSearch{Album: m.AlbumUUID, Hidden: canEdit(CurrentUser(c), m.OwnerUUID)}
+
+		sendComments(c, q, f)
+	})
+}
+
+// POST /api/v1/photos/:uuid/comments
+//
+// Parameters:
+//   uuid: string PhotoUUID as returned by the API
+func CreatePhotoComment(router *gin.RouterGroup, conf *config.Config) {
+	router.POST("/photos/:uuid/comments", func(c *gin.Context) {
+		q := userQuery(c, conf)
+		m, err := q.PhotoByUUID(c.Param("uuid"))
+
+		if err != nil || !q.PhotoVisible(m.PhotoUUID) {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrPhotoNotFound)
+			return
+		}
+
+		user := CurrentUser(c)
+
+		createComment(c, conf, entity.Comment{PhotoUUID: m.PhotoUUID, UserUUID: user.UserUUID, AuthorName: user.FullName()})
+	})
+}
+
+// POST /api/v1/albums/:uuid/comments
+//
+// Parameters:
+//   uuid: string Album UUID
+func CreateAlbumComment(router *gin.RouterGroup, conf *config.Config) {
+	router.POST("/albums/:uuid/comments", func(c *gin.Context) {
+		q := userQuery(c, conf)
+		m, err := q.AlbumByUUID(c.Param("uuid"))
+
+		if err != nil || !q.AlbumVisible(m.AlbumUUID) {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrAlbumNotFound)
+			return
+		}
+
+		user := CurrentUser(c)
+
+		createComment(c, conf, entity.Comment{AlbumUUID: m.AlbumUUID, UserUUID: user.UserUUID, AuthorName: user.FullName()})
+	})
+}
+
+// PUT /api/v1/comments/:uuid
+//
+// Hides or shows a comment, only owners of the photo or album and admins may moderate comments.
+//
+// Parameters:
+//   uuid: string Comment UUID
+func UpdateComment(router *gin.RouterGroup, conf *config.Config) {
+	router.PUT("/comments/:uuid", func(c *gin.Context) {
+		var f form.CommentModeration
+
+		if err := c.BindJSON(&f); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		m, ok := moderatedComment(c, conf)
+
+		if !ok {
+			return
+		}
+
+		if err := conf.Db().Model(&m).Update("comment_hidden", f.Hidden).Error; err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		publishComment("updated", m)
+
+		c.JSON(http.StatusOK, m)
+	})
+}
+
+// DELETE /api/v1/comments/:uuid
+//
+// Parameters:
+//   uuid: string Comment UUID
+func DeleteComment(router *gin.RouterGroup, conf *config.Config) {
+	router.DELETE("/comments/:uuid", func(c *gin.Context) {
+		m, ok := moderatedComment(c, conf)
+
+		if !ok {
+			return
+		}
+
+		if err := conf.Db().Delete(&m).Error; err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		publishComment("deleted", m)
+
+		c.JSON(http.StatusOK, m)
+	})
+}
+
+// GET /api/v1/shared/comments
+//
+// Query:
+//   photo: string PhotoUUID, returns comments on the shared album if empty
+func GetSharedComments(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/shared/comments", func(c *gin.Context) {
+		var f form.CommentSearch
+
+		if err := c.MustBindWith(&f, binding.Form); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		link := CurrentLink(c)
+		q := query.New(conf.Db())
+		photoUUID, albumUUID, ok := visitorCommentTarget(q, link, f.Photo)
+
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrPhotoNotFound)
+			return
+		}
+
+		sendComments(c, q, form.CommentSearch{Photo: photoUUID, Album: albumUUID, Count: f.Count, Offset: f.Offset})
+	})
+}
+
+// POST /api/v1/shared/comments
+//
+// Adds a comment to the shared album or photo, or a photo in the shared album, label or folder.
+// Requires a link that allows comments.
+func CreateSharedComment(router *gin.RouterGroup, conf *config.Config) {
+	router.POST("/shared/comments", func(c *gin.Context) {
+		var f form.Comment
+
+		if err := c.ShouldBindBodyWith(&f, binding.JSON); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		link := CurrentLink(c)
+
+		if !link.CanComment {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrForbidden)
+			return
+		}
+
+		photoUUID, albumUUID, ok := visitorCommentTarget(query.New(conf.Db()), link, f.Photo)
+
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrPhotoNotFound)
+			return
+		}
+
+		createComment(c, conf, entity.Comment{PhotoUUID: photoUUID, AlbumUUID: albumUUID, LinkToken: link.LinkToken, AuthorName: f.Author})
+	})
+}
+
+// sendComments responds with the comments matching the search form.
+func sendComments(c *gin.Context, q *query.Query, f form.CommentSearch) {
+	results, err := q.Comments(f)
+
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// createComment saves a new comment with the text of the request body and publishes an event.
+func createComment(c *gin.Context, conf *config.Config, target entity.Comment) {
+	var f form.Comment
+
+	// The body may already have been read by the caller.
+	if err := c.ShouldBindBodyWith(&f, binding.JSON); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+		return
+	}
+
+	m, err := entity.NewComment(target.AuthorName, f.Text)
+
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+		return
+	}
+
+	m.PhotoUUID = target.PhotoUUID
+	m.AlbumUUID = target.AlbumUUID
+	m.UserUUID = target.UserUUID
+	m.LinkToken = target.LinkToken
+
+	if err := conf.Db().Create(m).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": txt.UcFirst(err.Error())})
+		return
+	}
+
+	publishComment("created", *m)
+
+	c.JSON(http.StatusOK, m)
+}
+
+// publishComment notifies clients that a comment changed. Text and author aren't sent, as clients may not be
+// allowed to see the comment or the photo or album, so they must fetch comments again if needed.
+func publishComment(ev string, m entity.Comment) {
+	event.Publish("comments."+ev, event.Data{
+		"uuid":  m.CommentUUID,
+		"photo": m.PhotoUUID,
+		"album": m.AlbumUUID,
+	})
+}
+
+// moderatedComment returns the comment of the UUID in the request path if the current user may moderate it,
+// or aborts the request.
+func moderatedComment(c *gin.Context, conf *config.Config) (m entity.Comment, ok bool) {
+	q := query.New(conf.Db())
+	m, err := q.CommentByUUID(c.Param("uuid"))
+
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, ErrCommentNotFound)
+		return m, false
+	}
+
+	// Archived photos and deleted albums keep their owner.
+	db := conf.Db().Unscoped()
+
+	var ownerUUID string
+
+	if m.PhotoUUID != "" {
+		var p entity.Photo
+		err = db.Where("photo_uuid = ?", m.PhotoUUID).First(&p).Error
+		ownerUUID = p.OwnerUUID
+	} else {
+		var a entity.Album
+		err = db.Where("album_uuid = ?", m.AlbumUUID).First(&a).Error
+		ownerUUID = a.OwnerUUID
+	}
+
+	// Only admins may moderate comments on items that no longer exist.
+	if err != nil {
+		ownerUUID = entity.Admin.UserUUID
+	}
+
+	if !canEdit(CurrentUser(c), ownerUUID) {
+		c.AbortWithStatusJSON(http.StatusForbidden, ErrForbidden)
+		return m, false
+	}
+
+	return m, true
+}
+
+// visitorCommentTarget returns the photo or album a share link visitor may comment on,
+// without photo this is the shared album or photo itself.
+func visitorCommentTarget(q *query.Query, link *entity.Link, photoUUID string) (photo, album string, ok bool) {
+	shared := q.WithShare(link.ShareUUID)
+
+	if photoUUID != "" {
+		return photoUUID, "", shared.PhotoVisible(photoUUID)
+	}
+
+	if shared.AlbumVisible(link.ShareUUID) {
+		return "", link.ShareUUID, true
+	}
+
+	if shared.PhotoVisible(link.ShareUUID) {
+		return link.ShareUUID, "", true
+	}
+
+	return "", "", false
+}
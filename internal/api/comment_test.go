@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPhotoComments(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		GetPhotoComments(router, ctx)
+		result := PerformRequest(app, "GET", "/api/v1/photos/xxx/comments")
+		assert.Equal(t, http.StatusNotFound, result.Code)
+	})
+}
+
+func TestCreateAlbumComment(t *testing.T) {
+	t.Run("successful request", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		CreateAlbumComment(router.Group("", Auth(ctx, acl.RoleGuest, acl.ScopePhotosWrite)), ctx)
+		result := PerformRequestWithBody(app, "POST", "/api/v1/albums/3/comments", `{"text": "Looks great!"}`)
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Contains(t, result.Body.String(), "Looks great!")
+	})
+	t.Run("empty text", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		CreateAlbumComment(router.Group("", Auth(ctx, acl.RoleGuest, acl.ScopePhotosWrite)), ctx)
+		result := PerformRequestWithBody(app, "POST", "/api/v1/albums/3/comments", `{"text": ""}`)
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+}
+
+func TestDeleteComment(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		DeleteComment(router.Group("", Auth(ctx, acl.RoleGuest, acl.ScopePhotosWrite)), ctx)
+		result := PerformRequest(app, "DELETE", "/api/v1/comments/c000000000000000")
+		assert.Equal(t, http.StatusNotFound, result.Code)
+	})
+}
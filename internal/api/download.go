@@ -2,11 +2,12 @@ package api
 
 import (
 	"fmt"
+	"net/http"
 	"path"
 
 	"github.com/photoprism/photoprism/internal/config"
-	"github.com/photoprism/photoprism/internal/query"
 	"github.com/photoprism/photoprism/pkg/fs"
+	"github.com/photoprism/photoprism/pkg/txt"
 
 	"github.com/gin-gonic/gin"
 )
@@ -23,7 +24,12 @@ func GetDownload(router *gin.RouterGroup, conf *config.Config) {
 	router.GET("/download/:hash", func(c *gin.Context) {
 		fileHash := c.Param("hash")
 
-		q := query.New(conf.Db())
+		q := downloadQuery(c, conf)
+
+		if q == nil {
+			return
+		}
+
 		f, err := q.FileByHash(fileHash)
 
 		if err != nil {
@@ -31,6 +37,11 @@ func GetDownload(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
+		if !q.PhotoVisible(f.PhotoUUID) {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrPhotoNotFound)
+			return
+		}
+
 		fileName := path.Join(conf.OriginalsPath(), f.FileName)
 
 		if !fs.FileExists(fileName) {
@@ -43,10 +54,24 @@ func GetDownload(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		downloadFileName := f.ShareFileName()
+		if notModified(c, entityTag(f.FileHash, watermarkTag(c, conf)), cacheControlOriginals) {
+			return
+		}
+
+		if fileName, err = watermarkFile(c, conf, fileName, f.FileHash); err != nil {
+			log.Errorf("download: %s", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		downloadFileName := watermarkShareName(f.ShareFileName(), fileName)
 
 		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", downloadFileName))
 
+		if link := CurrentLink(c); link != nil {
+			linkAccessed(conf, link, true)
+		}
+
 		c.File(fileName)
 	})
 }
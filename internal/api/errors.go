@@ -11,11 +11,20 @@ import (
 var (
 	ErrUnauthorized     = gin.H{"code": http.StatusUnauthorized, "error": txt.UcFirst(config.ErrUnauthorized.Error())}
 	ErrReadOnly         = gin.H{"code": http.StatusForbidden, "error": txt.UcFirst(config.ErrReadOnly.Error())}
+	ErrForbidden        = gin.H{"code": http.StatusForbidden, "error": "Permission denied"}
 	ErrUploadNSFW       = gin.H{"code": http.StatusForbidden, "error": txt.UcFirst(config.ErrUploadNSFW.Error())}
 	ErrAccountNotFound  = gin.H{"code": http.StatusNotFound, "error": "Account not found"}
 	ErrConnectionFailed = gin.H{"code": http.StatusConflict, "error": "Failed to connect"}
 	ErrAlbumNotFound    = gin.H{"code": http.StatusNotFound, "error": "Album not found"}
 	ErrPhotoNotFound    = gin.H{"code": http.StatusNotFound, "error": "Photo not found"}
 	ErrLabelNotFound    = gin.H{"code": http.StatusNotFound, "error": "Label not found"}
+	ErrFolderNotFound   = gin.H{"code": http.StatusNotFound, "error": "Folder not found"}
+	ErrSessionNotFound  = gin.H{"code": http.StatusNotFound, "error": "Session not found"}
+	ErrTokenNotFound    = gin.H{"code": http.StatusNotFound, "error": "Token not found"}
+	ErrLinkNotFound     = gin.H{"code": http.StatusNotFound, "error": "Link not found"}
+	ErrLinkExpired      = gin.H{"code": http.StatusGone, "error": "Link expired"}
+	ErrInvalidPassword  = gin.H{"code": http.StatusUnauthorized, "error": "Invalid password"}
+	ErrCommentNotFound  = gin.H{"code": http.StatusNotFound, "error": "Comment not found"}
+	ErrSmartAlbum       = gin.H{"code": http.StatusBadRequest, "error": "Photos can't be added to or removed from smart albums"}
 	ErrUnexpectedError  = gin.H{"code": http.StatusInternalServerError, "error": "Unexpected error"}
 )
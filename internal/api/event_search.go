@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// GET /api/v1/events
+//
+// Query:
+//   q:      string Event name
+//   type:   string Event type, either "event" or "trip"
+//   year:   int    Year the event began
+//   count:  int    Max result count (required)
+//   offset: int    Result offset
+func GetEvents(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/events", func(c *gin.Context) {
+		var f form.EventSearch
+
+		if err := c.MustBindWith(&f, binding.Form); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		result, err := userQuery(c, conf).Events(f)
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		c.Header("X-Result-Count", strconv.Itoa(f.Count))
+		c.Header("X-Result-Offset", strconv.Itoa(f.Offset))
+
+		c.JSON(http.StatusOK, result)
+	})
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEvents(t *testing.T) {
+	t.Run("successful request", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+
+		GetEvents(router, conf)
+
+		result := PerformRequest(app, "GET", "/api/v1/events?count=10")
+		assert.Equal(t, http.StatusOK, result.Code)
+	})
+	t.Run("count missing", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+
+		GetEvents(router, conf)
+
+		result := PerformRequest(app, "GET", "/api/v1/events")
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+}
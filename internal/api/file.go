@@ -6,7 +6,6 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/photoprism/photoprism/internal/config"
 	"github.com/photoprism/photoprism/internal/event"
-	"github.com/photoprism/photoprism/internal/query"
 	"github.com/photoprism/photoprism/pkg/txt"
 )
 
@@ -16,19 +15,19 @@ import (
 //   hash: string SHA-1 hash of the file
 func GetFile(router *gin.RouterGroup, conf *config.Config) {
 	router.GET("/files/:hash", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
-		q := query.New(conf.Db())
+		q := userQuery(c, conf)
 		p, err := q.FileByHash(c.Param("hash"))
 
-		if err != nil {
+		if err != nil || !q.PhotoVisible(p.PhotoUUID) {
 			c.AbortWithStatusJSON(http.StatusNotFound, ErrPhotoNotFound)
 			return
 		}
 
+		// Share links must only be visible to users who may change them.
+		if p.Photo == nil || !canEdit(CurrentUser(c), p.Photo.OwnerUUID) {
+			p.Links = nil
+		}
+
 		c.JSON(http.StatusOK, p)
 	})
 }
@@ -39,13 +38,8 @@ func GetFile(router *gin.RouterGroup, conf *config.Config) {
 //   hash: string SHA-1 hash of the file
 func LinkFile(router *gin.RouterGroup, conf *config.Config) {
 	router.POST("/files/:hash/link", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		db := conf.Db()
-		q := query.New(db)
+		q := userQuery(c, conf)
 
 		m, err := q.FileByUUID(c.Param("hash"))
 
@@ -54,6 +48,10 @@ func LinkFile(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
+		if _, ok := editablePhoto(c, q, m.PhotoUUID); !ok {
+			return
+		}
+
 		if link, err := newLink(c); err != nil {
 			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
 			return
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// GET /api/v1/folders
+//
+// Parameters:
+//   path: string Folder path relative to the originals directory, default is the root folder
+//   count: int Max number of subfolders, default is all
+//   offset: int Number of subfolders to skip
+//
+// Photos in a folder can be found with the path and recursive parameters of /api/v1/photos.
+func GetFolders(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/folders", func(c *gin.Context) {
+		var f form.FolderSearch
+
+		q := userQuery(c, conf)
+		err := c.MustBindWith(&f, binding.Form)
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		result, err := q.Folders(f)
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		c.Header("X-Result-Count", strconv.Itoa(f.Count))
+		c.Header("X-Result-Offset", strconv.Itoa(f.Offset))
+
+		c.JSON(http.StatusOK, result)
+	})
+}
+
+// GET /api/v1/folders/:uuid
+func GetFolder(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/folders/:uuid", func(c *gin.Context) {
+		q := query.New(conf.Db())
+		m, err := q.FolderByUUID(c.Param("uuid"))
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrFolderNotFound)
+			return
+		}
+
+		// Share links must only be visible to users who may change them, folders can only be shared by admins.
+		if !canEdit(CurrentUser(c), entity.Admin.UserUUID) {
+			m.Links = nil
+		}
+
+		c.JSON(http.StatusOK, m)
+	})
+}
+
+// POST /api/v1/folders
+//
+// Returns the folder with the given path, so that it can be updated and shared.
+// Title and description are saved if not empty.
+func CreateFolder(router *gin.RouterGroup, conf *config.Config) {
+	router.POST("/folders", func(c *gin.Context) {
+		var f form.Folder
+
+		if err := c.BindJSON(&f); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		m := entity.NewFolder(f.FolderPath)
+
+		if err := m.FirstOrCreate(conf.Db()); err != nil {
+			log.Errorf("folder: %s", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrUnexpectedError)
+			return
+		}
+
+		if f.FolderTitle != "" || f.FolderDescription != "" {
+			m.SetTitle(f.FolderTitle, f.FolderDescription)
+			conf.Db().Save(m)
+		}
+
+		c.JSON(http.StatusOK, m)
+	})
+}
+
+// PUT /api/v1/folders/:uuid
+func UpdateFolder(router *gin.RouterGroup, conf *config.Config) {
+	router.PUT("/folders/:uuid", func(c *gin.Context) {
+		var f form.Folder
+
+		if err := c.BindJSON(&f); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		q := query.New(conf.Db())
+		m, err := q.FolderByUUID(c.Param("uuid"))
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrFolderNotFound)
+			return
+		}
+
+		m.SetTitle(f.FolderTitle, f.FolderDescription)
+		conf.Db().Save(&m)
+
+		event.Success("folder saved")
+
+		c.JSON(http.StatusOK, m)
+	})
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetFolders(t *testing.T) {
+	t.Run("root", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		GetFolders(router, ctx)
+		result := PerformRequest(app, "GET", "/api/v1/folders")
+		assert.Equal(t, http.StatusOK, result.Code)
+	})
+	t.Run("subfolder", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		GetFolders(router, ctx)
+		result := PerformRequest(app, "GET", "/api/v1/folders?path=2790/02&count=10")
+		assert.Equal(t, http.StatusOK, result.Code)
+	})
+}
+
+func TestGetFolder(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		GetFolder(router, ctx)
+		result := PerformRequest(app, "GET", "/api/v1/folders/xxx")
+		assert.Equal(t, http.StatusNotFound, result.Code)
+	})
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/photoprism/photoprism/internal/config"
 	"github.com/photoprism/photoprism/internal/query"
@@ -15,16 +16,25 @@ import (
 )
 
 // GET /api/v1/geo
+//
+// Returns a GeoJSON FeatureCollection.
+//
+// Query:
+//   q:       string Query string
+//   s2:      string S2 cell token
+//   olc:     string Open location code
+//   lat:     float  Latitude of the radius search center
+//   lng:     float  Longitude of the radius search center
+//   dist:    int    Radius in km (default 20), results are sorted by distance
+//   bbox:    string Bounding box "west,south,east,north"
+//   polygon: string Polygon "lng1,lat1,lng2,lat2,lng3,lat3,..."
+//   before:  date   Find photos taken before (format: "2006-01-02")
+//   after:   date   Find photos taken after (format: "2006-01-02")
 func GetGeo(router *gin.RouterGroup, conf *config.Config) {
 	router.GET("/geo", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		var f form.GeoSearch
 
-		q := query.New(conf.Db())
+		q := userQuery(c, conf)
 		err := c.MustBindWith(&f, binding.Form)
 
 		if err != nil {
@@ -39,50 +49,159 @@ func GetGeo(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		fc := geojson.NewFeatureCollection()
+		fc := photoFeatures(photos)
 
-		bbox := make([]float64, 4)
+		sendFeatures(c, fc)
+	})
+}
+
+// GET /api/v1/geo/clusters
+//
+// Returns a GeoJSON FeatureCollection with one feature per S2 cell, features have a Cluster, CellID,
+// Count and FileHash property. Individual photos are returned like GET /api/v1/geo if their number
+// doesn't exceed the threshold or the max zoom level is reached.
+//
+// Query:
+//   zoom:    int    Map zoom level (0-22)
+//   bbox:    string Viewport "west,south,east,north"
+//   Other parameters are the same as for GET /api/v1/geo
+func GetGeoClusters(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/geo/clusters", func(c *gin.Context) {
+		var f form.GeoSearch
 
-		bboxMin := func(pos int, val float64) {
-			if bbox[pos] == 0.0 || bbox[pos] > val {
-				bbox[pos] = val
-			}
+		q := userQuery(c, conf)
+		err := c.MustBindWith(&f, binding.Form)
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
 		}
 
-		bboxMax := func(pos int, val float64) {
-			if bbox[pos] == 0.0 || bbox[pos] < val {
-				bbox[pos] = val
-			}
+		zoom, _ := strconv.Atoi(c.Query("zoom"))
+		level := query.GeoClusterLevel(zoom)
+
+		clusters, err := q.GeoClusters(f, level)
+
+		if err != nil {
+			c.AbortWithStatusJSON(400, gin.H{"error": txt.UcFirst(err.Error())})
+			return
 		}
 
-		for _, p := range photos {
-			bboxMin(0, p.PhotoLng)
-			bboxMin(1, p.PhotoLat)
-			bboxMax(2, p.PhotoLng)
-			bboxMax(3, p.PhotoLat)
-
-			feat := geojson.NewPointFeature([]float64{p.PhotoLng, p.PhotoLat})
-			feat.ID = p.ID
-			feat.Properties = gin.H{
-				"PhotoUUID":  p.PhotoUUID,
-				"PhotoTitle": p.PhotoTitle,
-				"FileHash":   p.FileHash,
-				"FileWidth":  p.FileWidth,
-				"FileHeight": p.FileHeight,
-				"TakenAt":    p.TakenAt,
-			}
-			fc.AddFeature(feat)
+		total := 0
+
+		for _, cluster := range clusters {
+			total += cluster.Count
 		}
 
-		fc.BoundingBox = bbox
+		if total > GeoPointsThreshold && level < query.MaxClusterLevel {
+			sendFeatures(c, clusterFeatures(clusters))
+			return
+		}
 
-		resp, err := fc.MarshalJSON()
+		photos, err := q.Geo(f)
 
 		if err != nil {
 			c.AbortWithStatusJSON(400, gin.H{"error": txt.UcFirst(err.Error())})
 			return
 		}
 
-		c.Data(http.StatusOK, "application/json", resp)
+		sendFeatures(c, photoFeatures(photos))
 	})
 }
+
+// GeoPointsThreshold is the max number of photos that are returned as individual points instead of clusters.
+var GeoPointsThreshold = 500
+
+// geoBBox represents the bounding box of features.
+type geoBBox []float64
+
+// extend adds a position to the bounding box.
+func (bbox geoBBox) extend(lat, lng float64) {
+	bboxMin := func(pos int, val float64) {
+		if bbox[pos] == 0.0 || bbox[pos] > val {
+			bbox[pos] = val
+		}
+	}
+
+	bboxMax := func(pos int, val float64) {
+		if bbox[pos] == 0.0 || bbox[pos] < val {
+			bbox[pos] = val
+		}
+	}
+
+	bboxMin(0, lng)
+	bboxMin(1, lat)
+	bboxMax(2, lng)
+	bboxMax(3, lat)
+}
+
+// photoFeatures returns a feature collection with one point per photo.
+func photoFeatures(photos []query.GeoResult) *geojson.FeatureCollection {
+	fc := geojson.NewFeatureCollection()
+
+	bbox := make(geoBBox, 4)
+
+	for _, p := range photos {
+		bbox.extend(p.PhotoLat, p.PhotoLng)
+
+		feat := geojson.NewPointFeature([]float64{p.PhotoLng, p.PhotoLat})
+		feat.ID = p.ID
+		feat.Properties = gin.H{
+			"PhotoUUID":    p.PhotoUUID,
+			"PhotoTitle":   p.PhotoTitle,
+			"FileHash":     p.FileHash,
+			"FileWidth":    p.FileWidth,
+			"FileHeight":   p.FileHeight,
+			"FileBlurHash": p.FileBlurHash,
+			"TakenAt":      p.TakenAt,
+		}
+
+		if p.Distance > 0 {
+			feat.Properties["Distance"] = p.Distance
+		}
+
+		fc.AddFeature(feat)
+	}
+
+	fc.BoundingBox = bbox
+
+	return fc
+}
+
+// clusterFeatures returns a feature collection with one point per cluster.
+func clusterFeatures(clusters []query.GeoCluster) *geojson.FeatureCollection {
+	fc := geojson.NewFeatureCollection()
+
+	bbox := make(geoBBox, 4)
+
+	for _, cluster := range clusters {
+		bbox.extend(cluster.Lat, cluster.Lng)
+
+		feat := geojson.NewPointFeature([]float64{cluster.Lng, cluster.Lat})
+		feat.ID = cluster.CellID
+		feat.Properties = gin.H{
+			"Cluster":  true,
+			"CellID":   cluster.CellID,
+			"Count":    cluster.Count,
+			"FileHash": cluster.FileHash,
+		}
+
+		fc.AddFeature(feat)
+	}
+
+	fc.BoundingBox = bbox
+
+	return fc
+}
+
+// sendFeatures sends a feature collection as JSON.
+func sendFeatures(c *gin.Context, fc *geojson.FeatureCollection) {
+	resp, err := fc.MarshalJSON()
+
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": txt.UcFirst(err.Error())})
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json", resp)
+}
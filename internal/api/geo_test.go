@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/photoprism/photoprism/internal/query"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -17,3 +18,44 @@ func TestGetGeo(t *testing.T) {
 		assert.Equal(t, http.StatusOK, result.Code)
 	})
 }
+
+func TestGetGeoClusters(t *testing.T) {
+	t.Run("zoom 3", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+
+		GetGeoClusters(router, conf)
+
+		result := PerformRequest(app, "GET", "/api/v1/geo/clusters?zoom=3&bbox=-180,-90,180,90")
+		assert.Equal(t, http.StatusOK, result.Code)
+	})
+	t.Run("invalid bbox", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+
+		GetGeoClusters(router, conf)
+
+		result := PerformRequest(app, "GET", "/api/v1/geo/clusters?zoom=3&bbox=1,2,3")
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+}
+
+func TestPhotoFeatures(t *testing.T) {
+	fc := photoFeatures([]query.GeoResult{
+		{ID: "1", PhotoLat: 52.5, PhotoLng: 13.4, PhotoUUID: "pt9jtdre2lvl0yh7", FileHash: "abc", FileBlurHash: "LEHV6nWB2yk8pyo0adR*.7kCMdnj"},
+	})
+
+	assert.Len(t, fc.Features, 1)
+	assert.Equal(t, "abc", fc.Features[0].Properties["FileHash"])
+	assert.Equal(t, "LEHV6nWB2yk8pyo0adR*.7kCMdnj", fc.Features[0].Properties["FileBlurHash"])
+}
+
+func TestClusterFeatures(t *testing.T) {
+	fc := clusterFeatures([]query.GeoCluster{
+		{CellID: "4799c", Lat: 52.5, Lng: 13.4, Count: 5, FileHash: "abc"},
+		{CellID: "47a1", Lat: 48.8, Lng: 2.3, Count: 2, FileHash: "def"},
+	})
+
+	assert.Len(t, fc.Features, 2)
+	assert.Equal(t, []float64{2.3, 48.8, 13.4, 52.5}, fc.BoundingBox)
+	assert.Equal(t, 5, fc.Features[0].Properties["Count"])
+	assert.Equal(t, true, fc.Features[0].Properties["Cluster"])
+}
@@ -26,11 +26,6 @@ func StartImport(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		start := time.Now()
 
 		var f form.ImportOptions
@@ -63,6 +58,9 @@ func StartImport(router *gin.RouterGroup, conf *config.Config) {
 			opt = photoprism.ImportOptionsCopy(path)
 		}
 
+		// Imported photos are owned by the user who started the import.
+		opt.OwnerUUID = currentUserUUID(c)
+
 		imp.Start(opt)
 
 		if subPath != "" && path != conf.ImportPath() && fs.IsEmpty(path) {
@@ -87,11 +85,6 @@ func StartImport(router *gin.RouterGroup, conf *config.Config) {
 // DELETE /api/v1/import
 func CancelImport(router *gin.RouterGroup, conf *config.Config) {
 	router.DELETE("/import", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		imp := service.Import()
 
 		imp.Cancel()
@@ -18,11 +18,6 @@ import (
 // POST /api/v1/index
 func StartIndexing(router *gin.RouterGroup, conf *config.Config) {
 	router.POST("/index", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		start := time.Now()
 
 		var f form.IndexOptions
@@ -81,11 +76,6 @@ func StartIndexing(router *gin.RouterGroup, conf *config.Config) {
 // DELETE /api/v1/index
 func CancelIndexing(router *gin.RouterGroup, conf *config.Config) {
 	router.DELETE("/index", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		ind := service.Index()
 
 		ind.Cancel()
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"os"
 	"path"
 	"strconv"
 	"time"
@@ -22,11 +23,6 @@ import (
 // GET /api/v1/labels
 func GetLabels(router *gin.RouterGroup, conf *config.Config) {
 	router.GET("/labels", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		var f form.LabelSearch
 
 		q := query.New(conf.Db())
@@ -53,11 +49,6 @@ func GetLabels(router *gin.RouterGroup, conf *config.Config) {
 // PUT /api/v1/labels/:uuid
 func UpdateLabel(router *gin.RouterGroup, conf *config.Config) {
 	router.PUT("/labels/:uuid", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		var f form.Label
 
 		if err := c.BindJSON(&f); err != nil {
@@ -66,12 +57,11 @@ func UpdateLabel(router *gin.RouterGroup, conf *config.Config) {
 		}
 
 		id := c.Param("uuid")
-		q := query.New(conf.Db())
+		q := userQuery(c, conf)
 
-		m, err := q.LabelByUUID(id)
+		m, ok := editableLabel(c, q, id)
 
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusNotFound, ErrLabelNotFound)
+		if !ok {
 			return
 		}
 
@@ -92,18 +82,12 @@ func UpdateLabel(router *gin.RouterGroup, conf *config.Config) {
 //   uuid: string Label UUID
 func LikeLabel(router *gin.RouterGroup, conf *config.Config) {
 	router.POST("/labels/:uuid/like", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		id := c.Param("uuid")
-		q := query.New(conf.Db())
+		q := userQuery(c, conf)
 
-		label, err := q.LabelByUUID(id)
+		label, ok := editableLabel(c, q, id)
 
-		if err != nil {
-			c.AbortWithStatusJSON(404, gin.H{"error": txt.UcFirst(err.Error())})
+		if !ok {
 			return
 		}
 
@@ -128,18 +112,12 @@ func LikeLabel(router *gin.RouterGroup, conf *config.Config) {
 //   uuid: string Label UUID
 func DislikeLabel(router *gin.RouterGroup, conf *config.Config) {
 	router.DELETE("/labels/:uuid/like", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		id := c.Param("uuid")
-		q := query.New(conf.Db())
+		q := userQuery(c, conf)
 
-		label, err := q.LabelByUUID(id)
+		label, ok := editableLabel(c, q, id)
 
-		if err != nil {
-			c.AbortWithStatusJSON(404, gin.H{"error": txt.UcFirst(err.Error())})
+		if !ok {
 			return
 		}
 
@@ -179,14 +157,28 @@ func LabelThumbnail(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		q := query.New(conf.Db())
+		q, viewer := mediaQuery(c, conf)
 
+		if viewer == "" {
+			c.Data(http.StatusUnauthorized, "image/svg+xml", labelIconSvg)
+			return
+		}
+
+		// Covers depend on the photos visible to the user and may be watermarked.
 		gc := conf.Cache()
-		cacheKey := fmt.Sprintf("label-thumbnail:%s:%s", labelUUID, typeName)
+		wm := watermarkTag(c, conf)
+		cacheKey := fmt.Sprintf("label-thumbnail:%s:%s:%s:%s", labelUUID, typeName, viewer, wm)
 
 		if cacheData, ok := gc.Get(cacheKey); ok {
 			log.Debugf("label: %s cache hit [%s]", cacheKey, time.Since(start))
-			c.Data(http.StatusOK, "image/jpeg", cacheData.([]byte))
+
+			cached := cacheData.(cachedThumb)
+
+			if notModified(c, cached.ETag, cacheControlCovers) {
+				return
+			}
+
+			serveData(c, "image/jpeg", cached.Data, cached.ModTime)
 			return
 		}
 
@@ -210,17 +202,40 @@ func LabelThumbnail(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
+		tag := entityTag(f.FileHash, typeName, wm)
+
+		if notModified(c, tag, cacheControlCovers) {
+			return
+		}
+
 		// Use original file if thumb size exceeds limit, see https://github.com/photoprism/photoprism/issues/157
 		if thumbType.ExceedsLimit() {
 			log.Debugf("label: using original, thumbnail size exceeds limit (width %d, height %d)", thumbType.Width, thumbType.Height)
 
+			if fileName, err = watermarkFile(c, conf, fileName, f.FileHash); err != nil {
+				log.Errorf("label: %s", err)
+				c.Data(http.StatusInternalServerError, "image/svg+xml", brokenIconSvg)
+				return
+			}
+
 			c.File(fileName)
 
 			return
 		}
 
 		if thumbnail, err := thumb.FromFile(fileName, f.FileHash, conf.ThumbnailsPath(), thumbType.Width, thumbType.Height, thumbType.Options...); err == nil {
+			if thumbnail, err = watermarkFile(c, conf, thumbnail, f.FileHash); err != nil {
+				log.Errorf("label: %s", err)
+				c.Data(http.StatusInternalServerError, "image/svg+xml", brokenIconSvg)
+				return
+			}
+
 			thumbData, err := ioutil.ReadFile(thumbnail)
+			modTime := time.Now()
+
+			if info, err := os.Stat(thumbnail); err == nil {
+				modTime = info.ModTime()
+			}
 
 			if err != nil {
 				log.Errorf("label: %s", err)
@@ -228,11 +243,11 @@ func LabelThumbnail(router *gin.RouterGroup, conf *config.Config) {
 				return
 			}
 
-			gc.Set(cacheKey, thumbData, time.Hour*4)
+			gc.Set(cacheKey, cachedThumb{ETag: tag, Data: thumbData, ModTime: modTime}, time.Hour*4)
 
 			log.Debugf("label: %s cached [%s]", cacheKey, time.Since(start))
 
-			c.Data(http.StatusOK, "image/jpeg", thumbData)
+			serveData(c, "image/jpeg", thumbData, modTime)
 		} else {
 			log.Errorf("label: %s", err)
 
@@ -2,14 +2,17 @@ package api
 
 import (
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/photoprism/photoprism/internal/config"
 	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/event"
 	"github.com/photoprism/photoprism/internal/form"
 	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/session"
 	"github.com/photoprism/photoprism/pkg/txt"
 )
 
@@ -34,18 +37,12 @@ func newLink(c *gin.Context) (link entity.Link, err error) {
 // POST /api/v1/albums/:uuid/link
 func LinkAlbum(router *gin.RouterGroup, conf *config.Config) {
 	router.POST("/albums/:uuid/link", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		db := conf.Db()
-		q := query.New(db)
+		q := userQuery(c, conf)
 
-		m, err := q.AlbumByUUID(c.Param("uuid"))
+		m, ok := editableAlbum(c, q, c.Param("uuid"))
 
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusNotFound, ErrAlbumNotFound)
+		if !ok {
 			return
 		}
 
@@ -65,18 +62,12 @@ func LinkAlbum(router *gin.RouterGroup, conf *config.Config) {
 // POST /api/v1/photos/:uuid/link
 func LinkPhoto(router *gin.RouterGroup, conf *config.Config) {
 	router.POST("/photos/:uuid/link", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		db := conf.Db()
-		q := query.New(db)
+		q := userQuery(c, conf)
 
-		m, err := q.PhotoByUUID(c.Param("uuid"))
+		m, ok := editablePhoto(c, q, c.Param("uuid"))
 
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusNotFound, ErrAlbumNotFound)
+		if !ok {
 			return
 		}
 
@@ -96,18 +87,38 @@ func LinkPhoto(router *gin.RouterGroup, conf *config.Config) {
 // POST /api/v1/labels/:uuid/link
 func LinkLabel(router *gin.RouterGroup, conf *config.Config) {
 	router.POST("/labels/:uuid/link", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
+		db := conf.Db()
+		q := userQuery(c, conf)
+
+		m, ok := editableLabel(c, q, c.Param("uuid"))
+
+		if !ok {
 			return
 		}
 
+		if link, err := newLink(c); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		} else {
+			db.Model(&m).Association("Links").Append(link)
+		}
+
+		event.Success("created label share link")
+
+		c.JSON(http.StatusOK, m)
+	})
+}
+
+// POST /api/v1/folders/:uuid/link
+func LinkFolder(router *gin.RouterGroup, conf *config.Config) {
+	router.POST("/folders/:uuid/link", func(c *gin.Context) {
 		db := conf.Db()
 		q := query.New(db)
 
-		m, err := q.LabelByUUID(c.Param("uuid"))
+		m, err := q.FolderByUUID(c.Param("uuid"))
 
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusNotFound, ErrAlbumNotFound)
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrFolderNotFound)
 			return
 		}
 
@@ -118,8 +129,165 @@ func LinkLabel(router *gin.RouterGroup, conf *config.Config) {
 			db.Model(&m).Association("Links").Append(link)
 		}
 
-		event.Success("created label share link")
+		event.Success("created folder share link")
 
 		c.JSON(http.StatusOK, m)
 	})
 }
+
+// GET /api/v1/links
+//
+// Query:
+//   count:  int    Max result count (required)
+//   offset: int    Result offset
+//   share:  string UUID of the shared album, label, folder or photo
+func GetLinks(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/links", func(c *gin.Context) {
+		var f form.LinkSearch
+
+		if err := c.MustBindWith(&f, binding.Form); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		result, err := userQuery(c, conf).Links(f)
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		c.Header("X-Result-Count", strconv.Itoa(f.Count))
+		c.Header("X-Result-Offset", strconv.Itoa(f.Offset))
+
+		c.JSON(http.StatusOK, result)
+	})
+}
+
+// PUT /api/v1/links/:token
+//
+// Changes password, expiry and permissions of a link, visitors must open it again if the password changed.
+//
+// Parameters:
+//   token: string Link token
+func UpdateLink(router *gin.RouterGroup, conf *config.Config) {
+	router.PUT("/links/:token", func(c *gin.Context) {
+		var f form.Link
+
+		if err := c.BindJSON(&f); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		m, ok := userLink(c, conf)
+
+		if !ok {
+			return
+		}
+
+		if f.Password != "" || f.RemovePassword {
+			m.SetPassword(f.Password)
+
+			if err := session.New(conf.Db()).DeleteLink(m.LinkToken); err != nil {
+				log.Errorf("link: %s", err)
+			}
+		}
+
+		if f.Expires != nil && *f.Expires > 0 {
+			expires := time.Now().Add(time.Duration(*f.Expires) * time.Second)
+			m.LinkExpires = &expires
+		} else if f.Expires != nil {
+			m.LinkExpires = nil
+		}
+
+		if f.CanComment != nil {
+			m.CanComment = *f.CanComment
+		}
+
+		if f.CanEdit != nil {
+			m.CanEdit = *f.CanEdit
+		}
+
+		if err := conf.Db().Save(&m).Error; err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		event.Success("share link saved")
+
+		c.JSON(http.StatusOK, m)
+	})
+}
+
+// DELETE /api/v1/links/:token
+//
+// Parameters:
+//   token: string Link token
+func DeleteLink(router *gin.RouterGroup, conf *config.Config) {
+	router.DELETE("/links/:token", func(c *gin.Context) {
+		m, ok := userLink(c, conf)
+
+		if !ok {
+			return
+		}
+
+		if err := conf.Db().Delete(&m).Error; err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		if err := session.New(conf.Db()).DeleteLink(m.LinkToken); err != nil {
+			log.Errorf("link: %s", err)
+		}
+
+		event.Success("share link revoked")
+
+		c.JSON(http.StatusOK, gin.H{"status": "ok", "token": m.LinkToken})
+	})
+}
+
+// userLink returns the link of the token in the request path if the current user may change it,
+// or aborts the request.
+func userLink(c *gin.Context, conf *config.Config) (m entity.Link, ok bool) {
+	q := userQuery(c, conf)
+	token := c.Param("token")
+
+	if results, err := q.Links(form.LinkSearch{Token: token, Count: 1}); err != nil || len(results) == 0 {
+		c.AbortWithStatusJSON(http.StatusNotFound, ErrLinkNotFound)
+		return m, false
+	}
+
+	m, err := q.LinkByToken(token)
+
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, ErrLinkNotFound)
+		return m, false
+	}
+
+	return m, true
+}
+
+// linkAccessed counts a view or download of a share link and notifies the owner of the shared item,
+// links of items without owner may be managed by all editors.
+func linkAccessed(conf *config.Config, link *entity.Link, download bool) {
+	if err := link.Accessed(conf.Db(), download); err != nil {
+		log.Errorf("link: %s", err)
+		return
+	}
+
+	results, err := query.New(conf.Db()).Links(form.LinkSearch{Token: link.LinkToken, Count: 1})
+
+	if err != nil || len(results) == 0 {
+		log.Errorf("link: can't find owner of %s", link.ShareUUID)
+		return
+	}
+
+	event.Publish("link.accessed", event.Data{
+		"owner":     results[0].OwnerUUID,
+		"token":     link.LinkToken,
+		"uuid":      link.ShareUUID,
+		"download":  download,
+		"views":     link.LinkViews,
+		"downloads": link.LinkDownloads,
+	})
+}
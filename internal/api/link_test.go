@@ -31,7 +31,8 @@ func TestLinkAlbum(t *testing.T) {
 
 		link := album.Links[0]
 
-		assert.Equal(t, "foobar", link.LinkPassword)
+		// Passwords are hashed and never returned.
+		assert.Equal(t, "", link.LinkPassword)
 		assert.Nil(t, link.LinkExpires)
 		assert.False(t, link.CanComment)
 		assert.True(t, link.CanEdit)
@@ -52,3 +53,67 @@ func TestLinkAlbum(t *testing.T) {
 		}
 	})
 }
+
+func TestGetLinks(t *testing.T) {
+	t.Run("successful request", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		GetLinks(router, ctx)
+		result := PerformRequest(app, "GET", "/api/v1/links?count=10")
+		assert.Equal(t, http.StatusOK, result.Code)
+	})
+	t.Run("count missing", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		GetLinks(router, ctx)
+		result := PerformRequest(app, "GET", "/api/v1/links")
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+}
+
+func TestUpdateLink(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		UpdateLink(router, ctx)
+		result := PerformRequestWithBody(app, "PUT", "/api/v1/links/xxxxxxxxxx", `{"expires": 3600}`)
+		assert.Equal(t, http.StatusNotFound, result.Code)
+	})
+	t.Run("omitted fields", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+
+		var album entity.Album
+		var link entity.Link
+
+		LinkAlbum(router, ctx)
+		UpdateLink(router, ctx)
+
+		result1 := PerformRequestWithBody(app, "POST", "/api/v1/albums/3/link", `{"expires": 3600, "edit": true}`)
+
+		assert.Equal(t, http.StatusOK, result1.Code)
+
+		if err := json.Unmarshal(result1.Body.Bytes(), &album); err != nil {
+			t.Fatal(err)
+		}
+
+		token := album.Links[len(album.Links)-1].LinkToken
+
+		result2 := PerformRequestWithBody(app, "PUT", "/api/v1/links/"+token, `{"comment": true}`)
+
+		assert.Equal(t, http.StatusOK, result2.Code)
+
+		if err := json.Unmarshal(result2.Body.Bytes(), &link); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.True(t, link.CanComment)
+		assert.True(t, link.CanEdit)
+		assert.NotNil(t, link.LinkExpires)
+	})
+}
+
+func TestDeleteLink(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		DeleteLink(router, ctx)
+		result := PerformRequest(app, "DELETE", "/api/v1/links/xxxxxxxxxx")
+		assert.Equal(t, http.StatusNotFound, result.Code)
+	})
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// GET /api/v1/memories
+//
+// Query:
+//   date:    date Day to find memories for (format: "2006-01-02", default today)
+//   private: bool Include private photos
+//   nsfw:    bool Include NSFW photos
+//   count:   int  Max number of photos per year (default 10)
+func GetMemories(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/memories", func(c *gin.Context) {
+		var f form.Memories
+
+		if err := c.ShouldBindWith(&f, binding.Form); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		q := userQuery(c, conf)
+
+		result, err := q.Memories(f)
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	})
+}
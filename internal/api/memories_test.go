@@ -0,0 +1,27 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMemories(t *testing.T) {
+	t.Run("today", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+
+		GetMemories(router, conf)
+
+		result := PerformRequest(app, "GET", "/api/v1/memories")
+		assert.Equal(t, http.StatusOK, result.Code)
+	})
+	t.Run("invalid date", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+
+		GetMemories(router, conf)
+
+		result := PerformRequest(app, "GET", "/api/v1/memories?date=yesterday")
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+}
@@ -13,11 +13,6 @@ import (
 // GET /api/v1/moments/time
 func GetMomentsTime(router *gin.RouterGroup, conf *config.Config) {
 	router.GET("/moments/time", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		q := query.New(conf.Db())
 
 		result, err := q.GetMomentsTime()
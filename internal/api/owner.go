@@ -0,0 +1,93 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/query"
+)
+
+// canEdit returns true if the user may change an item and moderate comments on it,
+// items without owner may be changed by all editors.
+func canEdit(user *entity.User, ownerUUID string) bool {
+	switch {
+	case user == nil:
+		return false
+	case user.Role == acl.RoleAdmin:
+		return true
+	case ownerUUID == "":
+		return user.Role.Allows(acl.RoleEditor)
+	default:
+		return ownerUUID == user.UserUUID
+	}
+}
+
+// editablePhoto returns the photo with the given UUID if the current user may change it, or aborts the request.
+func editablePhoto(c *gin.Context, q *query.Query, photoUUID string) (m entity.Photo, ok bool) {
+	m, err := q.PhotoByUUID(photoUUID)
+
+	if err != nil || !q.PhotoVisible(m.PhotoUUID) {
+		c.AbortWithStatusJSON(http.StatusNotFound, ErrPhotoNotFound)
+		return m, false
+	}
+
+	if !canEdit(CurrentUser(c), m.OwnerUUID) {
+		c.AbortWithStatusJSON(http.StatusForbidden, ErrForbidden)
+		return m, false
+	}
+
+	return m, true
+}
+
+// editableAlbum returns the album with the given UUID if the current user may change it, or aborts the request.
+func editableAlbum(c *gin.Context, q *query.Query, albumUUID string) (m entity.Album, ok bool) {
+	m, err := q.AlbumByUUID(albumUUID)
+
+	if err != nil || !q.AlbumVisible(m.AlbumUUID) {
+		c.AbortWithStatusJSON(http.StatusNotFound, ErrAlbumNotFound)
+		return m, false
+	}
+
+	if !canEdit(CurrentUser(c), m.OwnerUUID) {
+		c.AbortWithStatusJSON(http.StatusForbidden, ErrForbidden)
+		return m, false
+	}
+
+	return m, true
+}
+
+// editableLabel returns the label with the given UUID if the current user may change it, or aborts the request.
+func editableLabel(c *gin.Context, q *query.Query, labelUUID string) (m entity.Label, ok bool) {
+	m, err := q.LabelByUUID(labelUUID)
+
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, ErrLabelNotFound)
+		return m, false
+	}
+
+	if !canEdit(CurrentUser(c), m.OwnerUUID) {
+		c.AbortWithStatusJSON(http.StatusForbidden, ErrForbidden)
+		return m, false
+	}
+
+	return m, true
+}
+
+// editableUUIDs returns the selected UUIDs of rows in a table the current user may change, including
+// archived rows. It must only be used by routes that require the editor role, see canEdit.
+func editableUUIDs(c *gin.Context, db *gorm.DB, table, uuidColumn string, uuids []string) (result []string) {
+	s := db.Unscoped().Table(table).Where(uuidColumn+" IN (?)", uuids)
+
+	if user := CurrentUser(c); user == nil || user.Role != acl.RoleAdmin {
+		s = s.Where("owner_uuid IN ('', ?)", currentUserUUID(c))
+	}
+
+	if err := s.Pluck(uuidColumn, &result).Error; err != nil {
+		log.Errorf("%s: %s", table, err)
+	}
+
+	return result
+}
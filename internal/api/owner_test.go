@@ -0,0 +1,21 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanEdit(t *testing.T) {
+	owner := &entity.User{UserUUID: "u1", Role: acl.RoleViewer}
+	editor := &entity.User{UserUUID: "u2", Role: acl.RoleEditor}
+
+	assert.False(t, canEdit(nil, ""))
+	assert.True(t, canEdit(&entity.Admin, "u1"))
+	assert.True(t, canEdit(owner, "u1"))
+	assert.False(t, canEdit(editor, "u1"))
+	assert.True(t, canEdit(editor, ""))
+	assert.False(t, canEdit(owner, ""))
+}
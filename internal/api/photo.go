@@ -10,7 +10,6 @@ import (
 	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/event"
 	"github.com/photoprism/photoprism/internal/form"
-	"github.com/photoprism/photoprism/internal/query"
 	"github.com/photoprism/photoprism/pkg/fs"
 	"github.com/photoprism/photoprism/pkg/txt"
 )
@@ -21,19 +20,24 @@ import (
 //   uuid: string PhotoUUID as returned by the API
 func GetPhoto(router *gin.RouterGroup, conf *config.Config) {
 	router.GET("/photos/:uuid", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
+		if c.Param("uuid") == "facets" {
+			getPhotoFacets(c, conf)
 			return
 		}
 
-		q := query.New(conf.Db())
+		q := userQuery(c, conf)
 		p, err := q.PreloadPhotoByUUID(c.Param("uuid"))
 
-		if err != nil {
+		if err != nil || !q.PhotoVisible(p.PhotoUUID) {
 			c.AbortWithStatusJSON(http.StatusNotFound, ErrPhotoNotFound)
 			return
 		}
 
+		// Share links must only be visible to users who may change them.
+		if !canEdit(CurrentUser(c), p.OwnerUUID) {
+			p.Links = nil
+		}
+
 		c.JSON(http.StatusOK, p)
 	})
 }
@@ -41,18 +45,12 @@ func GetPhoto(router *gin.RouterGroup, conf *config.Config) {
 // PUT /api/v1/photos/:uuid
 func UpdatePhoto(router *gin.RouterGroup, conf *config.Config) {
 	router.PUT("/photos/:uuid", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		id := c.Param("uuid")
-		q := query.New(conf.Db())
+		q := userQuery(c, conf)
 
-		m, err := q.PhotoByUUID(id)
+		m, ok := editablePhoto(c, q, id)
 
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusNotFound, ErrPhotoNotFound)
+		if !ok {
 			return
 		}
 
@@ -98,10 +96,15 @@ func UpdatePhoto(router *gin.RouterGroup, conf *config.Config) {
 //   uuid: string PhotoUUID as returned by the API
 func GetPhotoDownload(router *gin.RouterGroup, conf *config.Config) {
 	router.GET("/photos/:uuid/download", func(c *gin.Context) {
-		q := query.New(conf.Db())
+		q := downloadQuery(c, conf)
+
+		if q == nil {
+			return
+		}
+
 		f, err := q.FileByPhotoUUID(c.Param("uuid"))
 
-		if err != nil {
+		if err != nil || !q.PhotoVisible(f.PhotoUUID) {
 			c.AbortWithStatusJSON(http.StatusNotFound, ErrPhotoNotFound)
 			return
 		}
@@ -118,10 +121,24 @@ func GetPhotoDownload(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		downloadFileName := f.ShareFileName()
+		if notModified(c, entityTag(f.FileHash, watermarkTag(c, conf)), cacheControlOriginals) {
+			return
+		}
+
+		if fileName, err = watermarkFile(c, conf, fileName, f.FileHash); err != nil {
+			log.Errorf("download: %s", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		downloadFileName := watermarkShareName(f.ShareFileName(), fileName)
 
 		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", downloadFileName))
 
+		if link := CurrentLink(c); link != nil {
+			linkAccessed(conf, link, true)
+		}
+
 		c.File(fileName)
 	})
 }
@@ -132,17 +149,11 @@ func GetPhotoDownload(router *gin.RouterGroup, conf *config.Config) {
 //   uuid: string PhotoUUID as returned by the API
 func LikePhoto(router *gin.RouterGroup, conf *config.Config) {
 	router.POST("/photos/:uuid/like", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		id := c.Param("uuid")
-		q := query.New(conf.Db())
-		m, err := q.PhotoByUUID(id)
+		q := userQuery(c, conf)
+		m, ok := editablePhoto(c, q, id)
 
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusNotFound, ErrPhotoNotFound)
+		if !ok {
 			return
 		}
 
@@ -165,17 +176,11 @@ func LikePhoto(router *gin.RouterGroup, conf *config.Config) {
 //   uuid: string PhotoUUID as returned by the API
 func DislikePhoto(router *gin.RouterGroup, conf *config.Config) {
 	router.DELETE("/photos/:uuid/like", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		id := c.Param("uuid")
-		q := query.New(conf.Db())
-		m, err := q.PhotoByUUID(id)
+		q := userQuery(c, conf)
+		m, ok := editablePhoto(c, q, id)
 
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusNotFound, ErrPhotoNotFound)
+		if !ok {
 			return
 		}
 
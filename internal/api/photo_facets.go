@@ -0,0 +1,94 @@
+package api
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// cachedFacets represents facet counts kept in the in-memory cache.
+type cachedFacets struct {
+	ETag string
+	Data []byte
+}
+
+// GET /api/v1/photos/facets
+//
+// Query:
+//   Same parameters as GET /api/v1/photos, count and offset are ignored
+//   top: int Max number of values per facet (default 10, max 100)
+//
+// The route is dispatched by GetPhoto, as the router doesn't allow static and
+// wildcard path segments at the same position.
+func getPhotoFacets(c *gin.Context, conf *config.Config) {
+	var f form.PhotoSearch
+
+	if err := c.MustBindWith(&f, binding.Form); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+		return
+	}
+
+	top, _ := strconv.Atoi(c.Query("top"))
+
+	if top <= 0 {
+		top = query.FacetLimit
+	} else if top > 100 {
+		top = 100
+	}
+
+	// Counts depend on the photos visible to the user.
+	gc := conf.Cache()
+	cacheKey := fmt.Sprintf("photo-facets:%d:%s:%s", top, c.Request.URL.Query().Encode(), currentUserUUID(c))
+
+	if cacheData, ok := gc.Get(cacheKey); ok {
+		cached := cacheData.(cachedFacets)
+
+		if notModified(c, cached.ETag, cacheControlFacets) {
+			return
+		}
+
+		c.Data(http.StatusOK, "application/json; charset=utf-8", cached.Data)
+		return
+	}
+
+	q := userQuery(c, conf)
+	result, err := q.PhotoFacets(f, top)
+
+	if queryErr, ok := err.(*form.QueryError); ok {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(queryErr.Error()), "position": queryErr.Pos})
+		return
+	} else if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+		return
+	}
+
+	data, err := json.Marshal(result)
+
+	if err != nil {
+		log.Errorf("photos: %s", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrUnexpectedError)
+		return
+	}
+
+	hash := sha1.Sum(data)
+	etag := entityTag(hex.EncodeToString(hash[:])[:16])
+
+	gc.Set(cacheKey, cachedFacets{ETag: etag, Data: data}, 5*time.Minute)
+
+	if notModified(c, etag, cacheControlFacets) {
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+}
@@ -9,7 +9,6 @@ import (
 	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/event"
 	"github.com/photoprism/photoprism/internal/form"
-	"github.com/photoprism/photoprism/internal/query"
 	"github.com/photoprism/photoprism/pkg/txt"
 )
 
@@ -19,17 +18,11 @@ import (
 //   uuid: string PhotoUUID as returned by the API
 func AddPhotoLabel(router *gin.RouterGroup, conf *config.Config) {
 	router.POST("/photos/:uuid/label", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
-		q := query.New(conf.Db())
-		m, err := q.PhotoByUUID(c.Param("uuid"))
+		q := userQuery(c, conf)
+		m, ok := editablePhoto(c, q, c.Param("uuid"))
 		db := conf.Db()
 
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusNotFound, ErrPhotoNotFound)
+		if !ok {
 			return
 		}
 
@@ -59,6 +52,10 @@ func AddPhotoLabel(router *gin.RouterGroup, conf *config.Config) {
 			}
 		}
 
+		if lm.New {
+			lm.OwnerUUID = currentUserUUID(c)
+		}
+
 		db.Save(&lm)
 
 		p, err := q.PreloadPhotoByUUID(c.Param("uuid"))
@@ -79,16 +76,10 @@ func AddPhotoLabel(router *gin.RouterGroup, conf *config.Config) {
 //   id: int LabelId as returned by the API
 func RemovePhotoLabel(router *gin.RouterGroup, conf *config.Config) {
 	router.DELETE("/photos/:uuid/label/:id", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
+		q := userQuery(c, conf)
+		m, ok := editablePhoto(c, q, c.Param("uuid"))
 
-		q := query.New(conf.Db())
-		m, err := q.PhotoByUUID(c.Param("uuid"))
-
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusNotFound, ErrPhotoNotFound)
+		if !ok {
 			return
 		}
 
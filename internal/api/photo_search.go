@@ -5,7 +5,6 @@ import (
 	"strconv"
 
 	"github.com/photoprism/photoprism/internal/config"
-	"github.com/photoprism/photoprism/internal/query"
 	"github.com/photoprism/photoprism/pkg/txt"
 
 	"github.com/gin-gonic/gin"
@@ -16,27 +15,27 @@ import (
 // GET /api/v1/photos
 //
 // Query:
-//   q:         string Query string
+//   q:         string Query string, supports AND, OR, NOT, -term, (...), "phrases" and filters like iso:100-400
 //   label:     string Label
 //   cat:       string Category
 //   country:   string Country code
+//   path:      string Folder path relative to the originals directory, see /api/v1/folders
+//   recursive: bool   Include photos in subfolders of path
 //   camera:    int    UpdateCamera ID
-//   order:     string Sort order
+//   order:     string Sort order, text queries are ranked by relevance unless sorted by "newest", "oldest" or "imported"
 //   count:     int    Max result count (required)
 //   offset:    int    Result offset
+//   cursor:    string Next page cursor from the X-Next-Cursor header of the previous response, replaces offset
 //   before:    date   Find photos taken before (format: "2006-01-02")
 //   after:     date   Find photos taken after (format: "2006-01-02")
 //   favorites: bool   Find favorites only
+//   similar:   string Photo UUID, sorts photos by the similarity of their color palette
+//   palette:   string Hex palette like "6B3300FFF:88FF00FF0" or "#2196F3,#F5F5F5", see colors.ParsePalette
 func GetPhotos(router *gin.RouterGroup, conf *config.Config) {
 	router.GET("/photos", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		var f form.PhotoSearch
 
-		q := query.New(conf.Db())
+		q := userQuery(c, conf)
 		err := c.MustBindWith(&f, binding.Form)
 
 		if err != nil {
@@ -44,9 +43,12 @@ func GetPhotos(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		result, err := q.Photos(f)
+		result, next, err := q.PhotoPage(f)
 
-		if err != nil {
+		if queryErr, ok := err.(*form.QueryError); ok {
+			c.AbortWithStatusJSON(400, gin.H{"error": txt.UcFirst(queryErr.Error()), "position": queryErr.Pos})
+			return
+		} else if err != nil {
 			c.AbortWithStatusJSON(400, gin.H{"error": txt.UcFirst(err.Error())})
 			return
 		}
@@ -54,6 +56,10 @@ func GetPhotos(router *gin.RouterGroup, conf *config.Config) {
 		c.Header("X-Result-Count", strconv.Itoa(f.Count))
 		c.Header("X-Result-Offset", strconv.Itoa(f.Offset))
 
+		if next != "" {
+			c.Header("X-Next-Cursor", next)
+		}
+
 		c.JSON(http.StatusOK, result)
 	})
 }
@@ -4,10 +4,11 @@ import (
 	"fmt"
 	"net/http"
 	"path"
+	"path/filepath"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/photoprism/photoprism/internal/config"
-	"github.com/photoprism/photoprism/internal/query"
 	"github.com/photoprism/photoprism/internal/thumb"
 	"github.com/photoprism/photoprism/pkg/fs"
 )
@@ -31,10 +32,16 @@ func GetThumbnail(router *gin.RouterGroup, conf *config.Config) {
 		}
 
 		db := conf.Db()
-		q := query.New(db)
+		q, viewer := mediaQuery(c, conf)
+
+		if viewer == "" {
+			c.Data(http.StatusUnauthorized, "image/svg+xml", photoIconSvg)
+			return
+		}
+
 		f, err := q.FileByHash(fileHash)
 
-		if err != nil {
+		if err != nil || !q.PhotoVisible(f.PhotoUUID) {
 			c.Data(http.StatusNotFound, "image/svg+xml", photoIconSvg)
 			return
 		}
@@ -56,20 +63,60 @@ func GetThumbnail(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
+		format := fs.TypeJpeg
+
+		if c.Query("download") == "" {
+			format = thumbFormat(c, watermarkRequired(c, conf))
+		}
+
+		if notModified(c, entityTag(f.FileHash, typeName, string(format), watermarkTag(c, conf)), cacheControlThumbs) {
+			return
+		}
+
 		// Use original file if thumb size exceeds limit, see https://github.com/photoprism/photoprism/issues/157
 		if thumbType.ExceedsLimit() && c.Query("download") == "" {
 			log.Debugf("photo: using original, thumbnail size exceeds limit (width %d, height %d)", thumbType.Width, thumbType.Height)
 
+			if fileName, err = watermarkFile(c, conf, fileName, f.FileHash); err != nil {
+				log.Errorf("photo: %s", err)
+				c.Data(http.StatusInternalServerError, "image/svg+xml", brokenIconSvg)
+				return
+			}
+
 			c.File(fileName)
 
 			return
 		}
 
-		if thumbnail, err := thumb.FromFile(fileName, f.FileHash, conf.ThumbnailsPath(), thumbType.Width, thumbType.Height, thumbType.Options...); err == nil {
+		thumbnail, err := thumb.FromFile(fileName, f.FileHash, conf.ThumbnailsPath(), thumbType.Width, thumbType.Height, thumbType.FormatOptions(format)...)
+
+		if err != nil && format != fs.TypeJpeg {
+			log.Warnf("photo: %s, falling back to jpeg", err)
+
+			format = fs.TypeJpeg
+			c.Header("ETag", entityTag(f.FileHash, typeName, string(format), watermarkTag(c, conf)))
+			thumbnail, err = thumb.FromFile(fileName, f.FileHash, conf.ThumbnailsPath(), thumbType.Width, thumbType.Height, thumbType.Options...)
+		}
+
+		if err == nil {
+			// Keep track of access times so that unused on-demand thumbnails can be evicted.
+			if thumbType.SkipPreRender() {
+				if err := thumb.Touch(thumbnail); err != nil {
+					log.Warnf("photo: %s", err)
+				}
+			}
+
+			if thumbnail, err = watermarkFile(c, conf, thumbnail, f.FileHash); err != nil {
+				log.Errorf("photo: %s", err)
+				c.Data(http.StatusInternalServerError, "image/svg+xml", brokenIconSvg)
+				return
+			}
+
 			if c.Query("download") != "" {
 				c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", f.ShareFileName()))
 			}
 
+			c.Header("Content-Type", thumbMimeType(thumbnail))
 			c.File(thumbnail)
 		} else {
 			log.Errorf("photo: %s", err)
@@ -81,3 +128,26 @@ func GetThumbnail(router *gin.RouterGroup, conf *config.Config) {
 		}
 	})
 }
+
+// thumbFormat returns the thumbnail format accepted by the client and sets the Vary header accordingly.
+// Watermarked images are always JPEG, since WebP and AVIF files can't be decoded to apply the watermark.
+func thumbFormat(c *gin.Context, watermark bool) fs.FileType {
+	c.Header("Vary", "Accept")
+
+	if watermark {
+		return fs.TypeJpeg
+	}
+
+	return thumb.AcceptFormat(c.GetHeader("Accept"))
+}
+
+// thumbMimeType returns the mime type of a thumbnail file based on its extension.
+func thumbMimeType(fileName string) string {
+	format := fs.FileType(strings.TrimPrefix(filepath.Ext(fileName), "."))
+
+	if mimeType, ok := thumb.FormatMimeTypes[format]; ok {
+		return mimeType
+	}
+
+	return fs.MimeTypeJpeg
+}
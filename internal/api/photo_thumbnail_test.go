@@ -2,8 +2,11 @@ package api
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/pkg/fs"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -30,3 +33,22 @@ func TestGetThumbnail(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, result.Code)
 	})
 }
+
+func TestThumbFormat(t *testing.T) {
+	request := func(accept string) *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request, _ = http.NewRequest("GET", "/api/v1/thumbnails/1/fit_1280", nil)
+		c.Request.Header.Set("Accept", accept)
+		return c
+	}
+
+	t.Run("webp visitor", func(t *testing.T) {
+		c := request("image/webp,*/*")
+
+		assert.Equal(t, fs.TypeJpeg, thumbFormat(c, true))
+		assert.Equal(t, "Accept", c.Writer.Header().Get("Vary"))
+	})
+	t.Run("avif visitor", func(t *testing.T) {
+		assert.Equal(t, fs.TypeJpeg, thumbFormat(request("image/avif,image/webp,*/*"), true))
+	})
+}
@@ -31,10 +31,16 @@ func GetPreview(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		previewFilename := fmt.Sprintf("%s/%s.jpg", thumbPath, t[6:8])
+		format := thumbFormat(c, watermarkRequired(c, conf))
+
+		if notModified(c, entityTag("preview-"+t, string(format), watermarkTag(c, conf)), cacheControlPreview) {
+			return
+		}
+
+		previewFilename := fmt.Sprintf("%s/%s.%s", thumbPath, t[6:8], format)
 
 		if fs.FileExists(previewFilename) {
-			c.File(previewFilename)
+			sendPreview(c, conf, previewFilename)
 			return
 		}
 
@@ -45,7 +51,8 @@ func GetPreview(router *gin.RouterGroup, conf *config.Config) {
 		f.Count = 12
 		f.Order = "relevance"
 
-		q := query.New(conf.Db())
+		// The preview is public, so it must only show photos visible to everyone.
+		q := query.New(conf.Db()).WithEveryone()
 		p, err := q.Photos(f)
 
 		if err != nil {
@@ -99,8 +106,15 @@ func GetPreview(router *gin.RouterGroup, conf *config.Config) {
 			}
 		}
 
-		// Save the resulting image as JPEG.
-		err = imaging.Save(preview, previewFilename)
+		// Save the resulting image in the negotiated format, use JPEG if that fails.
+		err = thumb.Save(preview, previewFilename, thumb.JpegQuality)
+
+		if err != nil && format != fs.TypeJpeg {
+			log.Warnf("preview: %s, falling back to jpeg", err)
+
+			previewFilename = fmt.Sprintf("%s/%s.%s", thumbPath, t[6:8], fs.TypeJpeg)
+			err = thumb.Save(preview, previewFilename, thumb.JpegQuality)
+		}
 
 		if err != nil {
 			log.Error(err)
@@ -108,6 +122,20 @@ func GetPreview(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		c.File(previewFilename)
+		sendPreview(c, conf, previewFilename)
 	})
 }
+
+// sendPreview sends the preview image, watermarked if required for the current visitor.
+func sendPreview(c *gin.Context, conf *config.Config, fileName string) {
+	fileName, err := watermarkFile(c, conf, fileName, "")
+
+	if err != nil {
+		log.Error(err)
+		c.Data(http.StatusInternalServerError, "image/svg+xml", brokenIconSvg)
+		return
+	}
+
+	c.Header("Content-Type", thumbMimeType(fileName))
+	c.File(fileName)
+}
@@ -18,11 +18,6 @@ import (
 // POST /api/v1/batch/photos/archive
 func BatchPhotosArchive(router *gin.RouterGroup, conf *config.Config) {
 	router.POST("/batch/photos/archive", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		start := time.Now()
 
 		var f form.Selection
@@ -38,10 +33,15 @@ func BatchPhotosArchive(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		log.Infof("photos: archiving %#v", f.Photos)
-
 		db := conf.Db()
 
+		if f.Photos = editableUUIDs(c, db, "photos", "photo_uuid", f.Photos); len(f.Photos) == 0 {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrForbidden)
+			return
+		}
+
+		log.Infof("photos: archiving %#v", f.Photos)
+
 		db.Where("photo_uuid IN (?)", f.Photos).Delete(&entity.Photo{})
 
 		elapsed := int(time.Since(start).Seconds())
@@ -57,11 +57,6 @@ func BatchPhotosArchive(router *gin.RouterGroup, conf *config.Config) {
 // POST /api/v1/batch/photos/restore
 func BatchPhotosRestore(router *gin.RouterGroup, conf *config.Config) {
 	router.POST("/batch/photos/restore", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		start := time.Now()
 
 		var f form.Selection
@@ -77,10 +72,15 @@ func BatchPhotosRestore(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		log.Infof("restoring photos: %#v", f.Photos)
-
 		db := conf.Db()
 
+		if f.Photos = editableUUIDs(c, db, "photos", "photo_uuid", f.Photos); len(f.Photos) == 0 {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrForbidden)
+			return
+		}
+
+		log.Infof("restoring photos: %#v", f.Photos)
+
 		db.Unscoped().Model(&entity.Photo{}).Where("photo_uuid IN (?)", f.Photos).
 			UpdateColumn("deleted_at", gorm.Expr("NULL"))
 
@@ -97,11 +97,6 @@ func BatchPhotosRestore(router *gin.RouterGroup, conf *config.Config) {
 // POST /api/v1/batch/albums/delete
 func BatchAlbumsDelete(router *gin.RouterGroup, conf *config.Config) {
 	router.POST("/batch/albums/delete", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		var f form.Selection
 
 		if err := c.BindJSON(&f); err != nil {
@@ -115,10 +110,15 @@ func BatchAlbumsDelete(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		log.Infof("albums: deleting %#v", f.Albums)
-
 		db := conf.Db()
 
+		if f.Albums = editableUUIDs(c, db, "albums", "album_uuid", f.Albums); len(f.Albums) == 0 {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrForbidden)
+			return
+		}
+
+		log.Infof("albums: deleting %#v", f.Albums)
+
 		db.Where("album_uuid IN (?)", f.Albums).Delete(&entity.Album{})
 		db.Where("album_uuid IN (?)", f.Albums).Delete(&entity.PhotoAlbum{})
 
@@ -133,11 +133,6 @@ func BatchAlbumsDelete(router *gin.RouterGroup, conf *config.Config) {
 // POST /api/v1/batch/photos/private
 func BatchPhotosPrivate(router *gin.RouterGroup, conf *config.Config) {
 	router.POST("/batch/photos/private", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		start := time.Now()
 
 		var f form.Selection
@@ -153,10 +148,15 @@ func BatchPhotosPrivate(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		log.Infof("marking photos as private: %#v", f.Photos)
-
 		db := conf.Db()
 
+		if f.Photos = editableUUIDs(c, db, "photos", "photo_uuid", f.Photos); len(f.Photos) == 0 {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrForbidden)
+			return
+		}
+
+		log.Infof("marking photos as private: %#v", f.Photos)
+
 		db.Model(entity.Photo{}).Where("photo_uuid IN (?)", f.Photos).UpdateColumn("photo_private", gorm.Expr("IF (`photo_private`, 0, 1)"))
 
 		elapsed := time.Since(start)
@@ -168,11 +168,6 @@ func BatchPhotosPrivate(router *gin.RouterGroup, conf *config.Config) {
 // POST /api/v1/batch/photos/story
 func BatchPhotosStory(router *gin.RouterGroup, conf *config.Config) {
 	router.POST("/batch/photos/story", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		start := time.Now()
 
 		var f form.Selection
@@ -188,10 +183,15 @@ func BatchPhotosStory(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		log.Infof("marking photos as story: %#v", f.Photos)
-
 		db := conf.Db()
 
+		if f.Photos = editableUUIDs(c, db, "photos", "photo_uuid", f.Photos); len(f.Photos) == 0 {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrForbidden)
+			return
+		}
+
+		log.Infof("marking photos as story: %#v", f.Photos)
+
 		db.Model(entity.Photo{}).Where("photo_uuid IN (?)", f.Photos).Updates(map[string]interface{}{
 			"photo_story": gorm.Expr("IF (`photo_story`, 0, 1)"),
 		})
@@ -205,11 +205,6 @@ func BatchPhotosStory(router *gin.RouterGroup, conf *config.Config) {
 // POST /api/v1/batch/labels/delete
 func BatchLabelsDelete(router *gin.RouterGroup, conf *config.Config) {
 	router.POST("/batch/labels/delete", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		var f form.Selection
 
 		if err := c.BindJSON(&f); err != nil {
@@ -223,10 +218,15 @@ func BatchLabelsDelete(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		log.Infof("labels: deleting %#v", f.Labels)
-
 		db := conf.Db()
 
+		if f.Labels = editableUUIDs(c, db, "labels", "label_uuid", f.Labels); len(f.Labels) == 0 {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrForbidden)
+			return
+		}
+
+		log.Infof("labels: deleting %#v", f.Labels)
+
 		db.Where("label_uuid IN (?)", f.Labels).Delete(&entity.Label{})
 
 		event.Publish("config.updated", event.Data(conf.ClientConfig()))
@@ -2,14 +2,25 @@ package api
 
 import (
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/internal/acl"
 	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/query"
 	"github.com/photoprism/photoprism/internal/session"
 	"github.com/photoprism/photoprism/pkg/txt"
 )
 
+// contextUser is the gin context key of the user set by the Auth middleware.
+const contextUser = "user"
+
+// sessionCookie contains the session token, so that browsers can load thumbnails and downloads of private photos.
+const sessionCookie = "session_token"
+
 // POST /api/v1/session
 func CreateSession(router *gin.RouterGroup, conf *config.Config) {
 	router.POST("/session", func(c *gin.Context) {
@@ -20,16 +31,25 @@ func CreateSession(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		if !conf.CheckPassword(f.Password) {
-			c.AbortWithStatusJSON(400, gin.H{"error": "Invalid password"})
+		q := query.New(conf.Db())
+		user, err := q.UserByName(f.UserName())
+
+		if err != nil || !user.CheckPassword(f.Password) {
+			c.AbortWithStatusJSON(400, gin.H{"error": "Invalid user name or password"})
 			return
 		}
 
-		user := gin.H{"ID": 1, "FirstName": "Admin", "LastName": "", "Role": "admin", "Email": "photoprism@localhost"}
+		token, err := session.New(conf.Db()).Create(user.UserUUID, c.ClientIP(), c.Request.UserAgent())
 
-		token := session.Create(user)
+		if err != nil {
+			log.Errorf("session: %s", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrUnexpectedError)
+			return
+		}
 
 		c.Header("X-Session-Token", token)
+		c.SetSameSite(http.SameSiteStrictMode)
+		c.SetCookie(sessionCookie, token, 0, "/api/v1", "", false, true)
 
 		s := gin.H{"token": token, "user": user, "config": conf.ClientConfig()}
 
@@ -42,22 +62,228 @@ func DeleteSession(router *gin.RouterGroup, conf *config.Config) {
 	router.DELETE("/session/:token", func(c *gin.Context) {
 		token := c.Param("token")
 
-		session.Delete(token)
+		session.New(conf.Db()).Delete(token)
+
+		c.SetCookie(sessionCookie, "", -1, "/api/v1", "", false, true)
 
 		c.JSON(http.StatusOK, gin.H{"status": "ok", "token": token})
 	})
 }
 
-// Returns true, if user doesn't have a valid session token
-func Unauthorized(c *gin.Context, conf *config.Config) bool {
-	// Always return false if site is public
+// GET /api/v1/sessions
+func GetSessions(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/sessions", func(c *gin.Context) {
+		user := CurrentUser(c)
+
+		results, err := session.New(conf.Db()).List(user.UserUUID)
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		current := session.Hash(sessionToken(c))
+
+		for i := range results {
+			results[i].Current = results[i].ID == current
+		}
+
+		c.JSON(http.StatusOK, results)
+	})
+}
+
+// DELETE /api/v1/sessions/:uuid
+//
+// Parameters:
+//   uuid: string Session UUID
+func RevokeSession(router *gin.RouterGroup, conf *config.Config) {
+	router.DELETE("/sessions/:uuid", func(c *gin.Context) {
+		user := CurrentUser(c)
+		sessionUUID := c.Param("uuid")
+
+		found, err := session.New(conf.Db()).Revoke(user.UserUUID, sessionUUID)
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		if !found {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrSessionNotFound)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"status": "ok", "uuid": sessionUUID})
+	})
+}
+
+// DELETE /api/v1/sessions
+//
+// Logs out everyone except the current session.
+func DeleteSessions(router *gin.RouterGroup, conf *config.Config) {
+	router.DELETE("/sessions", func(c *gin.Context) {
+		count, err := session.New(conf.Db()).DeleteAll(sessionToken(c))
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		log.Infof("session: %s logged out everyone (%d sessions)", CurrentUser(c).UserName, count)
+
+		c.JSON(http.StatusOK, gin.H{"status": "ok", "count": count})
+	})
+}
+
+// SessionUser returns the user of the current session or API token, or nil if there is none
+// or the token may not read photos. All requests are made as default admin user if the site is public.
+func SessionUser(c *gin.Context, conf *config.Config) *entity.User {
+	user, scopes := authUser(c, conf)
+
+	if user == nil || !scopes.Allows(acl.ScopePhotosRead) {
+		return nil
+	}
+
+	return user
+}
+
+// authUser returns the user and the scopes granted by the API token or session of the current request,
+// sessions have all scopes. The user is nil if neither is valid.
+func authUser(c *gin.Context, conf *config.Config) (*entity.User, acl.Scopes) {
 	if conf.Public() {
-		return false
+		user := entity.Admin
+		return &user, acl.Scopes{acl.ScopeAdmin}
 	}
 
-	// Get session token from HTTP header
-	token := c.GetHeader("X-Session-Token")
+	var userUUID string
+	scopes := acl.Scopes{acl.ScopeAdmin}
+
+	if secret := bearerToken(c); secret != "" {
+		token, err := query.New(conf.Db()).TokenBySecret(secret)
+
+		if err != nil || token.Expired(time.Now()) {
+			return nil, nil
+		}
+
+		if err := token.Touch(conf.Db()); err != nil {
+			log.Errorf("token: %s", err)
+		}
+
+		userUUID = token.UserUUID
+		scopes = token.Scopes()
+	} else if m, ok := session.New(conf.Db()).Get(sessionToken(c)); ok && !m.Visitor() {
+		userUUID = m.UserUUID
+	} else {
+		return nil, nil
+	}
+
+	// Users may have been removed or changed since they logged in.
+	user, err := query.New(conf.Db()).UserByUUID(userUUID)
+
+	if err != nil {
+		return nil, nil
+	}
+
+	return &user, scopes
+}
+
+// sessionToken returns the session token from the HTTP header or cookie.
+func sessionToken(c *gin.Context) string {
+	if token := c.GetHeader("X-Session-Token"); token != "" {
+		return token
+	}
+
+	token, _ := c.Cookie(sessionCookie)
+
+	return token
+}
+
+// bearerToken returns the API token from the authorization header, if any.
+func bearerToken(c *gin.Context) string {
+	const prefix = "Bearer "
+
+	if header := c.GetHeader("Authorization"); strings.HasPrefix(header, prefix) {
+		return strings.TrimSpace(header[len(prefix):])
+	}
+
+	return ""
+}
+
+// Auth returns a middleware that aborts requests unless the session user has the required role,
+// and API tokens additionally need the required scope.
+func Auth(conf *config.Config, role acl.Role, scope acl.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, scopes := authUser(c, conf)
+
+		if user == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
+			return
+		}
+
+		if !user.Role.Allows(role) || !scopes.Allows(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrForbidden)
+			return
+		}
+
+		c.Set(contextUser, user)
+		c.Next()
+	}
+}
+
+// userQuery returns a query that only finds photos and albums visible to the current user.
+func userQuery(c *gin.Context, conf *config.Config) *query.Query {
+	return query.New(conf.Db()).WithUser(CurrentUser(c))
+}
+
+// mediaQuery returns a query restricted to the session user or share link visitor for routes without
+// Auth middleware, like thumbnails and downloads requested by the browser. The viewer is the UUID of
+// the user or link token of the visitor for caching, and empty without valid session.
+func mediaQuery(c *gin.Context, conf *config.Config) (q *query.Query, viewer string) {
+	if user := SessionUser(c, conf); user != nil {
+		c.Set(contextUser, user)
+		return query.New(conf.Db()).WithUser(user), user.UserUUID
+	}
+
+	if link, ok := visitorLink(c, conf); ok {
+		c.Set(contextLink, &link)
+		return query.New(conf.Db()).WithShare(link.ShareUUID), "link:" + link.LinkToken
+	}
+
+	return nil, ""
+}
+
+// downloadQuery returns a query like mediaQuery for downloading originals, which requires the viewer role
+// or a share link. It returns nil and aborts the request otherwise.
+func downloadQuery(c *gin.Context, conf *config.Config) *query.Query {
+	q, viewer := mediaQuery(c, conf)
+
+	if viewer == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
+		return nil
+	}
+
+	if user := CurrentUser(c); user != nil && !user.Role.Allows(acl.RoleViewer) {
+		c.AbortWithStatusJSON(http.StatusForbidden, ErrForbidden)
+		return nil
+	}
+
+	return q
+}
+
+// CurrentUser returns the user set by the Auth middleware, or nil for routes that don't require a login.
+func CurrentUser(c *gin.Context) *entity.User {
+	if user, ok := c.Get(contextUser); ok {
+		return user.(*entity.User)
+	}
+
+	return nil
+}
+
+// currentUserUUID returns the UUID of the current user, or an empty string for routes that don't require a login.
+func currentUserUUID(c *gin.Context) string {
+	if user := CurrentUser(c); user != nil {
+		return user.UserUUID
+	}
 
-	// Check if session token is valid
-	return !session.Exists(token)
+	return ""
 }
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateSession(t *testing.T) {
+	t.Run("invalid password", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		CreateSession(router, ctx)
+		result := PerformRequestWithBody(app, "POST", "/api/v1/session", `{"user": "admin", "password": "xxx"}`)
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+	t.Run("unknown user", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		CreateSession(router, ctx)
+		result := PerformRequestWithBody(app, "POST", "/api/v1/session", `{"user": "nobody", "password": "photoprism"}`)
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+}
+
+func TestAuth(t *testing.T) {
+	t.Run("public", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+
+		router.GET("/auth", Auth(ctx, acl.RoleAdmin, acl.ScopeAdmin), func(c *gin.Context) {
+			user := CurrentUser(c)
+
+			if user == nil {
+				c.AbortWithStatus(http.StatusInternalServerError)
+				return
+			}
+
+			c.String(http.StatusOK, user.UserName)
+		})
+
+		result := PerformRequest(app, "GET", "/api/v1/auth")
+		assert.Equal(t, http.StatusOK, result.Code)
+		assert.Equal(t, "admin", result.Body.String())
+	})
+}
+
+func TestGetSessions(t *testing.T) {
+	app, router, ctx := NewApiTest()
+	GetSessions(router.Group("", Auth(ctx, acl.RoleGuest, acl.ScopeAdmin)), ctx)
+	result := PerformRequest(app, "GET", "/api/v1/sessions")
+	assert.Equal(t, http.StatusOK, result.Code)
+}
+
+func TestRevokeSession(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		RevokeSession(router.Group("", Auth(ctx, acl.RoleGuest, acl.ScopeAdmin)), ctx)
+		result := PerformRequest(app, "DELETE", "/api/v1/sessions/s000000000000000")
+		assert.Equal(t, http.StatusNotFound, result.Code)
+	})
+}
+
+func TestBearerToken(t *testing.T) {
+	t.Run("bearer", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		router.GET("/bearer", func(c *gin.Context) { c.String(http.StatusOK, bearerToken(c)) })
+
+		req, _ := http.NewRequest("GET", "/api/v1/bearer", nil)
+		req.Header.Set("Authorization", "Bearer abc123")
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+
+		assert.Equal(t, "abc123", w.Body.String())
+	})
+	t.Run("basic", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		router.GET("/bearer", func(c *gin.Context) { c.String(http.StatusOK, bearerToken(c)) })
+
+		req, _ := http.NewRequest("GET", "/api/v1/bearer", nil)
+		req.Header.Set("Authorization", "Basic abc123")
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+
+		assert.Equal(t, "", w.Body.String())
+	})
+}
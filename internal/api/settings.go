@@ -12,11 +12,6 @@ import (
 // GET /api/v1/settings
 func GetSettings(router *gin.RouterGroup, conf *config.Config) {
 	router.GET("/settings", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		s := conf.Settings()
 
 		c.JSON(http.StatusOK, s)
@@ -26,11 +21,6 @@ func GetSettings(router *gin.RouterGroup, conf *config.Config) {
 // POST /api/v1/settings
 func SaveSettings(router *gin.RouterGroup, conf *config.Config) {
 	router.POST("/settings", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		s := conf.Settings()
 
 		if err := c.BindJSON(s); err != nil {
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// PUT /api/v1/photos/:uuid/share
+//
+// Sets the visibility of a photo to "private", "shared" or "everyone",
+// shared photos are visible to the users in the Users list.
+//
+// Parameters:
+//   uuid: string PhotoUUID as returned by the API
+func SharePhoto(router *gin.RouterGroup, conf *config.Config) {
+	router.PUT("/photos/:uuid/share", func(c *gin.Context) {
+		q := query.New(conf.Db())
+		m, err := q.PhotoByUUID(c.Param("uuid"))
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrPhotoNotFound)
+			return
+		}
+
+		if !shareWithUsers(c, conf, m.OwnerUUID, m.PhotoUUID, &m.PhotoVisibility) {
+			return
+		}
+
+		conf.Db().Model(&m).Update("photo_visibility", m.PhotoVisibility)
+
+		event.Success("photo visibility changed")
+
+		c.JSON(http.StatusOK, m)
+	})
+}
+
+// PUT /api/v1/albums/:uuid/share
+//
+// Sets the visibility of an album like PUT /api/v1/photos/:uuid/share,
+// photos of the owner in shared albums are visible as well.
+//
+// Parameters:
+//   uuid: string Album UUID
+func ShareAlbum(router *gin.RouterGroup, conf *config.Config) {
+	router.PUT("/albums/:uuid/share", func(c *gin.Context) {
+		q := query.New(conf.Db())
+		m, err := q.AlbumByUUID(c.Param("uuid"))
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrAlbumNotFound)
+			return
+		}
+
+		if !shareWithUsers(c, conf, m.OwnerUUID, m.AlbumUUID, &m.AlbumVisibility) {
+			return
+		}
+
+		conf.Db().Model(&m).Update("album_visibility", m.AlbumVisibility)
+
+		event.Success("album visibility changed")
+
+		c.JSON(http.StatusOK, m)
+	})
+}
+
+// shareWithUsers sets the visibility and the users a photo or album is shared with,
+// only owners and admins may do this. It returns false if the request was aborted.
+func shareWithUsers(c *gin.Context, conf *config.Config, ownerUUID, shareUUID string, visibility *string) bool {
+	if user := CurrentUser(c); user != nil && user.Role != acl.RoleAdmin && (ownerUUID == "" || ownerUUID != user.UserUUID) {
+		c.AbortWithStatusJSON(http.StatusForbidden, ErrForbidden)
+		return false
+	}
+
+	var f form.Share
+
+	if err := c.BindJSON(&f); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+		return false
+	}
+
+	if !entity.ValidVisibility(f.Visibility) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid visibility \"%s\"", f.Visibility)})
+		return false
+	}
+
+	var userUUIDs []string
+
+	if f.Visibility == entity.VisibilityShared {
+		q := query.New(conf.Db())
+
+		for _, name := range f.Users {
+			user, err := q.UserByName(name)
+
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("User \"%s\" not found", name)})
+				return false
+			}
+
+			userUUIDs = append(userUUIDs, user.UserUUID)
+		}
+	}
+
+	if err := entity.SetUserShares(conf.Db(), shareUUID, userUUIDs); err != nil {
+		log.Errorf("share: %s", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrUnexpectedError)
+		return false
+	}
+
+	*visibility = f.Visibility
+
+	return true
+}
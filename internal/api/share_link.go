@@ -0,0 +1,215 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/session"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// contextLink is the gin context key of the share link set by the Visitor middleware.
+const contextLink = "link"
+
+// visitorCookie contains the visitor session token, so that opening a share link doesn't replace the user session.
+const visitorCookie = "visitor_token"
+
+// GET /s/:token
+// POST /s/:token
+//
+// Opens a share link and returns a visitor session, which can only be used to view, download
+// and list what has been shared. Password protected links must be opened with POST.
+//
+// Parameters:
+//   token: string Link token
+func VisitLink(router *gin.RouterGroup, conf *config.Config) {
+	handler := func(c *gin.Context) {
+		var f form.LinkVisit
+
+		if c.Request.Method == http.MethodPost {
+			if err := c.BindJSON(&f); err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+				return
+			}
+		}
+
+		q := query.New(conf.Db())
+		link, err := q.LinkByToken(c.Param("token"))
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrLinkNotFound)
+			return
+		}
+
+		if link.Expired(time.Now()) {
+			c.AbortWithStatusJSON(http.StatusGone, ErrLinkExpired)
+			return
+		}
+
+		if !link.CheckPassword(f.Password) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrInvalidPassword)
+			return
+		}
+
+		kind, item := sharedItem(q, link.ShareUUID)
+
+		if item == nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrLinkNotFound)
+			return
+		}
+
+		token, err := session.New(conf.Db()).CreateVisitor(link, c.ClientIP(), c.Request.UserAgent())
+
+		if err != nil {
+			log.Errorf("session: %s", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrUnexpectedError)
+			return
+		}
+
+		c.Header("X-Session-Token", token)
+		c.SetSameSite(http.SameSiteStrictMode)
+		c.SetCookie(visitorCookie, token, 0, "/api/v1", "", false, true)
+
+		linkAccessed(conf, &link, false)
+
+		c.JSON(http.StatusOK, gin.H{"token": token, "type": kind, "uuid": link.ShareUUID, "comment": link.CanComment})
+	}
+
+	router.GET("/:token", handler)
+	router.POST("/:token", handler)
+}
+
+// GET /api/v1/shared
+//
+// Returns the album, label, folder or photo shared with the link of the current visitor.
+func GetShared(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/shared", func(c *gin.Context) {
+		link := CurrentLink(c)
+		kind, item := sharedItem(query.New(conf.Db()), link.ShareUUID)
+
+		if item == nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrLinkNotFound)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"type": kind, "uuid": link.ShareUUID, "comment": link.CanComment, kind: item})
+	})
+}
+
+// GET /api/v1/shared/photos
+//
+// Query:
+//   count:  int    Max result count (required)
+//   offset: int    Result offset
+//   order:  string Sort order like "newest" or "oldest"
+func GetSharedPhotos(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/shared/photos", func(c *gin.Context) {
+		var f form.PhotoSearch
+
+		if err := c.MustBindWith(&f, binding.Form); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		// Visitors can't search, so that nothing but the shared photos can be found.
+		f = form.PhotoSearch{Count: f.Count, Offset: f.Offset, Order: f.Order, Public: true}
+
+		result, err := query.New(conf.Db()).WithShare(CurrentLink(c).ShareUUID).Photos(f)
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		c.Header("X-Result-Count", strconv.Itoa(f.Count))
+		c.Header("X-Result-Offset", strconv.Itoa(f.Offset))
+
+		c.JSON(http.StatusOK, result)
+	})
+}
+
+// Visitor returns a middleware that aborts requests unless they are made with the session of a valid share link.
+func Visitor(conf *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		link, ok := visitorLink(c, conf)
+
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
+			return
+		}
+
+		c.Set(contextLink, &link)
+		c.Next()
+	}
+}
+
+// visitorLink returns the share link of the visitor session, links may have expired or been revoked since.
+func visitorLink(c *gin.Context, conf *config.Config) (link entity.Link, ok bool) {
+	m, ok := session.New(conf.Db()).Get(visitorToken(c))
+
+	if !ok || !m.Visitor() {
+		return link, false
+	}
+
+	link, err := query.New(conf.Db()).LinkByToken(m.LinkToken)
+
+	if err != nil || link.ShareUUID != m.ShareUUID || link.Expired(time.Now()) {
+		return link, false
+	}
+
+	return link, true
+}
+
+// visitorToken returns the visitor session token from the request header or cookie.
+func visitorToken(c *gin.Context) string {
+	if token := c.GetHeader("X-Session-Token"); token != "" {
+		return token
+	}
+
+	token, _ := c.Cookie(visitorCookie)
+
+	return token
+}
+
+// CurrentLink returns the share link set by the Visitor middleware or media routes, or nil if the request
+// wasn't made by a share link visitor.
+func CurrentLink(c *gin.Context) *entity.Link {
+	if link, ok := c.Get(contextLink); ok {
+		return link.(*entity.Link)
+	}
+
+	return nil
+}
+
+// sharedItem returns the type and entity of a shared album, label, folder or photo, or nil if it doesn't exist.
+// Links are removed, so that visitors can't see other links of the same item.
+func sharedItem(q *query.Query, shareUUID string) (kind string, item interface{}) {
+	if m, err := q.AlbumByUUID(shareUUID); err == nil {
+		m.Links = nil
+		return "album", m
+	}
+
+	if m, err := q.LabelByUUID(shareUUID); err == nil {
+		m.Links = nil
+		return "label", m
+	}
+
+	if m, err := q.FolderByUUID(shareUUID); err == nil {
+		m.Links = nil
+		return "folder", m
+	}
+
+	if m, err := q.PhotoByUUID(shareUUID); err == nil && !m.PhotoPrivate {
+		m.Links = nil
+		return "photo", m
+	}
+
+	return "", nil
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVisitLink(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		app, _, ctx := NewApiTest()
+		VisitLink(app.Group("/s"), ctx)
+		result := PerformRequest(app, "GET", "/s/xxxxxxxxxx")
+		assert.Equal(t, http.StatusNotFound, result.Code)
+	})
+	t.Run("invalid request", func(t *testing.T) {
+		app, _, ctx := NewApiTest()
+		VisitLink(app.Group("/s"), ctx)
+		result := PerformRequestWithBody(app, "POST", "/s/xxxxxxxxxx", `{"password": 123}`)
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+}
+
+func TestGetShared(t *testing.T) {
+	t.Run("no visitor session", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		GetShared(router.Group("", Visitor(ctx)), ctx)
+		result := PerformRequest(app, "GET", "/api/v1/shared")
+		assert.Equal(t, http.StatusUnauthorized, result.Code)
+	})
+}
+
+func TestGetSharedPhotos(t *testing.T) {
+	t.Run("no visitor session", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		GetSharedPhotos(router.Group("", Visitor(ctx)), ctx)
+		result := PerformRequest(app, "GET", "/api/v1/shared/photos?count=10")
+		assert.Equal(t, http.StatusUnauthorized, result.Code)
+	})
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSharePhoto(t *testing.T) {
+	t.Run("not existing photo", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		SharePhoto(router, ctx)
+		result := PerformRequestWithBody(app, "PUT", "/api/v1/photos/xxx/share", `{"Visibility": "private"}`)
+		assert.Equal(t, http.StatusNotFound, result.Code)
+	})
+	t.Run("invalid visibility", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		SharePhoto(router, ctx)
+		result := PerformRequestWithBody(app, "PUT", "/api/v1/photos/654/share", `{"Visibility": "public"}`)
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+	t.Run("unknown user", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		SharePhoto(router, ctx)
+		result := PerformRequestWithBody(app, "PUT", "/api/v1/photos/654/share", `{"Visibility": "shared", "Users": ["nobody"]}`)
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+}
+
+func TestShareAlbum(t *testing.T) {
+	t.Run("not existing album", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		ShareAlbum(router, ctx)
+		result := PerformRequestWithBody(app, "PUT", "/api/v1/albums/xxx/share", `{"Visibility": "everyone"}`)
+		assert.Equal(t, http.StatusNotFound, result.Code)
+	})
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// GET /api/v1/tokens
+func GetTokens(router *gin.RouterGroup, conf *config.Config) {
+	router.GET("/tokens", func(c *gin.Context) {
+		results, err := query.New(conf.Db()).Tokens(currentUserUUID(c))
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		c.JSON(http.StatusOK, results)
+	})
+}
+
+// POST /api/v1/tokens
+//
+// Creates a new API token for the current user, the secret is only returned once.
+func CreateToken(router *gin.RouterGroup, conf *config.Config) {
+	router.POST("/tokens", func(c *gin.Context) {
+		var f form.Token
+
+		if err := c.BindJSON(&f); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		scopes, err := acl.ParseScopes(strings.Join(f.Scopes, ","))
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		var expiresAt *time.Time
+
+		if f.ExpiresDays > 0 {
+			t := time.Now().UTC().AddDate(0, 0, f.ExpiresDays)
+			expiresAt = &t
+		}
+
+		m, secret, err := entity.NewToken(currentUserUUID(c), f.Name, scopes, expiresAt)
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		if err := conf.Db().Create(m).Error; err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"token": m, "secret": secret})
+	})
+}
+
+// DELETE /api/v1/tokens/:uuid
+//
+// Parameters:
+//   uuid: string Token UUID
+func DeleteToken(router *gin.RouterGroup, conf *config.Config) {
+	router.DELETE("/tokens/:uuid", func(c *gin.Context) {
+		tokenUUID := c.Param("uuid")
+
+		found, err := query.New(conf.Db()).DeleteToken(currentUserUUID(c), tokenUUID)
+
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": txt.UcFirst(err.Error())})
+			return
+		}
+
+		if !found {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrTokenNotFound)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"status": "ok", "uuid": tokenUUID})
+	})
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateToken(t *testing.T) {
+	t.Run("invalid scope", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		CreateToken(router.Group("", Auth(ctx, acl.RoleGuest, acl.ScopeAdmin)), ctx)
+		result := PerformRequestWithBody(app, "POST", "/api/v1/tokens", `{"Name": "Backup", "Scopes": ["everything"]}`)
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+	t.Run("no scope", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		CreateToken(router.Group("", Auth(ctx, acl.RoleGuest, acl.ScopeAdmin)), ctx)
+		result := PerformRequestWithBody(app, "POST", "/api/v1/tokens", `{"Name": "Backup"}`)
+		assert.Equal(t, http.StatusBadRequest, result.Code)
+	})
+}
+
+func TestDeleteToken(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		app, router, ctx := NewApiTest()
+		DeleteToken(router.Group("", Auth(ctx, acl.RoleGuest, acl.ScopeAdmin)), ctx)
+		result := PerformRequest(app, "DELETE", "/api/v1/tokens/t000000000000000")
+		assert.Equal(t, http.StatusNotFound, result.Code)
+	})
+}
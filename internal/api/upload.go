@@ -24,11 +24,6 @@ func Upload(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		start := time.Now()
 		subPath := c.Param("path")
 
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/session"
+	"github.com/photoprism/photoprism/internal/thumb"
+)
+
+// watermarkRequired returns true if images must be watermarked for the current visitor,
+// which is the case for share link visitors and public instances without user session.
+func watermarkRequired(c *gin.Context, conf *config.Config) bool {
+	if !conf.Watermark() {
+		return false
+	}
+
+	if CurrentLink(c) != nil {
+		return true
+	}
+
+	if m, ok := session.New(conf.Db()).Get(sessionToken(c)); ok && !m.Visitor() {
+		return false
+	}
+
+	if _, ok := visitorLink(c, conf); ok {
+		return true
+	}
+
+	return conf.Public() || c.Query("t") != ""
+}
+
+// watermarkTag returns an entity tag variant for watermarked files, or an empty string if not required.
+func watermarkTag(c *gin.Context, conf *config.Config) string {
+	if !watermarkRequired(c, conf) {
+		return ""
+	}
+
+	return "w" + thumb.WatermarkKey()
+}
+
+// watermarkFile returns the file name of the image that should be sent to the current visitor.
+func watermarkFile(c *gin.Context, conf *config.Config, fileName, fileHash string) (string, error) {
+	if !watermarkRequired(c, conf) {
+		return fileName, nil
+	}
+
+	return thumb.Watermarked(fileName, fileHash, conf.ThumbnailsPath())
+}
+
+// watermarkShareName returns the share file name with the extension of the file actually sent,
+// since watermarked copies of other image formats are saved as JPEG.
+func watermarkShareName(shareName, fileName string) string {
+	if ext := filepath.Ext(fileName); ext != filepath.Ext(shareName) {
+		return strings.TrimSuffix(shareName, filepath.Ext(shareName)) + ext
+	}
+
+	return shareName
+}
@@ -3,13 +3,17 @@ package api
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
+	"github.com/photoprism/photoprism/internal/acl"
 	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/query"
 	"github.com/photoprism/photoprism/internal/session"
 	"github.com/photoprism/photoprism/pkg/rnd"
 )
@@ -28,10 +32,17 @@ type clientInfo struct {
 	Version      string `json:"version"`
 }
 
+// wsClient contains the user and session token of an authenticated connection.
+type wsClient struct {
+	user  *entity.User
+	token string
+}
+
+// wsAuth contains the clients of authenticated connections.
 var wsAuth = struct {
-	authenticated map[string]bool
-	mutex         sync.RWMutex
-}{authenticated: make(map[string]bool)}
+	client map[string]wsClient
+	mutex  sync.RWMutex
+}{client: make(map[string]wsClient)}
 
 func wsReader(ws *websocket.Conn, writeMutex *sync.Mutex, connId string, conf *config.Config) {
 	defer ws.Close()
@@ -54,11 +65,19 @@ func wsReader(ws *websocket.Conn, writeMutex *sync.Mutex, connId string, conf *c
 		if err := json.Unmarshal(m, &info); err != nil {
 			log.Error(err)
 		} else {
-			if session.Exists(info.SessionToken) {
+			// Share link visitors must not receive events of the whole library.
+			if m, ok := session.New(conf.Db()).Get(info.SessionToken); ok && !m.Visitor() {
+				user, err := query.New(conf.Db()).UserByUUID(m.UserUUID)
+
+				if err != nil {
+					log.Errorf("websocket: %s", err)
+					continue
+				}
+
 				log.Debug("websocket: authenticated")
 
 				wsAuth.mutex.Lock()
-				wsAuth.authenticated[connId] = true
+				wsAuth.client[connId] = wsClient{user: &user, token: info.SessionToken}
 				wsAuth.mutex.Unlock()
 
 				writeMutex.Lock()
@@ -73,9 +92,9 @@ func wsReader(ws *websocket.Conn, writeMutex *sync.Mutex, connId string, conf *c
 	}
 }
 
-func wsWriter(ws *websocket.Conn, writeMutex *sync.Mutex, connId string) {
+func wsWriter(ws *websocket.Conn, writeMutex *sync.Mutex, connId string, conf *config.Config) {
 	pingTicker := time.NewTicker(15 * time.Second)
-	s := event.Subscribe("log.*", "notify.*", "index.*", "upload.*", "import.*", "config.*", "count.*", "photos.*", "albums.*", "labels.*", "sync.*")
+	s := event.Subscribe("log.*", "notify.*", "index.*", "upload.*", "import.*", "config.*", "count.*", "photos.*", "albums.*", "labels.*", "sync.*", "link.*", "comments.*", "events.*")
 
 	defer func() {
 		pingTicker.Stop()
@@ -83,23 +102,39 @@ func wsWriter(ws *websocket.Conn, writeMutex *sync.Mutex, connId string) {
 		ws.Close()
 
 		wsAuth.mutex.Lock()
-		wsAuth.authenticated[connId] = false
+		delete(wsAuth.client, connId)
 		wsAuth.mutex.Unlock()
 	}()
 
 	for {
 		select {
 		case <-pingTicker.C:
+			// Connections are closed when the session was deleted, e.g. after logging out everywhere.
+			if !wsRefresh(conf, connId) {
+				return
+			}
+
 			ws.SetWriteDeadline(time.Now().Add(30 * time.Second))
 			if err := ws.WriteMessage(websocket.PingMessage, []byte{}); err != nil {
 				return
 			}
 		case msg := <-s.Receiver:
 			wsAuth.mutex.RLock()
-			auth := wsAuth.authenticated[connId]
+			user := wsAuth.client[connId].user
 			wsAuth.mutex.RUnlock()
 
-			if auth {
+			// Events with owner are only sent to users who may edit the item, like the owner of a share link.
+			if owner, ok := msg.Fields["owner"].(string); ok && !canEdit(user, owner) {
+				continue
+			}
+
+			if user != nil {
+				msg, visible := wsVisible(conf, user, msg)
+
+				if !visible {
+					continue
+				}
+
 				writeMutex.Lock()
 				ws.SetWriteDeadline(time.Now().Add(30 * time.Second))
 
@@ -114,6 +149,122 @@ func wsWriter(ws *websocket.Conn, writeMutex *sync.Mutex, connId string) {
 	}
 }
 
+// wsRefresh reloads the user of an authenticated connection and returns false if its session
+// or user no longer exists. Connections without session token are not checked.
+func wsRefresh(conf *config.Config, connId string) bool {
+	wsAuth.mutex.RLock()
+	client, ok := wsAuth.client[connId]
+	wsAuth.mutex.RUnlock()
+
+	if !ok || client.token == "" {
+		return true
+	}
+
+	m, ok := session.New(conf.Db()).Get(client.token)
+
+	if !ok || m.Visitor() {
+		log.Debug("websocket: session deleted")
+		return false
+	}
+
+	user, err := query.New(conf.Db()).UserByUUID(m.UserUUID)
+
+	if err != nil {
+		log.Debugf("websocket: %s", err)
+		return false
+	}
+
+	wsAuth.mutex.Lock()
+	wsAuth.client[connId] = wsClient{user: &user, token: client.token}
+	wsAuth.mutex.Unlock()
+
+	return true
+}
+
+// wsVisible returns the message with only the photos and albums visible to the user, or false if none remain.
+// Counts are only sent to admins, since they may include private photos of other users.
+func wsVisible(conf *config.Config, user *entity.User, msg event.Message) (event.Message, bool) {
+	if user.Role == acl.RoleAdmin {
+		return msg, true
+	}
+
+	var visible func(uuid string) bool
+
+	q := query.New(conf.Db()).WithUser(user)
+
+	switch strings.SplitN(msg.Name, ".", 2)[0] {
+	case "count":
+		return msg, false
+	case "photos":
+		visible = q.PhotoVisible
+	case "albums":
+		visible = q.AlbumVisible
+	default:
+		return msg, true
+	}
+
+	entities, ok := wsEntities(msg.Fields["entities"])
+
+	if !ok {
+		return msg, false
+	}
+
+	var result []interface{}
+
+	for _, e := range entities {
+		if uuid := wsEntityUUID(e); uuid != "" && visible(uuid) {
+			result = append(result, e)
+		}
+	}
+
+	if len(result) == 0 {
+		return msg, false
+	}
+
+	fields := event.Data{}
+
+	for k, v := range msg.Fields {
+		fields[k] = v
+	}
+
+	fields["entities"] = result
+
+	return event.Message{Name: msg.Name, Fields: fields}, true
+}
+
+// wsEntities returns the entities of an event as list of UUIDs and JSON objects.
+func wsEntities(entities interface{}) (result []interface{}, ok bool) {
+	data, err := json.Marshal(entities)
+
+	if err != nil {
+		log.Errorf("websocket: %s", err)
+		return nil, false
+	}
+
+	if err := json.Unmarshal(data, &result); err != nil {
+		log.Errorf("websocket: %s", err)
+		return nil, false
+	}
+
+	return result, true
+}
+
+// wsEntityUUID returns the UUID of an event entity, which is either a UUID or an object with a photo or album UUID.
+func wsEntityUUID(e interface{}) string {
+	switch v := e.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		for _, key := range []string{"PhotoUUID", "AlbumUUID"} {
+			if uuid, ok := v[key].(string); ok && uuid != "" {
+				return uuid
+			}
+		}
+	}
+
+	return ""
+}
+
 // GET /api/v1/ws
 func Websocket(router *gin.RouterGroup, conf *config.Config) {
 	if router == nil {
@@ -143,14 +294,16 @@ func Websocket(router *gin.RouterGroup, conf *config.Config) {
 		connId := rnd.UUID()
 
 		if conf.Public() {
+			user := entity.Admin
+
 			wsAuth.mutex.Lock()
-			wsAuth.authenticated[connId] = true
+			wsAuth.client[connId] = wsClient{user: &user}
 			wsAuth.mutex.Unlock()
 		}
 
 		log.Debug("websocket: connected")
 
-		go wsWriter(ws, &writeMutex, connId)
+		go wsWriter(ws, &writeMutex, connId, conf)
 
 		wsReader(ws, &writeMutex, connId, conf)
 	})
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWsEntities(t *testing.T) {
+	t.Run("photos", func(t *testing.T) {
+		entities, ok := wsEntities([]query.PhotoResult{{PhotoUUID: "pt9jtdre2lvl0yh7"}})
+
+		assert.True(t, ok)
+		assert.Len(t, entities, 1)
+		assert.Equal(t, "pt9jtdre2lvl0yh7", wsEntityUUID(entities[0]))
+	})
+	t.Run("albums", func(t *testing.T) {
+		entities, ok := wsEntities([]entity.Album{{AlbumUUID: "at9lxuqxpogaaba7"}})
+
+		assert.True(t, ok)
+		assert.Equal(t, "at9lxuqxpogaaba7", wsEntityUUID(entities[0]))
+	})
+	t.Run("uuids", func(t *testing.T) {
+		entities, ok := wsEntities([]string{"pt9jtdre2lvl0yh7", "pt9jtdre2lvl0yh8"})
+
+		assert.True(t, ok)
+		assert.Len(t, entities, 2)
+		assert.Equal(t, "pt9jtdre2lvl0yh8", wsEntityUUID(entities[1]))
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, ok := wsEntities(map[string]string{"uuid": "pt9jtdre2lvl0yh7"})
+
+		assert.False(t, ok)
+	})
+}
@@ -12,7 +12,6 @@ import (
 
 	"github.com/photoprism/photoprism/internal/config"
 	"github.com/photoprism/photoprism/internal/form"
-	"github.com/photoprism/photoprism/internal/query"
 	"github.com/photoprism/photoprism/pkg/fs"
 	"github.com/photoprism/photoprism/pkg/rnd"
 	"github.com/photoprism/photoprism/pkg/txt"
@@ -23,11 +22,6 @@ import (
 // POST /api/v1/zip
 func CreateZip(router *gin.RouterGroup, conf *config.Config) {
 	router.POST("/zip", func(c *gin.Context) {
-		if Unauthorized(c, conf) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrUnauthorized)
-			return
-		}
-
 		var f form.Selection
 		start := time.Now()
 
@@ -42,7 +36,7 @@ func CreateZip(router *gin.RouterGroup, conf *config.Config) {
 			return
 		}
 
-		q := query.New(conf.Db())
+		q := userQuery(c, conf)
 		files, err := q.FilesByUUID(f.Photos, 1000, 0)
 
 		if err != nil {
@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"context"
+	"path"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/urfave/cli"
+)
+
+// BlurHashCommand is used to register the blurhash cli command
+var BlurHashCommand = cli.Command{
+	Name:  "blurhash",
+	Usage: "Computes BlurHash placeholders for indexed images",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "force, f",
+			Usage: "re-compute existing placeholders",
+		},
+	},
+	Action: blurHashAction,
+}
+
+// blurHashAction backfills BlurHash placeholders for existing files
+func blurHashAction(ctx *cli.Context) error {
+	start := time.Now()
+
+	conf := config.NewConfig(ctx)
+
+	if err := conf.CreateDirectories(); err != nil {
+		return err
+	}
+
+	cctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := conf.Init(cctx); err != nil {
+		return err
+	}
+
+	conf.MigrateDb()
+
+	db := conf.Db()
+	q := query.New(db)
+	force := ctx.Bool("force")
+
+	var lastID uint
+	var updated int
+
+	for {
+		files, err := q.BlurHashFiles(lastID, 500, force)
+
+		if err != nil {
+			return err
+		}
+
+		if len(files) == 0 {
+			break
+		}
+
+		for _, file := range files {
+			lastID = file.ID
+
+			mf, err := photoprism.NewMediaFile(path.Join(conf.OriginalsPath(), file.FileName))
+
+			if err != nil {
+				log.Errorf("blurhash: %s", err.Error())
+				continue
+			}
+
+			hash, err := mf.BlurHash(conf.ThumbnailsPath())
+
+			if err != nil {
+				log.Errorf("blurhash: %s", err.Error())
+				continue
+			}
+
+			if err := db.Model(&entity.File{}).Where("id = ?", file.ID).UpdateColumn("file_blur_hash", hash).Error; err != nil {
+				log.Errorf("blurhash: %s", err.Error())
+				continue
+			}
+
+			updated++
+		}
+	}
+
+	elapsed := time.Since(start)
+
+	log.Infof("computed %d placeholders in %s", updated, elapsed)
+
+	conf.Shutdown()
+
+	return nil
+}
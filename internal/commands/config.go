@@ -74,6 +74,8 @@ func configAction(ctx *cli.Context) error {
 	fmt.Printf("darktable-bin         %s\n", conf.DarktableBin())
 	fmt.Printf("exiftool-bin          %s\n", conf.ExifToolBin())
 	fmt.Printf("heifconvert-bin       %s\n", conf.HeifConvertBin())
+	fmt.Printf("webp-bin              %s\n", conf.WebpBin())
+	fmt.Printf("avif-bin              %s\n", conf.AvifBin())
 
 	fmt.Printf("detect-nsfw           %t\n", conf.DetectNSFW())
 	fmt.Printf("upload-nsfw           %t\n", conf.UploadNSFW())
@@ -82,6 +84,13 @@ func configAction(ctx *cli.Context) error {
 	fmt.Printf("thumb-size            %d\n", conf.ThumbSize())
 	fmt.Printf("thumb-limit           %d\n", conf.ThumbLimit())
 	fmt.Printf("thumb-filter          %s\n", conf.ThumbFilter())
+	fmt.Printf("thumb-lazy            %t\n", conf.ThumbLazy())
+	fmt.Printf("thumb-quota           %d\n", conf.ThumbQuota()/(1024*1024))
+	fmt.Printf("watermark-text        %s\n", conf.WatermarkText())
+	fmt.Printf("watermark-image       %s\n", conf.WatermarkImage())
+	fmt.Printf("watermark-position    %s\n", conf.WatermarkPosition())
+	fmt.Printf("watermark-opacity     %d\n", conf.WatermarkOpacity())
+	fmt.Printf("watermark-min-size    %d\n", conf.WatermarkMinSize())
 
 	return nil
 }
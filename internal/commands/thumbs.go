@@ -1,10 +1,12 @@
 package commands
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/photoprism/photoprism/internal/config"
 	"github.com/photoprism/photoprism/internal/service"
+	"github.com/photoprism/photoprism/internal/thumb"
 	"github.com/urfave/cli"
 )
 
@@ -17,6 +19,14 @@ var ThumbsCommand = cli.Command{
 			Name:  "force, f",
 			Usage: "re-create existing thumbnails",
 		},
+		cli.BoolFlag{
+			Name:  "usage, u",
+			Usage: "show thumbnail cache usage",
+		},
+		cli.BoolFlag{
+			Name:  "cleanup, c",
+			Usage: "remove thumbnails rendered with outdated crop methods",
+		},
 	},
 	Action: thumbsAction,
 }
@@ -32,6 +42,22 @@ func thumbsAction(ctx *cli.Context) error {
 		return err
 	}
 
+	if ctx.Bool("usage") {
+		return thumbsUsage(conf)
+	}
+
+	if ctx.Bool("cleanup") {
+		removed, freed, err := thumb.RemoveLegacy(conf.ThumbnailsPath())
+
+		if err != nil {
+			return err
+		}
+
+		log.Infof("removed %d outdated thumbnails (%.1f MB)", removed, float64(freed)/(1024*1024))
+
+		return nil
+	}
+
 	log.Infof("creating thumbnails in \"%s\"", conf.ThumbnailsPath())
 
 	rs := service.Resample()
@@ -47,3 +73,38 @@ func thumbsAction(ctx *cli.Context) error {
 
 	return nil
 }
+
+// thumbsUsage prints the thumbnail cache usage grouped by type
+func thumbsUsage(conf *config.Config) error {
+	files, err := thumb.Cached(conf.ThumbnailsPath())
+
+	if err != nil {
+		return err
+	}
+
+	mb := func(size int64) float64 {
+		return float64(size) / (1024 * 1024)
+	}
+
+	fmt.Printf("TYPE          FILES      SIZE (MB)  ON-DEMAND\n")
+
+	for _, u := range files.Usage() {
+		onDemand := u.TypeName == thumb.LegacyType
+
+		if t, ok := thumb.Types[u.TypeName]; ok {
+			onDemand = t.SkipPreRender()
+		}
+
+		fmt.Printf("%-12s  %-9d  %-9.1f  %t\n", u.TypeName, u.Files, mb(u.Size), onDemand)
+	}
+
+	fmt.Printf("\ntotal         %-9d  %.1f\n", len(files), mb(files.Size()))
+
+	if quota := conf.ThumbQuota(); quota > 0 {
+		fmt.Printf("quota                    %.1f (%.0f%% used)\n", mb(quota), float64(files.Size())*100/float64(quota))
+	} else {
+		fmt.Printf("quota                    unlimited\n")
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/urfave/cli"
+)
+
+// TokensCommand is used to register the tokens cli command
+var TokensCommand = cli.Command{
+	Name:  "tokens",
+	Usage: "Manages API tokens for scripts and other applications",
+	Subcommands: []cli.Command{
+		{
+			Name:      "ls",
+			Usage:     "Lists the API tokens of a user",
+			ArgsUsage: "[user name]",
+			Action:    tokensListAction,
+		},
+		{
+			Name:      "add",
+			Usage:     "Adds a new API token",
+			ArgsUsage: "[user name] [token name]",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "scope, s",
+					Usage: "comma separated list of scopes (photos:read, photos:write, upload, import, albums or admin)",
+					Value: string(acl.ScopePhotosRead),
+				},
+				cli.IntFlag{
+					Name:  "expires, e",
+					Usage: "number of days until the token expires, 0 for never",
+				},
+			},
+			Action: tokensAddAction,
+		},
+		{
+			Name:      "rm",
+			Usage:     "Revokes an API token",
+			ArgsUsage: "[user name] [token uuid]",
+			Action:    tokensRemoveAction,
+		},
+	},
+}
+
+// tokensListAction lists the API tokens of a user
+func tokensListAction(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("user name required")
+	}
+
+	conf, err := usersConfig(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	defer conf.Shutdown()
+
+	q := query.New(conf.Db())
+
+	user, err := q.UserByName(ctx.Args().First())
+
+	if err != nil {
+		return fmt.Errorf("user \"%s\" not found", ctx.Args().First())
+	}
+
+	tokens, err := q.Tokens(user.UserUUID)
+
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "UUID\tNAME\tSCOPES\tEXPIRES\tLAST USED")
+
+	for _, t := range tokens {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", t.TokenUUID, t.TokenName, t.TokenScopes, tokensTime(t.ExpiresAt, "never"), tokensTime(t.LastUsed, "never"))
+	}
+
+	return w.Flush()
+}
+
+// tokensTime formats an optional time, or returns the default if it is nil.
+func tokensTime(t *time.Time, def string) string {
+	if t == nil {
+		return def
+	}
+
+	return t.Format("2006-01-02 15:04:05")
+}
+
+// tokensAddAction adds a new API token and shows the secret once
+func tokensAddAction(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return fmt.Errorf("user name and token name required")
+	}
+
+	scopes, err := acl.ParseScopes(ctx.String("scope"))
+
+	if err != nil {
+		return err
+	}
+
+	var expiresAt *time.Time
+
+	if days := ctx.Int("expires"); days > 0 {
+		t := time.Now().UTC().AddDate(0, 0, days)
+		expiresAt = &t
+	}
+
+	conf, err := usersConfig(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	defer conf.Shutdown()
+
+	user, err := query.New(conf.Db()).UserByName(ctx.Args().First())
+
+	if err != nil {
+		return fmt.Errorf("user \"%s\" not found", ctx.Args().First())
+	}
+
+	token, secret, err := entity.NewToken(user.UserUUID, ctx.Args().Get(1), scopes, expiresAt)
+
+	if err != nil {
+		return err
+	}
+
+	if err := conf.Db().Create(token).Error; err != nil {
+		return err
+	}
+
+	log.Infof("added token \"%s\" for \"%s\" with scopes %s", token.TokenName, user.UserName, token.TokenScopes)
+
+	fmt.Printf("token: %s\n", secret)
+
+	return nil
+}
+
+// tokensRemoveAction revokes an API token
+func tokensRemoveAction(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return fmt.Errorf("user name and token uuid required")
+	}
+
+	conf, err := usersConfig(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	defer conf.Shutdown()
+
+	q := query.New(conf.Db())
+
+	user, err := q.UserByName(ctx.Args().First())
+
+	if err != nil {
+		return fmt.Errorf("user \"%s\" not found", ctx.Args().First())
+	}
+
+	found, err := q.DeleteToken(user.UserUUID, ctx.Args().Get(1))
+
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return fmt.Errorf("token \"%s\" not found", ctx.Args().Get(1))
+	}
+
+	log.Infof("revoked token %s of \"%s\"", ctx.Args().Get(1), user.UserName)
+
+	return nil
+}
@@ -0,0 +1,305 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/session"
+	"github.com/photoprism/photoprism/pkg/rnd"
+	"github.com/urfave/cli"
+)
+
+// UsersCommand is used to register the users cli command
+var UsersCommand = cli.Command{
+	Name:  "users",
+	Usage: "Manages users who can log in",
+	Subcommands: []cli.Command{
+		{
+			Name:   "ls",
+			Usage:  "Lists all users",
+			Action: usersListAction,
+		},
+		{
+			Name:      "add",
+			Usage:     "Adds a new user",
+			ArgsUsage: "[name]",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "role, r",
+					Usage: "user role (admin, editor, viewer or guest)",
+					Value: string(acl.RoleViewer),
+				},
+				cli.StringFlag{
+					Name:  "password, p",
+					Usage: "initial password, a random password is generated if empty",
+				},
+				cli.StringFlag{
+					Name:  "email, e",
+					Usage: "email address",
+				},
+			},
+			Action: usersAddAction,
+		},
+		{
+			Name:      "rm",
+			Usage:     "Removes a user",
+			ArgsUsage: "[name]",
+			Action:    usersRemoveAction,
+		},
+		{
+			Name:      "reset",
+			Usage:     "Resets the password and optionally the role of a user",
+			ArgsUsage: "[name]",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "role, r",
+					Usage: "new user role (admin, editor, viewer or guest)",
+				},
+				cli.StringFlag{
+					Name:  "password, p",
+					Usage: "new password, a random password is generated if empty",
+				},
+			},
+			Action: usersResetAction,
+		},
+	},
+}
+
+// usersConfig initializes the config and database used by the users subcommands.
+func usersConfig(ctx *cli.Context) (*config.Config, error) {
+	conf := config.NewConfig(ctx)
+
+	cctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := conf.Init(cctx); err != nil {
+		return conf, err
+	}
+
+	conf.MigrateDb()
+
+	return conf, nil
+}
+
+// usersPassword returns the password flag value or a new random password that is shown once.
+func usersPassword(ctx *cli.Context) (password string, generated bool) {
+	if password = ctx.String("password"); password != "" {
+		return password, false
+	}
+
+	return rnd.Token(10), true
+}
+
+// usersListAction lists all users
+func usersListAction(ctx *cli.Context) error {
+	conf, err := usersConfig(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	defer conf.Shutdown()
+
+	users, err := query.New(conf.Db()).Users()
+
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "NAME\tROLE\tEMAIL\tCREATED")
+
+	for _, u := range users {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", u.UserName, u.Role, u.Email, u.CreatedAt.Format("2006-01-02 15:04:05"))
+	}
+
+	return w.Flush()
+}
+
+// usersAddAction adds a new user
+func usersAddAction(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("user name required")
+	}
+
+	role, err := acl.ParseRole(ctx.String("role"))
+
+	if err != nil {
+		return err
+	}
+
+	user, err := entity.NewUser(ctx.Args().First(), role)
+
+	if err != nil {
+		return err
+	}
+
+	password, generated := usersPassword(ctx)
+
+	if err := user.SetPassword(password); err != nil {
+		return err
+	}
+
+	user.Email = ctx.String("email")
+
+	conf, err := usersConfig(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	defer conf.Shutdown()
+
+	if _, err := query.New(conf.Db()).UserByName(user.UserName); err == nil {
+		return fmt.Errorf("user \"%s\" already exists", user.UserName)
+	}
+
+	if err := conf.Db().Create(user).Error; err != nil {
+		return err
+	}
+
+	log.Infof("added %s \"%s\"", user.Role, user.UserName)
+
+	if generated {
+		fmt.Printf("password: %s\n", password)
+	}
+
+	return nil
+}
+
+// usersRemoveAction removes a user, the last admin can't be removed
+func usersRemoveAction(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("user name required")
+	}
+
+	conf, err := usersConfig(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	defer conf.Shutdown()
+
+	db := conf.Db()
+
+	user, err := query.New(db).UserByName(ctx.Args().First())
+
+	if err != nil {
+		return fmt.Errorf("user \"%s\" not found", ctx.Args().First())
+	}
+
+	if last, err := lastAdmin(db, user); err != nil {
+		return err
+	} else if last {
+		return fmt.Errorf("can't remove the last admin")
+	}
+
+	// Delete permanently, so that the name can be used again.
+	if err := db.Unscoped().Delete(&user).Error; err != nil {
+		return err
+	}
+
+	if err := session.New(db).DeleteUser(user.UserUUID); err != nil {
+		return err
+	}
+
+	if err := db.Where("user_uuid = ?", user.UserUUID).Delete(&entity.Token{}).Error; err != nil {
+		return err
+	}
+
+	log.Infof("removed user \"%s\"", user.UserName)
+
+	return nil
+}
+
+// usersResetAction sets a new password and role
+func usersResetAction(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("user name required")
+	}
+
+	conf, err := usersConfig(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	defer conf.Shutdown()
+
+	db := conf.Db()
+
+	user, err := query.New(db).UserByName(ctx.Args().First())
+
+	if err != nil {
+		return fmt.Errorf("user \"%s\" not found", ctx.Args().First())
+	}
+
+	if ctx.String("role") != "" {
+		role, err := acl.ParseRole(ctx.String("role"))
+
+		if err != nil {
+			return err
+		}
+
+		if role != acl.RoleAdmin {
+			if last, err := lastAdmin(db, user); err != nil {
+				return err
+			} else if last {
+				return fmt.Errorf("can't change the role of the last admin")
+			}
+		}
+
+		user.Role = role
+	}
+
+	password, generated := usersPassword(ctx)
+
+	if err := user.SetPassword(password); err != nil {
+		return err
+	}
+
+	if err := db.Save(&user).Error; err != nil {
+		return err
+	}
+
+	// Log out everywhere and revoke API tokens, the old password may have been compromised.
+	if err := session.New(db).DeleteUser(user.UserUUID); err != nil {
+		return err
+	}
+
+	if err := db.Where("user_uuid = ?", user.UserUUID).Delete(&entity.Token{}).Error; err != nil {
+		return err
+	}
+
+	log.Infof("updated password of %s \"%s\"", user.Role, user.UserName)
+
+	if generated {
+		fmt.Printf("password: %s\n", password)
+	}
+
+	return nil
+}
+
+// lastAdmin returns true if the user is the only admin.
+func lastAdmin(db *gorm.DB, user entity.User) (bool, error) {
+	if user.Role != acl.RoleAdmin {
+		return false, nil
+	}
+
+	var admins int
+
+	if err := db.Model(&entity.User{}).Where("role = ?", acl.RoleAdmin).Count(&admins).Error; err != nil {
+		return false, err
+	}
+
+	return admins < 2, nil
+}
@@ -68,6 +68,13 @@ func NewConfig(ctx *cli.Context) *Config {
 	thumb.PreRenderSize = c.ThumbSize()
 	thumb.MaxRenderSize = c.ThumbLimit()
 	thumb.Filter = c.ThumbFilter()
+	thumb.WebpBin = c.WebpBin()
+	thumb.AvifBin = c.AvifBin()
+	thumb.WatermarkText = c.WatermarkText()
+	thumb.WatermarkImage = c.WatermarkImage()
+	thumb.WatermarkPosition = c.WatermarkPosition()
+	thumb.WatermarkOpacity = float64(c.WatermarkOpacity()) / 100
+	thumb.WatermarkMinSize = c.WatermarkMinSize()
 
 	return c
 }
@@ -201,6 +208,7 @@ func (c *Config) Shutdown() {
 	mutex.Worker.Cancel()
 	mutex.Share.Cancel()
 	mutex.Sync.Cancel()
+	mutex.Thumbs.Cancel()
 
 	if err := c.CloseDb(); err != nil {
 		log.Errorf("could not close database connection: %s", err)
@@ -248,6 +256,10 @@ func (c *Config) ThumbQuality() int {
 
 // ThumbSize returns the pre-rendered thumbnail size limit in pixels (720-3840).
 func (c *Config) ThumbSize() int {
+	if c.ThumbLazy() {
+		return 720
+	}
+
 	if c.config.ThumbSize > 3840 {
 		return 3840
 	}
@@ -288,6 +300,59 @@ func (c *Config) ThumbFilter() thumb.ResampleFilter {
 	}
 }
 
+// ThumbLazy returns true if thumbnails larger than 720 pixels should be rendered on demand only.
+func (c *Config) ThumbLazy() bool {
+	return c.config.ThumbLazy
+}
+
+// ThumbQuota returns the thumbnail cache quota in bytes (0 for unlimited).
+func (c *Config) ThumbQuota() int64 {
+	if c.config.ThumbQuota <= 0 {
+		return 0
+	}
+
+	return int64(c.config.ThumbQuota) * 1024 * 1024
+}
+
+// WatermarkText returns the watermark text for shared and public downloads.
+func (c *Config) WatermarkText() string {
+	return c.config.WatermarkText
+}
+
+// WatermarkPosition returns the watermark position (top-left, top-right, bottom-left, bottom-right or center).
+func (c *Config) WatermarkPosition() string {
+	position := strings.ToLower(c.config.WatermarkPosition)
+
+	if _, ok := thumb.WatermarkAnchors[position]; !ok {
+		return "bottom-right"
+	}
+
+	return position
+}
+
+// WatermarkOpacity returns the watermark opacity in percent (1-100).
+func (c *Config) WatermarkOpacity() int {
+	if c.config.WatermarkOpacity <= 0 || c.config.WatermarkOpacity > 100 {
+		return 50
+	}
+
+	return c.config.WatermarkOpacity
+}
+
+// WatermarkMinSize returns the minimum image size in pixels for watermarks.
+func (c *Config) WatermarkMinSize() int {
+	if c.config.WatermarkMinSize < 0 {
+		return 0
+	}
+
+	return c.config.WatermarkMinSize
+}
+
+// Watermark returns true if shared and public downloads should be watermarked.
+func (c *Config) Watermark() bool {
+	return c.WatermarkText() != "" || c.WatermarkImage() != ""
+}
+
 // GeoCodingApi returns the preferred geo coding api (none, osm or places).
 func (c *Config) GeoCodingApi() string {
 	switch c.config.GeoCodingApi {
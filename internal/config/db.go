@@ -75,16 +75,23 @@ func (c *Config) MigrateDb() {
 		&entity.Country{},
 		&entity.Album{},
 		&entity.PhotoAlbum{},
+		&entity.Folder{},
 		&entity.Label{},
 		&entity.Category{},
 		&entity.PhotoLabel{},
 		&entity.Keyword{},
 		&entity.PhotoKeyword{},
 		&entity.Link{},
+		&entity.User{},
+		&entity.UserShare{},
+		&entity.Session{},
+		&entity.Token{},
+		&entity.Comment{},
 	)
 
 	entity.CreateUnknownPlace(db)
 	entity.CreateUnknownCountry(db)
+	entity.CreateDefaultUser(db, c.AdminPassword())
 }
 
 // DropTables drops all tables in the currently configured database (be careful!).
@@ -112,12 +119,18 @@ func (c *Config) DropTables() {
 		&entity.Country{},
 		&entity.Album{},
 		&entity.PhotoAlbum{},
+		&entity.Folder{},
 		&entity.Label{},
 		&entity.Category{},
 		&entity.PhotoLabel{},
 		&entity.Keyword{},
 		&entity.PhotoKeyword{},
 		&entity.Link{},
+		&entity.User{},
+		&entity.UserShare{},
+		&entity.Session{},
+		&entity.Token{},
+		&entity.Comment{},
 	)
 
 	log.SetLevel(logLevel)
@@ -151,6 +151,16 @@ func (c *Config) ExifToolBin() string {
 	return findExecutable(c.config.ExifToolBin, "exiftool")
 }
 
+// WebpBin returns the cwebp binary file name.
+func (c *Config) WebpBin() string {
+	return findExecutable(c.config.WebpBin, "cwebp")
+}
+
+// AvifBin returns the avifenc binary file name.
+func (c *Config) AvifBin() string {
+	return findExecutable(c.config.AvifBin, "avifenc")
+}
+
 // TempPath returns a temporary directory name for uploads and downloads.
 func (c *Config) TempPath() string {
 	if c.config.TempPath == "" {
@@ -170,6 +180,15 @@ func (c *Config) ThumbnailsPath() string {
 	return c.CachePath() + "/thumbnails"
 }
 
+// WatermarkImage returns the PNG file name used as watermark, if any.
+func (c *Config) WatermarkImage() string {
+	if c.config.WatermarkImage == "" {
+		return ""
+	}
+
+	return fs.Abs(c.config.WatermarkImage)
+}
+
 // AssetsPath returns the path to the assets.
 func (c *Config) AssetsPath() string {
 	return fs.Abs(c.config.AssetsPath)
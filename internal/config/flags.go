@@ -190,6 +190,18 @@ var GlobalFlags = []cli.Flag{
 		Value:  "heif-convert",
 		EnvVar: "PHOTOPRISM_HEIFCONVERT_BIN",
 	},
+	cli.StringFlag{
+		Name:   "webp-bin",
+		Usage:  "webp encoder cli binary `FILENAME`",
+		Value:  "cwebp",
+		EnvVar: "PHOTOPRISM_WEBP_BIN",
+	},
+	cli.StringFlag{
+		Name:   "avif-bin",
+		Usage:  "avif encoder cli binary `FILENAME`",
+		Value:  "avifenc",
+		EnvVar: "PHOTOPRISM_AVIF_BIN",
+	},
 	cli.IntFlag{
 		Name:   "http-port",
 		Usage:  "HTTP server port",
@@ -265,4 +277,42 @@ var GlobalFlags = []cli.Flag{
 		Value:  "lanczos",
 		EnvVar: "PHOTOPRISM_THUMB_FILTER",
 	},
+	cli.BoolFlag{
+		Name:   "thumb-lazy",
+		Usage:  "render thumbnails larger than 720 pixels on demand only",
+		EnvVar: "PHOTOPRISM_THUMB_LAZY",
+	},
+	cli.IntFlag{
+		Name:   "thumb-quota",
+		Usage:  "thumbnail cache quota in MB (0 for unlimited)",
+		EnvVar: "PHOTOPRISM_THUMB_QUOTA",
+	},
+	cli.StringFlag{
+		Name:   "watermark-text",
+		Usage:  "watermark text for shared and public downloads",
+		EnvVar: "PHOTOPRISM_WATERMARK_TEXT",
+	},
+	cli.StringFlag{
+		Name:   "watermark-image",
+		Usage:  "PNG `FILENAME` used as watermark instead of text",
+		EnvVar: "PHOTOPRISM_WATERMARK_IMAGE",
+	},
+	cli.StringFlag{
+		Name:   "watermark-position",
+		Usage:  "watermark position (top-left, top-right, bottom-left, bottom-right or center)",
+		Value:  "bottom-right",
+		EnvVar: "PHOTOPRISM_WATERMARK_POSITION",
+	},
+	cli.IntFlag{
+		Name:   "watermark-opacity",
+		Usage:  "watermark opacity in percent (1-100)",
+		Value:  50,
+		EnvVar: "PHOTOPRISM_WATERMARK_OPACITY",
+	},
+	cli.IntFlag{
+		Name:   "watermark-min-size",
+		Usage:  "minimum image size in pixels for watermarks",
+		Value:  720,
+		EnvVar: "PHOTOPRISM_WATERMARK_MIN_SIZE",
+	},
 }
@@ -68,6 +68,8 @@ type Params struct {
 	DarktableBin       string `yaml:"darktable-bin" flag:"darktable-bin"`
 	ExifToolBin        string `yaml:"exiftool-bin" flag:"exiftool-bin"`
 	HeifConvertBin     string `yaml:"heifconvert-bin" flag:"heifconvert-bin"`
+	WebpBin            string `yaml:"webp-bin" flag:"webp-bin"`
+	AvifBin            string `yaml:"avif-bin" flag:"avif-bin"`
 	PIDFilename        string `yaml:"pid-filename" flag:"pid-filename"`
 	LogFilename        string `yaml:"log-filename" flag:"log-filename"`
 	DetachServer       bool   `yaml:"detach-server" flag:"detach-server"`
@@ -79,6 +81,13 @@ type Params struct {
 	ThumbSize          int    `yaml:"thumb-size" flag:"thumb-size"`
 	ThumbLimit         int    `yaml:"thumb-limit" flag:"thumb-limit"`
 	ThumbFilter        string `yaml:"thumb-filter" flag:"thumb-filter"`
+	ThumbLazy          bool   `yaml:"thumb-lazy" flag:"thumb-lazy"`
+	ThumbQuota         int    `yaml:"thumb-quota" flag:"thumb-quota"`
+	WatermarkText      string `yaml:"watermark-text" flag:"watermark-text"`
+	WatermarkImage     string `yaml:"watermark-image" flag:"watermark-image"`
+	WatermarkPosition  string `yaml:"watermark-position" flag:"watermark-position"`
+	WatermarkOpacity   int    `yaml:"watermark-opacity" flag:"watermark-opacity"`
+	WatermarkMinSize   int    `yaml:"watermark-min-size" flag:"watermark-min-size"`
 }
 
 // NewParams creates a new configuration entity by using two methods:
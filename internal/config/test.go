@@ -107,6 +107,13 @@ func NewTestConfig() *Config {
 	thumb.PreRenderSize = c.ThumbSize()
 	thumb.MaxRenderSize = c.ThumbLimit()
 	thumb.Filter = c.ThumbFilter()
+	thumb.WebpBin = c.WebpBin()
+	thumb.AvifBin = c.AvifBin()
+	thumb.WatermarkText = c.WatermarkText()
+	thumb.WatermarkImage = c.WatermarkImage()
+	thumb.WatermarkPosition = c.WatermarkPosition()
+	thumb.WatermarkOpacity = float64(c.WatermarkOpacity()) / 100
+	thumb.WatermarkMinSize = c.WatermarkMinSize()
 
 	return c
 }
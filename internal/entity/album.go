@@ -20,7 +20,10 @@ type Album struct {
 	AlbumNotes       string `gorm:"type:text;"`
 	AlbumOrder       string `gorm:"type:varbinary(32);"`
 	AlbumTemplate    string `gorm:"type:varbinary(256);"`
+	AlbumFilter      string `gorm:"type:varbinary(1024);"`
 	AlbumFavorite    bool
+	OwnerUUID        string `gorm:"type:varbinary(36);index;"`
+	AlbumVisibility  string `gorm:"type:varbinary(16);"`
 	Links            []Link `gorm:"foreignkey:ShareUUID;association_foreignkey:AlbumUUID"`
 	CreatedAt        time.Time
 	UpdatedAt        time.Time
@@ -63,3 +66,8 @@ func (m *Album) Rename(albumName string) {
 	m.AlbumName = strings.TrimSpace(albumName)
 	m.AlbumSlug = slug.Make(m.AlbumName)
 }
+
+// IsSmart returns true if the album contains all photos matching a saved search query.
+func (m *Album) IsSmart() bool {
+	return m.AlbumFilter != ""
+}
@@ -44,3 +44,15 @@ func TestRename(t *testing.T) {
 		assert.Equal(t, "january-0001", album.AlbumSlug)
 	})
 }
+
+func TestAlbum_IsSmart(t *testing.T) {
+	t.Run("regular album", func(t *testing.T) {
+		album := NewAlbum("Holiday")
+		assert.False(t, album.IsSmart())
+	})
+	t.Run("smart album", func(t *testing.T) {
+		album := NewAlbum("Beach")
+		album.AlbumFilter = "label:beach country:es favorites:true"
+		assert.True(t, album.IsSmart())
+	})
+}
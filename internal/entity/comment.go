@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/pkg/rnd"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// MaxCommentLength is the maximum number of characters in a comment.
+var MaxCommentLength = 4096
+
+// Comment represents feedback on a photo or album by a user or share link visitor.
+type Comment struct {
+	ID            uint   `gorm:"primary_key"`
+	CommentUUID   string `gorm:"type:varbinary(36);unique_index;"`
+	PhotoUUID     string `gorm:"type:varbinary(36);index;"`
+	AlbumUUID     string `gorm:"type:varbinary(36);index;"`
+	UserUUID      string `gorm:"type:varbinary(36);"`
+	LinkToken     string `gorm:"type:varbinary(256);" json:"-"`
+	AuthorName    string `gorm:"type:varchar(128);"`
+	CommentText   string `gorm:"type:text;"`
+	CommentHidden bool
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     *time.Time `sql:"index"`
+}
+
+// BeforeCreate computes a random UUID when a new comment is created in database
+func (m *Comment) BeforeCreate(scope *gorm.Scope) error {
+	if err := scope.SetColumn("CommentUUID", rnd.PPID('c')); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NewComment returns a new comment, the author is "Guest" if empty.
+func NewComment(author, text string) (*Comment, error) {
+	text = strings.TrimSpace(text)
+
+	if text == "" {
+		return nil, fmt.Errorf("comment must not be empty")
+	}
+
+	if len([]rune(text)) > MaxCommentLength {
+		return nil, fmt.Errorf("comment must not have more than %d characters", MaxCommentLength)
+	}
+
+	author = txt.Clip(strings.TrimSpace(author), 128)
+
+	if author == "" {
+		author = "Guest"
+	}
+
+	result := &Comment{
+		AuthorName:  author,
+		CommentText: text,
+	}
+
+	return result, nil
+}
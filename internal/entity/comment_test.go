@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewComment(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		m, err := NewComment(" Jane ", " Please retouch this one. ")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "Jane", m.AuthorName)
+		assert.Equal(t, "Please retouch this one.", m.CommentText)
+		assert.False(t, m.CommentHidden)
+	})
+	t.Run("no author", func(t *testing.T) {
+		m, err := NewComment("", "Nice!")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "Guest", m.AuthorName)
+	})
+	t.Run("empty", func(t *testing.T) {
+		_, err := NewComment("Jane", "  ")
+		assert.EqualError(t, err, "comment must not be empty")
+	})
+	t.Run("too long", func(t *testing.T) {
+		_, err := NewComment("Jane", strings.Repeat("a", MaxCommentLength+1))
+		assert.Error(t, err)
+	})
+}
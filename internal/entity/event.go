@@ -7,6 +7,15 @@ import (
 	"github.com/photoprism/photoprism/pkg/rnd"
 )
 
+// Types of events detected by the events worker, which replaces them when photos change.
+const (
+	EventTypeEvent = "event" // Photos taken close in time at home
+	EventTypeTrip  = "trip"  // Photos taken away from home
+)
+
+// EventTypesDetected contains the types of detected events.
+var EventTypesDetected = []string{EventTypeEvent, EventTypeTrip}
+
 // Event defines temporal event that can be used to link photos together
 type Event struct {
 	EventUUID        string `gorm:"type:varbinary(36);unique_index;"`
@@ -20,6 +29,7 @@ type Event struct {
 	EventLat         float64
 	EventLng         float64
 	EventDist        float64
+	OwnerUUID        string `gorm:"type:varbinary(36);index;"`
 	CreatedAt        time.Time
 	UpdatedAt        time.Time
 	DeletedAt        *time.Time `sql:"index"`
@@ -30,7 +40,12 @@ func (Event) TableName() string {
 	return "events"
 }
 
-// BeforeCreate computes a random UUID when a new event is created in database
+// BeforeCreate computes a random UUID when a new event is created in database,
+// unless it replaces a detected event with the same UUID.
 func (e *Event) BeforeCreate(scope *gorm.Scope) error {
+	if e.EventUUID != "" {
+		return nil
+	}
+
 	return scope.SetColumn("EventUUID", rnd.PPID('e'))
 }
@@ -12,43 +12,45 @@ import (
 
 // File represents an image or sidecar file that belongs to a photo
 type File struct {
-	ID              uint `gorm:"primary_key"`
-	Photo           *Photo
-	PhotoID         uint   `gorm:"index;"`
-	PhotoUUID       string `gorm:"type:varbinary(36);index;"`
-	FileUUID        string `gorm:"type:varbinary(36);unique_index;"`
-	FileName        string `gorm:"type:varbinary(600);unique_index"`
-	OriginalName    string `gorm:"type:varbinary(600);"`
-	FileHash        string `gorm:"type:varbinary(128);index"`
-	FileModified    time.Time
-	FileSize        int64
-	FileType        string `gorm:"type:varbinary(32)"`
-	FileMime        string `gorm:"type:varbinary(64)"`
-	FilePrimary     bool
-	FileSidecar     bool
-	FileVideo       bool
-	FileMissing     bool
-	FileDuplicate   bool
-	FilePortrait    bool
-	FileWidth       int
-	FileHeight      int
-	FileOrientation int
-	FileAspectRatio float64
-	FileMainColor   string `gorm:"type:varbinary(16);index;"`
-	FileColors      string `gorm:"type:binary(9);"`
-	FileLuminance   string `gorm:"type:binary(9);"`
-	FileDiff        uint32
-	FileChroma      uint8
-	FileNotes       string `gorm:"type:text"`
-	FileError       string `gorm:"type:varbinary(512)"`
-	Share           []FileShare
-	Sync            []FileSync
-	Links           []Link `gorm:"foreignkey:ShareUUID;association_foreignkey:FileUUID"`
-	CreatedAt       time.Time
-	CreatedIn       int64
-	UpdatedAt       time.Time
-	UpdatedIn       int64
-	DeletedAt       *time.Time `sql:"index"`
+	ID               uint `gorm:"primary_key"`
+	Photo            *Photo
+	PhotoID          uint   `gorm:"index;"`
+	PhotoUUID        string `gorm:"type:varbinary(36);index;"`
+	FileUUID         string `gorm:"type:varbinary(36);unique_index;"`
+	FileName         string `gorm:"type:varbinary(600);unique_index"`
+	OriginalName     string `gorm:"type:varbinary(600);"`
+	FileHash         string `gorm:"type:varbinary(128);index"`
+	FileModified     time.Time
+	FileSize         int64
+	FileType         string `gorm:"type:varbinary(32)"`
+	FileMime         string `gorm:"type:varbinary(64)"`
+	FilePrimary      bool
+	FileSidecar      bool
+	FileVideo        bool
+	FileMissing      bool
+	FileDuplicate    bool
+	FilePortrait     bool
+	FileWidth        int
+	FileHeight       int
+	FileOrientation  int
+	FileAspectRatio  float64
+	FileMainColor    string `gorm:"type:varbinary(16);index;"`
+	FileColors       string `gorm:"type:binary(9);"`
+	FileLuminance    string `gorm:"type:binary(9);"`
+	FileDiff         uint32
+	FileChroma       uint8
+	FileColorProfile string `gorm:"type:varbinary(64);"`
+	FileBlurHash     string `gorm:"type:varbinary(64);"`
+	FileNotes        string `gorm:"type:text"`
+	FileError        string `gorm:"type:varbinary(512)"`
+	Share            []FileShare
+	Sync             []FileSync
+	Links            []Link `gorm:"foreignkey:ShareUUID;association_foreignkey:FileUUID"`
+	CreatedAt        time.Time
+	CreatedIn        int64
+	UpdatedAt        time.Time
+	UpdatedIn        int64
+	DeletedAt        *time.Time `sql:"index"`
 }
 
 // FirstFileByHash gets a file in db from its hash
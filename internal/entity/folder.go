@@ -0,0 +1,74 @@
+package entity
+
+import (
+	"path"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/internal/mutex"
+	"github.com/photoprism/photoprism/pkg/rnd"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// Folder represents a directory of originals with optional title and description.
+type Folder struct {
+	ID                uint   `gorm:"primary_key"`
+	FolderUUID        string `gorm:"type:varbinary(36);unique_index;"`
+	FolderPath        string `gorm:"type:varbinary(512);unique_index;"`
+	FolderTitle       string `gorm:"type:varchar(128);"`
+	FolderDescription string `gorm:"type:text;"`
+	Links             []Link `gorm:"foreignkey:ShareUUID;association_foreignkey:FolderUUID"`
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	DeletedAt         *time.Time `sql:"index"`
+}
+
+// BeforeCreate computes a random UUID when a new folder is created in database
+func (m *Folder) BeforeCreate(scope *gorm.Scope) error {
+	if err := scope.SetColumn("FolderUUID", rnd.PPID('d')); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NewFolder creates a new folder for a path relative to the originals directory.
+func NewFolder(folderPath string) *Folder {
+	result := &Folder{
+		FolderPath: FolderPath(folderPath),
+	}
+
+	return result
+}
+
+// FirstOrCreate returns the existing folder with the same path or creates a new one.
+func (m *Folder) FirstOrCreate(db *gorm.DB) error {
+	mutex.Db.Lock()
+	defer mutex.Db.Unlock()
+
+	return db.Preload("Links").FirstOrCreate(m, "folder_path = ?", m.FolderPath).Error
+}
+
+// SetTitle updates title and description, the title is clipped to 128 characters.
+func (m *Folder) SetTitle(title, description string) {
+	m.FolderTitle = txt.Clip(title, 128)
+	m.FolderDescription = strings.TrimSpace(description)
+}
+
+// Name returns the folder title, or the last path element if no title was set.
+func (m *Folder) Name() string {
+	if m.FolderTitle != "" {
+		return m.FolderTitle
+	}
+
+	return path.Base("/" + m.FolderPath)
+}
+
+// FolderPath returns a clean folder path relative to the originals directory without leading
+// or trailing slashes, the root folder is an empty string.
+func FolderPath(folderPath string) string {
+	result := path.Clean("/" + strings.ReplaceAll(strings.TrimSpace(folderPath), "\\", "/"))
+
+	return strings.Trim(result, "/")
+}
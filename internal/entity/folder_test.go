@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFolderPath(t *testing.T) {
+	t.Run("root", func(t *testing.T) {
+		assert.Equal(t, "", FolderPath(""))
+		assert.Equal(t, "", FolderPath("/"))
+		assert.Equal(t, "", FolderPath(" / "))
+	})
+	t.Run("nested", func(t *testing.T) {
+		assert.Equal(t, "2020/Holiday", FolderPath("/2020/Holiday/"))
+		assert.Equal(t, "2020/Holiday", FolderPath("2020//Holiday"))
+		assert.Equal(t, "2020/Holiday", FolderPath("2020\\Holiday"))
+	})
+	t.Run("parent directory", func(t *testing.T) {
+		assert.Equal(t, "Holiday", FolderPath("2020/../Holiday"))
+		assert.Equal(t, "etc", FolderPath("../../etc"))
+	})
+}
+
+func TestFolder_Name(t *testing.T) {
+	t.Run("path", func(t *testing.T) {
+		folder := NewFolder("2020/Holiday")
+		assert.Equal(t, "2020/Holiday", folder.FolderPath)
+		assert.Equal(t, "Holiday", folder.Name())
+	})
+	t.Run("title", func(t *testing.T) {
+		folder := NewFolder("2020/Holiday")
+		folder.SetTitle(" Summer in Italy ", " Two weeks at the lake ")
+		assert.Equal(t, "Summer in Italy", folder.Name())
+		assert.Equal(t, "Two weeks at the lake", folder.FolderDescription)
+	})
+}
@@ -21,6 +21,7 @@ type Label struct {
 	LabelFavorite    bool
 	LabelDescription string   `gorm:"type:text;"`
 	LabelNotes       string   `gorm:"type:text;"`
+	OwnerUUID        string   `gorm:"type:varbinary(36);index;"`
 	LabelCategories  []*Label `gorm:"many2many:categories;association_jointable_foreignkey:category_id"`
 	Links            []Link   `gorm:"foreignkey:ShareUUID;association_foreignkey:LabelUUID"`
 	CreatedAt        time.Time
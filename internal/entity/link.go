@@ -1,23 +1,29 @@
 package entity
 
 import (
+	"crypto/subtle"
+	"strings"
 	"time"
 
 	"github.com/jinzhu/gorm"
 	"github.com/photoprism/photoprism/pkg/rnd"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // Link represents a sharing link.
 type Link struct {
-	LinkToken    string     `gorm:"type:varbinary(256);primary_key;"`
-	LinkPassword string     `gorm:"type:varbinary(256);"`
-	LinkExpires  *time.Time `gorm:"type:datetime;"`
-	ShareUUID    string     `gorm:"type:varbinary(36);index;"`
-	CanComment   bool
-	CanEdit      bool
-	CreatedAt    time.Time  `deepcopier:"skip"`
-	UpdatedAt    time.Time  `deepcopier:"skip"`
-	DeletedAt    *time.Time `deepcopier:"skip" sql:"index"`
+	LinkToken     string     `gorm:"type:varbinary(256);primary_key;"`
+	LinkPassword  string     `gorm:"type:varbinary(256);" json:"-"`
+	LinkExpires   *time.Time `gorm:"type:datetime;"`
+	ShareUUID     string     `gorm:"type:varbinary(36);index;"`
+	CanComment    bool
+	CanEdit       bool
+	LinkViews     uint
+	LinkDownloads uint
+	LastAccess    *time.Time `deepcopier:"skip"`
+	CreatedAt     time.Time  `deepcopier:"skip"`
+	UpdatedAt     time.Time  `deepcopier:"skip"`
+	DeletedAt     *time.Time `deepcopier:"skip" sql:"index"`
 }
 
 // BeforeCreate creates a new URL token when a new link is created.
@@ -29,14 +35,71 @@ func (m *Link) BeforeCreate(scope *gorm.Scope) error {
 	return nil
 }
 
-// NewLink creates a sharing link.
+// NewLink creates a sharing link, the password is stored as bcrypt hash.
 func NewLink(password string, canComment, canEdit bool) Link {
 	result := Link{
-		LinkToken:    rnd.Token(10),
-		LinkPassword: password,
-		CanComment:   canComment,
-		CanEdit:      canEdit,
+		LinkToken:  rnd.Token(10),
+		CanComment: canComment,
+		CanEdit:    canEdit,
 	}
 
+	result.SetPassword(password)
+
 	return result
 }
+
+// SetPassword stores a bcrypt hash of the password, an empty password removes it.
+func (m *Link) SetPassword(password string) {
+	if password == "" {
+		m.LinkPassword = ""
+		return
+	}
+
+	if hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost); err != nil {
+		log.Errorf("link: %s", err)
+		m.LinkPassword = password
+	} else {
+		m.LinkPassword = string(hash)
+	}
+}
+
+// HasPassword returns true if visitors must enter a password.
+func (m *Link) HasPassword() bool {
+	return m.LinkPassword != ""
+}
+
+// CheckPassword returns true if the link has no password or the password matches,
+// links created before passwords were hashed contain them in plain text.
+func (m *Link) CheckPassword(password string) bool {
+	if !m.HasPassword() {
+		return true
+	}
+
+	if strings.HasPrefix(m.LinkPassword, "$2") {
+		return bcrypt.CompareHashAndPassword([]byte(m.LinkPassword), []byte(password)) == nil
+	}
+
+	return subtle.ConstantTimeCompare([]byte(m.LinkPassword), []byte(password)) == 1
+}
+
+// Expired returns true if the link has an expiry date that has passed.
+func (m *Link) Expired(now time.Time) bool {
+	return m.LinkExpires != nil && !m.LinkExpires.After(now)
+}
+
+// Accessed increments the view or download count and sets the last access time.
+func (m *Link) Accessed(db *gorm.DB, download bool) error {
+	now := time.Now().UTC()
+	column := "link_views"
+
+	if download {
+		column = "link_downloads"
+		m.LinkDownloads++
+	} else {
+		m.LinkViews++
+	}
+
+	m.LastAccess = &now
+
+	return db.Model(m).UpdateColumns(map[string]interface{}{column: gorm.Expr(column + " + 1"), "last_access": now}).Error
+}
@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLink(t *testing.T) {
+	t.Run("password", func(t *testing.T) {
+		link := NewLink("secret", true, false)
+
+		assert.Equal(t, 10, len(link.LinkToken))
+		assert.True(t, link.HasPassword())
+		assert.NotEqual(t, "secret", link.LinkPassword)
+		assert.True(t, link.CheckPassword("secret"))
+		assert.False(t, link.CheckPassword("wrong"))
+		assert.True(t, link.CanComment)
+		assert.False(t, link.CanEdit)
+	})
+	t.Run("no password", func(t *testing.T) {
+		link := NewLink("", false, false)
+
+		assert.False(t, link.HasPassword())
+		assert.True(t, link.CheckPassword(""))
+		assert.True(t, link.CheckPassword("anything"))
+	})
+}
+
+func TestLink_CheckPassword(t *testing.T) {
+	link := Link{LinkPassword: "plain"}
+
+	assert.True(t, link.CheckPassword("plain"))
+	assert.False(t, link.CheckPassword("other"))
+}
+
+func TestLink_Expired(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+
+	assert.False(t, (&Link{}).Expired(now))
+	assert.True(t, (&Link{LinkExpires: &past}).Expired(now))
+	assert.False(t, (&Link{LinkExpires: &future}).Expired(now))
+}
+
+func TestLink_SetPassword(t *testing.T) {
+	link := NewLink("secret", false, false)
+
+	link.SetPassword("changed")
+	assert.True(t, link.CheckPassword("changed"))
+	assert.False(t, link.CheckPassword("secret"))
+
+	link.SetPassword("")
+	assert.False(t, link.HasPassword())
+}
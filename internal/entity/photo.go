@@ -18,6 +18,8 @@ type Photo struct {
 	PhotoUUID           string      `gorm:"type:varbinary(36);unique_index;index:idx_photos_taken_uuid;"`
 	PhotoPath           string      `gorm:"type:varbinary(512);index;"`
 	PhotoName           string      `gorm:"type:varbinary(256);"`
+	OwnerUUID           string      `gorm:"type:varbinary(36);index;" json:"OwnerUUID"`
+	PhotoVisibility     string      `gorm:"type:varbinary(16);" json:"PhotoVisibility"`
 	PhotoTitle          string      `json:"PhotoTitle"`
 	PhotoFavorite       bool        `json:"PhotoFavorite"`
 	PhotoPrivate        bool        `json:"PhotoPrivate"`
@@ -36,6 +38,7 @@ type Photo struct {
 	PlaceID             string      `gorm:"type:varbinary(16);index;" json:"PlaceID"`
 	LocationID          string      `gorm:"type:varbinary(16);index;" json:"LocationID"`
 	LocationEstimated   bool        `json:"LocationEstimated"`
+	EventUUID           string      `gorm:"type:varbinary(36);index;" json:"EventUUID"`
 	PhotoCountry        string      `gorm:"index:idx_photos_country_year_month;" json:"PhotoCountry"`
 	PhotoYear           int         `gorm:"index:idx_photos_country_year_month;"`
 	PhotoMonth          int         `gorm:"index:idx_photos_country_year_month;"`
@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/pkg/rnd"
+)
+
+// Session represents a user login or a share link visitor, only a hash of the session token is stored.
+type Session struct {
+	ID          string `gorm:"type:varbinary(64);primary_key;auto_increment:false;" json:"-"`
+	SessionUUID string `gorm:"type:varbinary(36);unique_index;"`
+	UserUUID    string `gorm:"type:varbinary(36);index;"`
+	LinkToken   string `gorm:"type:varbinary(256);index;" json:"-"`
+	ShareUUID   string `gorm:"type:varbinary(36);" json:"-"`
+	ClientIP    string `gorm:"type:varbinary(64);"`
+	UserAgent   string `gorm:"type:varchar(512);"`
+	Current     bool   `gorm:"-"`
+	CreatedAt   time.Time
+	LastSeen    time.Time
+	ExpiresAt   time.Time `gorm:"index;"`
+}
+
+// BeforeCreate computes a random UUID when a new session is created in database
+func (m *Session) BeforeCreate(scope *gorm.Scope) error {
+	if err := scope.SetColumn("SessionUUID", rnd.PPID('s')); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Expired returns true if the session has expired at the given time.
+func (m *Session) Expired(now time.Time) bool {
+	return !m.ExpiresAt.After(now)
+}
+
+// Visitor returns true if the session belongs to a share link visitor instead of a user.
+func (m *Session) Visitor() bool {
+	return m.ShareUUID != ""
+}
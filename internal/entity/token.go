@@ -0,0 +1,98 @@
+package entity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/pkg/rnd"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// tokenTouchInterval limits how often the last used time is updated in the database.
+var tokenTouchInterval = time.Minute
+
+// Token represents a long-lived API token of a user, only a hash of the secret is stored.
+type Token struct {
+	ID          string     `gorm:"type:varbinary(64);primary_key;auto_increment:false;" json:"-"`
+	TokenUUID   string     `gorm:"type:varbinary(36);unique_index;"`
+	UserUUID    string     `gorm:"type:varbinary(36);index;"`
+	TokenName   string     `gorm:"type:varchar(128);"`
+	TokenScopes string     `gorm:"type:varbinary(255);"`
+	ExpiresAt   *time.Time `sql:"index"`
+	LastUsed    *time.Time
+	CreatedAt   time.Time
+}
+
+// BeforeCreate computes a random UUID when a new token is created in database
+func (m *Token) BeforeCreate(scope *gorm.Scope) error {
+	if err := scope.SetColumn("TokenUUID", rnd.PPID('t')); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NewToken returns a new API token and its secret, which can't be recovered later.
+// The token never expires if expiresAt is nil.
+func NewToken(userUUID, name string, scopes acl.Scopes, expiresAt *time.Time) (m *Token, secret string, err error) {
+	if len(scopes) == 0 {
+		return nil, "", fmt.Errorf("token requires at least one scope")
+	}
+
+	b := make([]byte, 24)
+
+	if _, err := rand.Read(b); err != nil {
+		return nil, "", err
+	}
+
+	secret = fmt.Sprintf("%x", b)
+
+	m = &Token{
+		ID:          TokenHash(secret),
+		UserUUID:    userUUID,
+		TokenName:   txt.Clip(strings.TrimSpace(name), 128),
+		TokenScopes: scopes.String(),
+		ExpiresAt:   expiresAt,
+	}
+
+	return m, secret, nil
+}
+
+// TokenHash returns the SHA256 hash of a token secret, which is used as token ID.
+func TokenHash(secret string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(secret)))
+}
+
+// Scopes returns the scopes granted to the token, invalid scopes are ignored.
+func (m *Token) Scopes() (result acl.Scopes) {
+	for _, s := range strings.Split(m.TokenScopes, ",") {
+		if scope := acl.Scope(s); scope.Valid() {
+			result = append(result, scope)
+		}
+	}
+
+	return result
+}
+
+// Expired returns true if the token has an expiry date that has passed.
+func (m *Token) Expired(now time.Time) bool {
+	return m.ExpiresAt != nil && !m.ExpiresAt.After(now)
+}
+
+// Touch updates the last used time, but not more than once per minute.
+func (m *Token) Touch(db *gorm.DB) error {
+	now := time.Now().UTC()
+
+	if m.LastUsed != nil && now.Sub(*m.LastUsed) < tokenTouchInterval {
+		return nil
+	}
+
+	m.LastUsed = &now
+
+	return db.Model(m).UpdateColumn("last_used", now).Error
+}
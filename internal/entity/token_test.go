@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewToken(t *testing.T) {
+	t.Run("scopes", func(t *testing.T) {
+		m, secret, err := NewToken("u000000000000001", " Backup ", acl.Scopes{acl.ScopePhotosRead, acl.ScopeUpload}, nil)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 48, len(secret))
+		assert.Equal(t, TokenHash(secret), m.ID)
+		assert.Equal(t, "Backup", m.TokenName)
+		assert.Equal(t, "photos:read,upload", m.TokenScopes)
+		assert.Equal(t, acl.Scopes{acl.ScopePhotosRead, acl.ScopeUpload}, m.Scopes())
+		assert.False(t, m.Expired(time.Now()))
+	})
+	t.Run("no scopes", func(t *testing.T) {
+		_, _, err := NewToken("u000000000000001", "Backup", nil, nil)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestToken_Expired(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	assert.False(t, (&Token{}).Expired(now))
+	assert.True(t, (&Token{ExpiresAt: &past}).Expired(now))
+	assert.False(t, (&Token{ExpiresAt: &future}).Expired(now))
+}
@@ -0,0 +1,144 @@
+package entity
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/pkg/rnd"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MinPasswordLength is the minimum length of user passwords.
+var MinPasswordLength = 6
+
+var userNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9._@-]{0,63}$`)
+
+// User represents a person who can log in.
+type User struct {
+	ID           uint     `gorm:"primary_key"`
+	UserUUID     string   `gorm:"type:varbinary(36);unique_index;"`
+	UserName     string   `gorm:"type:varchar(64);unique_index;"`
+	FirstName    string   `gorm:"type:varchar(64);"`
+	LastName     string   `gorm:"type:varchar(64);"`
+	Email        string   `gorm:"type:varchar(255);"`
+	Role         acl.Role `gorm:"type:varbinary(32);"`
+	PasswordHash string   `gorm:"type:varbinary(128);" json:"-"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	DeletedAt    *time.Time `sql:"index"`
+}
+
+// Admin is the default user, it is created with the admin password if no other users exist.
+var Admin = User{
+	ID:        1,
+	UserUUID:  "u000000000000001",
+	UserName:  "admin",
+	FirstName: "Admin",
+	Email:     "photoprism@localhost",
+	Role:      acl.RoleAdmin,
+}
+
+// BeforeCreate computes a random UUID when a new user is created in database, unless it's the default user
+func (m *User) BeforeCreate(scope *gorm.Scope) error {
+	if m.UserUUID != "" {
+		return nil
+	}
+
+	if err := scope.SetColumn("UserUUID", rnd.PPID('u')); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NewUser returns a new user with the given login name and role.
+func NewUser(userName string, role acl.Role) (*User, error) {
+	userName = strings.ToLower(strings.TrimSpace(userName))
+
+	if !userNameRegexp.MatchString(userName) {
+		return nil, fmt.Errorf("invalid user name \"%s\"", userName)
+	}
+
+	if !role.Valid() {
+		return nil, fmt.Errorf("invalid role \"%s\"", role)
+	}
+
+	result := &User{
+		UserName: userName,
+		Role:     role,
+	}
+
+	return result, nil
+}
+
+// SetPassword stores a bcrypt hash of the password.
+func (m *User) SetPassword(password string) error {
+	if len(password) < MinPasswordLength {
+		return fmt.Errorf("password must have at least %d characters", MinPasswordLength)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+
+	if err != nil {
+		return err
+	}
+
+	m.PasswordHash = string(hash)
+
+	return nil
+}
+
+// CheckPassword returns true if the password matches.
+func (m *User) CheckPassword(password string) bool {
+	if m.PasswordHash == "" {
+		return false
+	}
+
+	return bcrypt.CompareHashAndPassword([]byte(m.PasswordHash), []byte(password)) == nil
+}
+
+// FullName returns first and last name, or the login name if both are empty.
+func (m *User) FullName() string {
+	if name := strings.TrimSpace(m.FirstName + " " + m.LastName); name != "" {
+		return name
+	}
+
+	return m.UserName
+}
+
+// CreateDefaultUser creates the admin user with the given password if no users exist,
+// so that existing installations can log in as before. The password may already be a bcrypt hash.
+func CreateDefaultUser(db *gorm.DB, password string) {
+	var count int
+
+	if err := db.Model(&User{}).Count(&count).Error; err != nil {
+		log.Errorf("user: %s", err)
+		return
+	}
+
+	if count > 0 {
+		return
+	}
+
+	m := Admin
+
+	if strings.HasPrefix(password, "$2") {
+		m.PasswordHash = password
+	} else if hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost); err != nil {
+		log.Errorf("user: %s", err)
+		return
+	} else {
+		m.PasswordHash = string(hash)
+	}
+
+	if err := db.Create(&m).Error; err != nil {
+		log.Errorf("user: %s", err)
+		return
+	}
+
+	log.Infof("user: created default user \"%s\"", m.UserName)
+}
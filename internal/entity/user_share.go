@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/internal/mutex"
+)
+
+// Visibility of photos and albums owned by a user, an empty value is the same as everyone.
+const (
+	VisibilityPrivate  = "private"  // Owner only
+	VisibilityShared   = "shared"   // Owner and users it is shared with
+	VisibilityEveryone = "everyone" // All users
+)
+
+// ValidVisibility returns true if the visibility exists.
+func ValidVisibility(visibility string) bool {
+	switch visibility {
+	case VisibilityPrivate, VisibilityShared, VisibilityEveryone:
+		return true
+	default:
+		return false
+	}
+}
+
+// UserShare represents a photo or album shared with another user.
+type UserShare struct {
+	UserUUID  string `gorm:"type:varbinary(36);primary_key;auto_increment:false"`
+	ShareUUID string `gorm:"type:varbinary(36);primary_key;auto_increment:false"`
+	CreatedAt time.Time
+}
+
+// TableName returns UserShare table identifier "user_shares"
+func (UserShare) TableName() string {
+	return "user_shares"
+}
+
+// SetUserShares replaces the users a photo or album is shared with.
+func SetUserShares(db *gorm.DB, shareUUID string, userUUIDs []string) error {
+	mutex.Db.Lock()
+	defer mutex.Db.Unlock()
+
+	tx := db.Begin()
+
+	if err := tx.Where("share_uuid = ?", shareUUID).Delete(&UserShare{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for _, userUUID := range userUUIDs {
+		if err := tx.Create(&UserShare{UserUUID: userUUID, ShareUUID: shareUUID}).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
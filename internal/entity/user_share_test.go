@@ -0,0 +1,15 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidVisibility(t *testing.T) {
+	assert.True(t, ValidVisibility(VisibilityPrivate))
+	assert.True(t, ValidVisibility(VisibilityShared))
+	assert.True(t, ValidVisibility(VisibilityEveryone))
+	assert.False(t, ValidVisibility(""))
+	assert.False(t, ValidVisibility("public"))
+}
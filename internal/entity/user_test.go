@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUser(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		user, err := NewUser(" Jane.Doe ", acl.RoleEditor)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "jane.doe", user.UserName)
+		assert.Equal(t, acl.RoleEditor, user.Role)
+		assert.Equal(t, "jane.doe", user.FullName())
+	})
+	t.Run("invalid name", func(t *testing.T) {
+		_, err := NewUser("jane doe", acl.RoleViewer)
+		assert.EqualError(t, err, "invalid user name \"jane doe\"")
+	})
+	t.Run("invalid role", func(t *testing.T) {
+		_, err := NewUser("jane", acl.Role("owner"))
+		assert.EqualError(t, err, "invalid role \"owner\"")
+	})
+}
+
+func TestUser_SetPassword(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		user := User{UserName: "jane"}
+
+		if err := user.SetPassword("photoprism"); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.NotEqual(t, "photoprism", user.PasswordHash)
+		assert.True(t, user.CheckPassword("photoprism"))
+		assert.False(t, user.CheckPassword("photoprism2"))
+	})
+	t.Run("too short", func(t *testing.T) {
+		user := User{UserName: "jane"}
+		assert.Error(t, user.SetPassword("abc"))
+		assert.False(t, user.CheckPassword(""))
+	})
+}
+
+func TestUser_FullName(t *testing.T) {
+	assert.Equal(t, "Admin", Admin.FullName())
+	assert.Equal(t, "Jane Doe", (&User{UserName: "jane", FirstName: "Jane", LastName: "Doe"}).FullName())
+}
@@ -9,4 +9,5 @@ type Album struct {
 	AlbumPublic      bool   `json:"AlbumPublic"`
 	AlbumOrder       string `json:"AlbumOrder"`
 	AlbumTemplate    string `json:"AlbumTemplate"`
+	AlbumFilter      string `json:"AlbumFilter"`
 }
@@ -0,0 +1,22 @@
+package form
+
+// Comment represents a form to add a comment, visitors of album links may comment on a photo in the album.
+type Comment struct {
+	Author string `json:"author"`
+	Text   string `json:"text"`
+	Photo  string `json:"photo"`
+}
+
+// CommentModeration represents a form to hide or show a comment.
+type CommentModeration struct {
+	Hidden bool `json:"hidden"`
+}
+
+// CommentSearch represents search form fields for comments on a photo or album.
+type CommentSearch struct {
+	Photo  string `form:"photo"`
+	Album  string `form:"album"`
+	Hidden bool   `form:"hidden"`
+	Count  int    `form:"count"`
+	Offset int    `form:"offset"`
+}
@@ -0,0 +1,10 @@
+package form
+
+// EventSearch represents search form fields for "/api/v1/events".
+type EventSearch struct {
+	Query  string `form:"q"`
+	Type   string `form:"type"`
+	Year   int    `form:"year"`
+	Count  int    `form:"count" binding:"required"`
+	Offset int    `form:"offset"`
+}
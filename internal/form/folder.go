@@ -0,0 +1,17 @@
+package form
+
+// Folder represents a folder edit form.
+type Folder struct {
+	FolderPath        string `json:"FolderPath"`
+	FolderTitle       string `json:"FolderTitle"`
+	FolderDescription string `json:"FolderDescription"`
+}
+
+// FolderSearch represents search form fields for "/api/v1/folders".
+type FolderSearch struct {
+	Path   string `form:"path"`
+	Public bool   `form:"public"`
+	Safe   bool   `form:"safe"`
+	Count  int    `form:"count"`
+	Offset int    `form:"offset"`
+}
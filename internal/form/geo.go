@@ -0,0 +1,129 @@
+package form
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxPolygonPoints is the max number of points of a polygon filter.
+const MaxPolygonPoints = 100
+
+// BBox represents a bounding box in GeoJSON order: west, south, east and north.
+// The west longitude is greater than the east longitude if the box crosses the antimeridian.
+type BBox [4]float64
+
+// Polygon represents a closed polygon as list of longitude and latitude pairs.
+type Polygon [][2]float64
+
+// ParseBBox parses a bounding box like "13.2,52.4,13.6,52.6" (west, south, east, north).
+func ParseBBox(s string) (result BBox, err error) {
+	values, err := parseCoordinates(s)
+
+	if err != nil {
+		return result, err
+	}
+
+	if len(values) != 4 {
+		return result, fmt.Errorf("bbox must contain 4 coordinates")
+	}
+
+	copy(result[:], values)
+
+	if result[1] > result[3] {
+		return result, fmt.Errorf("bbox south must not be greater than north")
+	}
+
+	return result, validCoordinates(result[0], result[1], result[2], result[3])
+}
+
+// ParsePolygon parses a polygon from comma separated longitude and latitude pairs like "13.2,52.4,13.6,52.4,13.4,52.6".
+func ParsePolygon(s string) (result Polygon, err error) {
+	values, err := parseCoordinates(s)
+
+	if err != nil {
+		return result, err
+	}
+
+	if len(values)%2 != 0 {
+		return result, fmt.Errorf("polygon must contain longitude and latitude pairs")
+	}
+
+	if err := validCoordinates(values...); err != nil {
+		return result, err
+	}
+
+	for i := 0; i < len(values); i += 2 {
+		result = append(result, [2]float64{values[i], values[i+1]})
+	}
+
+	// The first point may be repeated to close the polygon.
+	if len(result) > 1 && result[0] == result[len(result)-1] {
+		result = result[:len(result)-1]
+	}
+
+	if len(result) < 3 {
+		return result, fmt.Errorf("polygon must contain at least 3 points")
+	} else if len(result) > MaxPolygonPoints {
+		return result, fmt.Errorf("polygon must not contain more than %d points", MaxPolygonPoints)
+	}
+
+	return result, nil
+}
+
+// Bounds returns the bounding box of the polygon.
+func (p Polygon) Bounds() (result BBox) {
+	for i, point := range p {
+		if i == 0 {
+			result = BBox{point[0], point[1], point[0], point[1]}
+			continue
+		}
+
+		if point[0] < result[0] {
+			result[0] = point[0]
+		}
+
+		if point[1] < result[1] {
+			result[1] = point[1]
+		}
+
+		if point[0] > result[2] {
+			result[2] = point[0]
+		}
+
+		if point[1] > result[3] {
+			result[3] = point[1]
+		}
+	}
+
+	return result
+}
+
+func parseCoordinates(s string) (result []float64, err error) {
+	for _, v := range strings.Split(s, ",") {
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid coordinate %q", strings.TrimSpace(v))
+		}
+
+		result = append(result, f)
+	}
+
+	return result, nil
+}
+
+// validCoordinates checks longitude and latitude pairs.
+func validCoordinates(values ...float64) error {
+	for i := 0; i+1 < len(values); i += 2 {
+		if values[i] < -180 || values[i] > 180 {
+			return fmt.Errorf("longitude %g out of range", values[i])
+		}
+
+		if values[i+1] < -90 || values[i+1] > 90 {
+			return fmt.Errorf("latitude %g out of range", values[i+1])
+		}
+	}
+
+	return nil
+}
@@ -4,14 +4,16 @@ import "time"
 
 // GeoSearch represents search form fields for "/api/v1/geo".
 type GeoSearch struct {
-	Query  string    `form:"q"`
-	Before time.Time `form:"before" time_format:"2006-01-02"`
-	After  time.Time `form:"after" time_format:"2006-01-02"`
-	Lat    float64   `form:"lat"`
-	Lng    float64   `form:"lng"`
-	S2     string    `form:"s2"`
-	Olc    string    `form:"olc"`
-	Dist   uint      `form:"dist"`
+	Query   string    `form:"q"`
+	Before  time.Time `form:"before" time_format:"2006-01-02"`
+	After   time.Time `form:"after" time_format:"2006-01-02"`
+	Lat     float64   `form:"lat"`
+	Lng     float64   `form:"lng"`
+	S2      string    `form:"s2"`
+	Olc     string    `form:"olc"`
+	Dist    uint      `form:"dist"`
+	BBox    string    `form:"bbox"`
+	Polygon string    `form:"polygon"`
 }
 
 // GetQuery returns the query parameter as string.
@@ -0,0 +1,55 @@
+package form
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBBox(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		result, err := ParseBBox("13.2, 52.4, 13.6, 52.6")
+
+		assert.Nil(t, err)
+		assert.Equal(t, BBox{13.2, 52.4, 13.6, 52.6}, result)
+	})
+	t.Run("antimeridian", func(t *testing.T) {
+		result, err := ParseBBox("170,-20,-170,-10")
+
+		assert.Nil(t, err)
+		assert.Equal(t, BBox{170, -20, -170, -10}, result)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ParseBBox("13.2,52.4,13.6")
+		assert.EqualError(t, err, "bbox must contain 4 coordinates")
+
+		_, err = ParseBBox("13.2,52.6,13.6,52.4")
+		assert.EqualError(t, err, "bbox south must not be greater than north")
+
+		_, err = ParseBBox("13.2,95,13.6,52.4")
+		assert.Error(t, err)
+
+		_, err = ParseBBox("a,b,c,d")
+		assert.EqualError(t, err, "invalid coordinate \"a\"")
+	})
+}
+
+func TestParsePolygon(t *testing.T) {
+	t.Run("closed", func(t *testing.T) {
+		result, err := ParsePolygon("13.2,52.4,13.6,52.4,13.4,52.6,13.2,52.4")
+
+		assert.Nil(t, err)
+		assert.Equal(t, Polygon{{13.2, 52.4}, {13.6, 52.4}, {13.4, 52.6}}, result)
+		assert.Equal(t, BBox{13.2, 52.4, 13.6, 52.6}, result.Bounds())
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ParsePolygon("13.2,52.4,13.6,52.4")
+		assert.EqualError(t, err, "polygon must contain at least 3 points")
+
+		_, err = ParsePolygon("13.2,52.4,13.6")
+		assert.EqualError(t, err, "polygon must contain longitude and latitude pairs")
+
+		_, err = ParsePolygon("200,52.4,13.6,52.4,13.4,52.6")
+		assert.EqualError(t, err, "longitude 200 out of range")
+	})
+}
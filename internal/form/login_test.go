@@ -0,0 +1,13 @@
+package form
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogin_UserName(t *testing.T) {
+	assert.Equal(t, "jane", Login{User: " jane ", Email: "admin"}.UserName())
+	assert.Equal(t, "jane@example.com", Login{Email: "jane@example.com"}.UserName())
+	assert.Equal(t, "admin", Login{}.UserName())
+}
@@ -0,0 +1,11 @@
+package form
+
+import "time"
+
+// Memories represents search form fields for "/api/v1/memories".
+type Memories struct {
+	Date    time.Time `form:"date" time_format:"2006-01-02"`
+	Private bool      `form:"private"`
+	Nsfw    bool      `form:"nsfw"`
+	Count   int       `form:"count"`
+}
@@ -1,7 +1,11 @@
 package form
 
 import (
+	"reflect"
+	"strings"
 	"time"
+
+	log "github.com/sirupsen/logrus"
 )
 
 // PhotoSearch represents search form fields for "/api/v1/photos".
@@ -16,6 +20,8 @@ type PhotoSearch struct {
 	Lat       float64   `form:"lat"`
 	Lng       float64   `form:"lng"`
 	Dist      uint      `form:"dist"`
+	BBox      string    `form:"bbox"`
+	Polygon   string    `form:"polygon"`
 	Fmin      float64   `form:"fmin"`
 	Fmax      float64   `form:"fmax"`
 	Chroma    uint8     `form:"chroma"`
@@ -25,10 +31,15 @@ type PhotoSearch struct {
 	Location  bool      `form:"location"`
 	Album     string    `form:"album"`
 	Label     string    `form:"label"`
+	Event     string    `form:"event"`
+	Path      string    `form:"path"`
+	Recursive bool      `form:"recursive"`
 	Country   string    `form:"country"`
 	Year      uint      `form:"year"`
 	Month     uint      `form:"month"`
 	Color     string    `form:"color"`
+	Similar   string    `form:"similar"`
+	Palette   string    `form:"palette"`
 	Camera    int       `form:"camera"`
 	Lens      int       `form:"lens"`
 	Before    time.Time `form:"before" time_format:"2006-01-02"`
@@ -40,7 +51,9 @@ type PhotoSearch struct {
 	Nsfw      bool      `form:"nsfw"`
 	Count     int       `form:"count" binding:"required"`
 	Offset    int       `form:"offset"`
+	Cursor    string    `form:"cursor"`
 	Order     string    `form:"order"`
+	Expr      Expr      `form:"-"`
 }
 
 func (f *PhotoSearch) GetQuery() string {
@@ -51,8 +64,166 @@ func (f *PhotoSearch) SetQuery(q string) {
 	f.Query = q
 }
 
+// ParseQueryString parses the search query. Queries with operators, alternatives, ranges or multiple
+// free text terms are parsed into a syntax tree, which is compiled to SQL by query.Photos.
 func (f *PhotoSearch) ParseQueryString() error {
-	return ParseQueryString(f)
+	if !advancedQuery(f.Query) {
+		return ParseQueryString(f)
+	}
+
+	expr, err := ParseExpr(f.Query)
+
+	if err != nil {
+		log.Errorf("error while parsing search query: %s", err)
+		return err
+	}
+
+	f.Query = ""
+	f.Expr, err = f.setOptions(expr)
+
+	return err
+}
+
+// ParseFilter parses a saved search query like the filter of a smart album, simple queries are
+// parsed into a syntax tree as well so that they can be combined with other search options.
+func (f *PhotoSearch) ParseFilter() (err error) {
+	expr, err := ParseExpr(f.Query)
+
+	if err != nil {
+		return err
+	}
+
+	f.Query = ""
+	f.Expr, err = f.setOptions(expr)
+
+	return err
+}
+
+// setOptions sets form fields like "count" or "before" found at the top level of a query
+// and returns the remaining expression.
+func (f *PhotoSearch) setOptions(expr Expr) (Expr, error) {
+	formValues := reflect.ValueOf(f).Elem()
+
+	var terms And
+
+	if and, ok := expr.(And); ok {
+		terms = and
+	} else {
+		terms = And{expr}
+	}
+
+	var result And
+
+	for _, e := range terms {
+		t, ok := e.(*Term)
+
+		if !ok || t.Key == "" || isFilter(t.Key) {
+			if err := checkFilters(e); err != nil {
+				return nil, err
+			}
+
+			result = append(result, e)
+			continue
+		}
+
+		if err := setValue(formValues, t.Key, t.Values[0].Text); err != nil {
+			return nil, queryError(t.Pos, "%s", err)
+		}
+	}
+
+	switch len(result) {
+	case 0:
+		return nil, nil
+	case 1:
+		return result[0], nil
+	default:
+		return result, nil
+	}
+}
+
+// isFilter returns true if the key is supported by the photo search query language.
+func isFilter(key string) bool {
+	_, ok := SearchFilters[key]
+
+	return ok
+}
+
+// checkFilters returns an error if an expression contains form options that can't be combined with operators.
+func checkFilters(expr Expr) error {
+	switch e := expr.(type) {
+	case And:
+		for _, sub := range e {
+			if err := checkFilters(sub); err != nil {
+				return err
+			}
+		}
+	case Or:
+		for _, sub := range e {
+			if err := checkFilters(sub); err != nil {
+				return err
+			}
+		}
+	case Not:
+		return checkFilters(e.Expr)
+	case *Term:
+		if e.Key != "" && !isFilter(e.Key) {
+			if reflect.ValueOf(PhotoSearch{}).FieldByName(strings.Title(e.Key)).IsValid() {
+				return queryError(e.Pos, "%s can't be combined with OR or NOT", e.Key)
+			}
+
+			return queryError(e.Pos, "unknown filter %s", e.Key)
+		}
+	}
+
+	return nil
+}
+
+// advancedQuery returns true if a query can't be parsed by the simple key:value parser.
+func advancedQuery(q string) bool {
+	tokens, err := tokenize([]rune(q))
+
+	// Let the query parser report syntax errors.
+	if err != nil {
+		return true
+	}
+
+	formFields := reflect.ValueOf(PhotoSearch{})
+	text := 0
+
+	for _, t := range tokens {
+		switch t.kind {
+		case tokenEnd:
+		case tokenPhrase:
+			text++
+		case tokenWord:
+			if t.key == "" {
+				text++
+				continue
+			}
+
+			key := strings.ToLower(t.key)
+
+			if !formFields.FieldByName(strings.Title(key)).IsValid() && isFilter(key) {
+				return true
+			}
+
+			if t.quoted {
+				continue
+			}
+
+			if strings.Contains(t.value, "|") || strings.Contains(t.value, "..") {
+				return true
+			}
+
+			if SearchFilters[key] == FilterNumber && strings.Contains(strings.TrimPrefix(t.value, "-"), "-") {
+				return true
+			}
+		default:
+			return true
+		}
+	}
+
+	return text > 1
 }
 
 func NewPhotoSearch(query string) PhotoSearch {
@@ -143,3 +143,39 @@ func TestParseQueryString(t *testing.T) {
 		assert.Equal(t, "Could not find format for \"cat\"", err.Error())
 	})
 }
+
+func TestPhotoSearch_ParseFilter(t *testing.T) {
+	t.Run("filters and options", func(t *testing.T) {
+		form := &PhotoSearch{Query: "label:beach country:es favorites:true order:oldest"}
+
+		err := form.ParseFilter()
+
+		assert.Nil(t, err)
+		assert.Equal(t, "", form.Query)
+		assert.Equal(t, "oldest", form.Order)
+		assert.Equal(t, "label:beach AND country:es AND favorites:true", form.Expr.String())
+	})
+	t.Run("relative date", func(t *testing.T) {
+		form := &PhotoSearch{Query: "taken:30d"}
+
+		err := form.ParseFilter()
+
+		assert.Nil(t, err)
+		assert.Equal(t, "taken:30d", form.Expr.String())
+	})
+	t.Run("empty", func(t *testing.T) {
+		form := &PhotoSearch{}
+
+		err := form.ParseFilter()
+
+		assert.Nil(t, err)
+		assert.Nil(t, form.Expr)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		form := &PhotoSearch{Query: "label:beach OR"}
+
+		err := form.ParseFilter()
+
+		assert.IsType(t, &QueryError{}, err)
+	})
+}
@@ -34,45 +34,8 @@ func ParseQueryString(f SearchForm) (result error) {
 	for _, char := range q {
 		if unicode.IsSpace(char) && !escaped {
 			if isKeyValue {
-				fieldName := strings.Title(string(key))
-				field := formValues.FieldByName(fieldName)
-				stringValue := string(value)
-
-				if field.CanSet() {
-					switch field.Interface().(type) {
-					case time.Time:
-						if timeValue, err := dateparse.ParseAny(stringValue); err != nil {
-							result = err
-						} else {
-							field.Set(reflect.ValueOf(timeValue))
-						}
-					case float32, float64:
-						if floatValue, err := strconv.ParseFloat(stringValue, 64); err != nil {
-							result = err
-						} else {
-							field.SetFloat(floatValue)
-						}
-					case int, int8, int16, int32, int64:
-						if intValue, err := strconv.Atoi(stringValue); err != nil {
-							result = err
-						} else {
-							field.SetInt(int64(intValue))
-						}
-					case uint, uint8, uint16, uint32, uint64:
-						if intValue, err := strconv.Atoi(stringValue); err != nil {
-							result = err
-						} else {
-							field.SetUint(uint64(intValue))
-						}
-					case string:
-						field.SetString(stringValue)
-					case bool:
-						field.SetBool(txt.Bool(stringValue))
-					default:
-						result = fmt.Errorf("unsupported type: %s", fieldName)
-					}
-				} else {
-					result = fmt.Errorf("unknown filter: %s", fieldName)
+				if err := setValue(formValues, string(key), string(value)); err != nil {
+					result = err
 				}
 			} else {
 				f.SetQuery(string(key))
@@ -99,3 +62,48 @@ func ParseQueryString(f SearchForm) (result error) {
 
 	return result
 }
+
+// setValue sets the form field matching a filter name, e.g. "favorites" for "favorites:true".
+func setValue(formValues reflect.Value, key, stringValue string) (result error) {
+	fieldName := strings.Title(key)
+	field := formValues.FieldByName(fieldName)
+
+	if !field.CanSet() {
+		return fmt.Errorf("unknown filter: %s", fieldName)
+	}
+
+	switch field.Interface().(type) {
+	case time.Time:
+		if timeValue, err := dateparse.ParseAny(stringValue); err != nil {
+			result = err
+		} else {
+			field.Set(reflect.ValueOf(timeValue))
+		}
+	case float32, float64:
+		if floatValue, err := strconv.ParseFloat(stringValue, 64); err != nil {
+			result = err
+		} else {
+			field.SetFloat(floatValue)
+		}
+	case int, int8, int16, int32, int64:
+		if intValue, err := strconv.Atoi(stringValue); err != nil {
+			result = err
+		} else {
+			field.SetInt(int64(intValue))
+		}
+	case uint, uint8, uint16, uint32, uint64:
+		if intValue, err := strconv.Atoi(stringValue); err != nil {
+			result = err
+		} else {
+			field.SetUint(uint64(intValue))
+		}
+	case string:
+		field.SetString(stringValue)
+	case bool:
+		field.SetBool(txt.Bool(stringValue))
+	default:
+		result = fmt.Errorf("unsupported type: %s", fieldName)
+	}
+
+	return result
+}
@@ -0,0 +1,641 @@
+package form
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// FilterType represents the value type of a search filter.
+type FilterType int
+
+const (
+	FilterText FilterType = iota
+	FilterNumber
+	FilterDate
+	FilterBool
+)
+
+// SearchFilters maps the filters supported by the photo search query language to their value type.
+var SearchFilters = map[string]FilterType{
+	"label":     FilterText,
+	"title":     FilterText,
+	"country":   FilterText,
+	"color":     FilterText,
+	"album":     FilterText,
+	"event":     FilterText,
+	"hash":      FilterText,
+	"camera":    FilterNumber,
+	"lens":      FilterNumber,
+	"year":      FilterNumber,
+	"month":     FilterNumber,
+	"iso":       FilterNumber,
+	"f":         FilterNumber,
+	"mm":        FilterNumber,
+	"chroma":    FilterNumber,
+	"taken":     FilterDate,
+	"favorites": FilterBool,
+	"story":     FilterBool,
+	"private":   FilterBool,
+	"nsfw":      FilterBool,
+	"portrait":  FilterBool,
+	"mono":      FilterBool,
+	"duplicate": FilterBool,
+}
+
+// DateLayouts contains the supported date formats with decreasing precision.
+var DateLayouts = []string{"2006-01-02", "2006-01", "2006"}
+
+// QueryError represents a syntax error in a search query.
+type QueryError struct {
+	Pos     int // Position starting at 1
+	Message string
+}
+
+// Error returns the error message including the position.
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Message, e.Pos)
+}
+
+func queryError(pos int, format string, a ...interface{}) *QueryError {
+	return &QueryError{Pos: pos + 1, Message: fmt.Sprintf(format, a...)}
+}
+
+// Expr represents a node in the syntax tree of a search query.
+type Expr interface {
+	String() string
+}
+
+// And matches if all expressions match.
+type And []Expr
+
+// Or matches if at least one expression matches.
+type Or []Expr
+
+// Not matches if the expression doesn't match.
+type Not struct {
+	Expr Expr
+}
+
+// Term represents a free text word, a quoted phrase or a filter like "label:cat".
+type Term struct {
+	Key    string
+	Values []Value
+	Phrase bool
+	Pos    int
+}
+
+// Value represents a single filter value or a range, min or max may be empty for open ranges.
+type Value struct {
+	Text  string
+	Min   string
+	Max   string
+	Range bool
+}
+
+func (e And) String() string {
+	return join(e, " AND ")
+}
+
+func (e Or) String() string {
+	return join(e, " OR ")
+}
+
+func (e Not) String() string {
+	return "NOT " + e.Expr.String()
+}
+
+func (t *Term) String() string {
+	var values []string
+
+	for _, v := range t.Values {
+		values = append(values, v.String())
+	}
+
+	value := strings.Join(values, "|")
+
+	if t.Phrase {
+		value = fmt.Sprintf("%q", value)
+	}
+
+	if t.Key == "" {
+		return value
+	}
+
+	return t.Key + ":" + value
+}
+
+func (v Value) String() string {
+	if v.Range {
+		return v.Min + ".." + v.Max
+	}
+
+	return v.Text
+}
+
+func join(list []Expr, sep string) string {
+	var parts []string
+
+	for _, e := range list {
+		switch e.(type) {
+		case And, Or:
+			parts = append(parts, "("+e.String()+")")
+		default:
+			parts = append(parts, e.String())
+		}
+	}
+
+	return strings.Join(parts, sep)
+}
+
+// Conjunction returns the terms of a query that only contains terms implicitly or explicitly joined with AND.
+func Conjunction(e Expr) (terms []*Term, ok bool) {
+	switch e := e.(type) {
+	case nil:
+		return terms, true
+	case *Term:
+		return []*Term{e}, true
+	case And:
+		for _, sub := range e {
+			t, ok := sub.(*Term)
+
+			if !ok {
+				return nil, false
+			}
+
+			terms = append(terms, t)
+		}
+
+		return terms, true
+	default:
+		return nil, false
+	}
+}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenPhrase
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenOpen
+	tokenClose
+	tokenEnd
+)
+
+type token struct {
+	kind   tokenKind
+	key    string
+	value  string
+	quoted bool
+	pos    int
+}
+
+// ParseExpr parses a search query into a syntax tree, an empty query returns nil.
+//
+// Terms are joined with AND by default, other operators are OR, NOT or "-" and parentheses.
+// Filters accept alternatives like "label:cat|dog" and ranges like "iso:100-400", "f:1.4..2.8"
+// or "taken:2019-06..2019-08".
+func ParseExpr(query string) (Expr, error) {
+	tokens, err := tokenize([]rune(query))
+
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	if p.peek().kind == tokenEnd {
+		return nil, nil
+	}
+
+	result, err := p.parseOr()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokenEnd {
+		return nil, queryError(t.pos, "unexpected %s", t.describe())
+	}
+
+	return result, nil
+}
+
+func (t token) describe() string {
+	switch t.kind {
+	case tokenOpen:
+		return "\"(\""
+	case tokenClose:
+		return "\")\""
+	case tokenAnd:
+		return "AND"
+	case tokenOr:
+		return "OR"
+	case tokenNot:
+		return "NOT"
+	case tokenEnd:
+		return "end of query"
+	default:
+		return fmt.Sprintf("%q", t.value)
+	}
+}
+
+// tokenize splits a query into tokens, positions are counted in runes.
+func tokenize(q []rune) (tokens []token, err error) {
+	i := 0
+
+	for i < len(q) {
+		c := q[i]
+
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenOpen, pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenClose, pos: i})
+			i++
+		case c == '"':
+			value, next, err := quoted(q, i)
+
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = append(tokens, token{kind: tokenPhrase, value: value, quoted: true, pos: i})
+			i = next
+		case c == '-' && i+1 < len(q) && !unicode.IsSpace(q[i+1]) && q[i+1] != ')':
+			tokens = append(tokens, token{kind: tokenNot, pos: i})
+			i++
+		default:
+			start := i
+			t := token{kind: tokenWord, pos: start}
+
+			for i < len(q) && !unicode.IsSpace(q[i]) && q[i] != '(' && q[i] != ')' {
+				if q[i] == '"' {
+					if t.key == "" || i != start+len([]rune(t.key))+1 {
+						return nil, queryError(i, "unexpected quote")
+					}
+
+					value, next, err := quoted(q, i)
+
+					if err != nil {
+						return nil, err
+					}
+
+					t.value = value
+					t.quoted = true
+					i = next
+
+					break
+				}
+
+				if q[i] == ':' && t.key == "" {
+					t.key = string(q[start:i])
+
+					if t.key == "" {
+						return nil, queryError(i, "missing filter name")
+					}
+				}
+
+				i++
+			}
+
+			if t.key == "" {
+				t.value = string(q[start:i])
+
+				switch t.value {
+				case "AND":
+					t.kind = tokenAnd
+				case "OR":
+					t.kind = tokenOr
+				case "NOT":
+					t.kind = tokenNot
+				}
+			} else if !t.quoted {
+				t.value = string(q[start+len([]rune(t.key))+1 : i])
+			}
+
+			tokens = append(tokens, t)
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokenEnd, pos: len(q)})
+
+	return tokens, nil
+}
+
+// quoted returns the text between the quote at position i and the closing quote.
+func quoted(q []rune, i int) (value string, next int, err error) {
+	for j := i + 1; j < len(q); j++ {
+		if q[j] == '"' {
+			return string(q[i+1 : j]), j + 1, nil
+		}
+	}
+
+	return "", 0, queryError(i, "missing closing quote")
+}
+
+type parser struct {
+	tokens []token
+	i      int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+
+	if t.kind != tokenEnd {
+		p.i++
+	}
+
+	return t
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	first, err := p.parseAnd()
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := Or{first}
+
+	for p.peek().kind == tokenOr {
+		op := p.next()
+
+		if !p.startsTerm() {
+			return nil, queryError(op.pos, "missing term after OR")
+		}
+
+		e, err := p.parseAnd()
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, e)
+	}
+
+	if len(result) == 1 {
+		return first, nil
+	}
+
+	return result, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	var result And
+
+	for {
+		t := p.peek()
+
+		if t.kind == tokenAnd {
+			if len(result) == 0 {
+				return nil, queryError(t.pos, "missing term before AND")
+			}
+
+			p.next()
+
+			if !p.startsTerm() {
+				return nil, queryError(t.pos, "missing term after AND")
+			}
+
+			continue
+		}
+
+		if !p.startsTerm() {
+			break
+		}
+
+		e, err := p.parseUnary()
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, e)
+	}
+
+	switch len(result) {
+	case 0:
+		t := p.peek()
+		return nil, queryError(t.pos, "unexpected %s", t.describe())
+	case 1:
+		return result[0], nil
+	default:
+		return result, nil
+	}
+}
+
+// startsTerm returns true if the next token can start a term.
+func (p *parser) startsTerm() bool {
+	switch p.peek().kind {
+	case tokenWord, tokenPhrase, tokenNot, tokenOpen:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if t := p.peek(); t.kind == tokenNot {
+		p.next()
+
+		switch p.peek().kind {
+		case tokenWord, tokenPhrase, tokenNot, tokenOpen:
+		default:
+			return nil, queryError(t.pos, "missing term after NOT")
+		}
+
+		e, err := p.parseUnary()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return Not{Expr: e}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	t := p.next()
+
+	switch t.kind {
+	case tokenOpen:
+		if p.peek().kind == tokenClose {
+			return nil, queryError(t.pos, "empty parentheses")
+		}
+
+		e, err := p.parseOr()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if p.peek().kind != tokenClose {
+			return nil, queryError(t.pos, "missing closing parenthesis")
+		}
+
+		p.next()
+
+		return e, nil
+	case tokenPhrase:
+		return &Term{Values: []Value{{Text: t.value}}, Phrase: true, Pos: t.pos}, nil
+	case tokenWord:
+		return newTerm(t)
+	default:
+		return nil, queryError(t.pos, "unexpected %s", t.describe())
+	}
+}
+
+// newTerm creates a term from a word token and validates the values of known filters.
+func newTerm(t token) (*Term, error) {
+	result := &Term{Key: strings.ToLower(t.key), Phrase: t.quoted, Pos: t.pos}
+
+	if t.key == "" {
+		result.Values = []Value{{Text: t.value}}
+		return result, nil
+	}
+
+	if t.value == "" {
+		return nil, queryError(t.pos, "missing value for %s", result.Key)
+	}
+
+	filterType, ok := SearchFilters[result.Key]
+
+	// Other keys are validated by the search form.
+	if !ok || t.quoted {
+		result.Values = []Value{{Text: t.value}}
+		return result, nil
+	}
+
+	for _, s := range strings.Split(t.value, "|") {
+		if s == "" {
+			return nil, queryError(t.pos, "empty alternative in %s", result.Key)
+		}
+
+		v, err := parseValue(filterType, s)
+
+		if err != nil {
+			return nil, queryError(t.pos, "invalid %s value %q", result.Key, s)
+		}
+
+		result.Values = append(result.Values, v)
+	}
+
+	if filterType == FilterBool && len(result.Values) > 1 {
+		return nil, queryError(t.pos, "%s doesn't accept alternatives", result.Key)
+	}
+
+	return result, nil
+}
+
+// parseValue parses a single value or range depending on the filter type.
+func parseValue(filterType FilterType, s string) (v Value, err error) {
+	switch filterType {
+	case FilterNumber:
+		if parts := strings.SplitN(s, "..", 2); len(parts) == 2 {
+			v = Value{Min: parts[0], Max: parts[1], Range: true}
+		} else if i := strings.Index(s[1:], "-"); i >= 0 {
+			v = Value{Min: s[:i+1], Max: s[i+2:], Range: true}
+		} else {
+			v = Value{Text: s}
+		}
+
+		for _, n := range []string{v.Text, v.Min, v.Max} {
+			if n == "" {
+				continue
+			}
+
+			if _, err := strconv.ParseFloat(n, 64); err != nil {
+				return v, err
+			}
+		}
+
+		if v.Range && v.Min == "" && v.Max == "" {
+			return v, fmt.Errorf("empty range")
+		}
+	case FilterDate:
+		if parts := strings.SplitN(s, "..", 2); len(parts) == 2 {
+			v = Value{Min: parts[0], Max: parts[1], Range: true}
+		} else {
+			v = Value{Text: s}
+		}
+
+		for _, d := range []string{v.Text, v.Min, v.Max} {
+			if d == "" {
+				continue
+			}
+
+			if _, _, err := ParseDate(d); err != nil {
+				return v, err
+			}
+		}
+
+		if v.Range && v.Min == "" && v.Max == "" {
+			return v, fmt.Errorf("empty range")
+		}
+	default:
+		v = Value{Text: s}
+	}
+
+	return v, nil
+}
+
+// ParseDate returns the start of the period described by a year, month or day and the start of the next period.
+// Relative periods like "30d", "2w", "6m" or "1y" start the given number of days, weeks, months or years ago
+// and end today.
+func ParseDate(s string) (start, end time.Time, err error) {
+	if len(s) < 2 {
+		return start, end, fmt.Errorf("invalid date %q", s)
+	}
+
+	if n, err := strconv.Atoi(s[:len(s)-1]); err == nil && n >= 0 {
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		end = today.AddDate(0, 0, 1)
+
+		switch s[len(s)-1] {
+		case 'd':
+			return today.AddDate(0, 0, -n), end, nil
+		case 'w':
+			return today.AddDate(0, 0, -7*n), end, nil
+		case 'm':
+			return today.AddDate(0, -n, 0), end, nil
+		case 'y':
+			return today.AddDate(-n, 0, 0), end, nil
+		}
+	}
+
+	for _, layout := range DateLayouts {
+		if len(s) != len(layout) {
+			continue
+		}
+
+		if start, err = time.Parse(layout, s); err != nil {
+			return start, end, err
+		}
+
+		switch layout {
+		case "2006":
+			return start, start.AddDate(1, 0, 0), nil
+		case "2006-01":
+			return start, start.AddDate(0, 1, 0), nil
+		default:
+			return start, start.AddDate(0, 0, 1), nil
+		}
+	}
+
+	return start, end, fmt.Errorf("invalid date %q", s)
+}
@@ -0,0 +1,152 @@
+package form
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseExpr(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		expr, err := ParseExpr("  ")
+
+		assert.Nil(t, err)
+		assert.Nil(t, expr)
+	})
+	t.Run("implicit and", func(t *testing.T) {
+		expr, err := ParseExpr("cat label:dog")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "cat AND label:dog", expr.String())
+	})
+	t.Run("precedence", func(t *testing.T) {
+		expr, err := ParseExpr("cat dog OR bird AND NOT fish")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "(cat AND dog) OR (bird AND NOT fish)", expr.String())
+	})
+	t.Run("parentheses and negation", func(t *testing.T) {
+		expr, err := ParseExpr(`(label:cat OR label:dog) -"black and white"`)
+
+		assert.Nil(t, err)
+		assert.Equal(t, `(label:cat OR label:dog) AND NOT "black and white"`, expr.String())
+	})
+	t.Run("quoted filter value", func(t *testing.T) {
+		expr, err := ParseExpr(`title:"New York" iso:100`)
+
+		assert.Nil(t, err)
+		assert.Equal(t, `title:"New York" AND iso:100`, expr.String())
+	})
+	t.Run("ranges and alternatives", func(t *testing.T) {
+		expr, err := ParseExpr("iso:100-400 f:1.4..2.8 taken:2019-06..2019-08 label:cat|dog mm:..50")
+
+		assert.Nil(t, err)
+
+		and, ok := expr.(And)
+
+		if !ok {
+			t.Fatal("expression should be a conjunction")
+		}
+
+		assert.Len(t, and, 5)
+		assert.Equal(t, []Value{{Min: "100", Max: "400", Range: true}}, and[0].(*Term).Values)
+		assert.Equal(t, []Value{{Min: "1.4", Max: "2.8", Range: true}}, and[1].(*Term).Values)
+		assert.Equal(t, []Value{{Min: "2019-06", Max: "2019-08", Range: true}}, and[2].(*Term).Values)
+		assert.Equal(t, []Value{{Text: "cat"}, {Text: "dog"}}, and[3].(*Term).Values)
+		assert.Equal(t, []Value{{Max: "50", Range: true}}, and[4].(*Term).Values)
+	})
+	t.Run("errors", func(t *testing.T) {
+		tests := map[string]string{
+			"cat OR":            "missing term after OR at position 5",
+			"(cat dog":          "missing closing parenthesis at position 1",
+			"cat )":             "unexpected \")\" at position 5",
+			"()":                "empty parentheses at position 1",
+			`"cat`:              "missing closing quote at position 1",
+			"iso:abc":           "invalid iso value \"abc\" at position 1",
+			"dog taken:2019-13": "invalid taken value \"2019-13\" at position 5",
+			"label:":            "missing value for label at position 1",
+			"AND cat":           "missing term before AND at position 1",
+			"NOT":               "missing term after NOT at position 1",
+			"label:cat||dog":    "empty alternative in label at position 1",
+		}
+
+		for q, msg := range tests {
+			_, err := ParseExpr(q)
+
+			if assert.Error(t, err, q) {
+				assert.Equal(t, msg, err.Error(), q)
+				assert.IsType(t, &QueryError{}, err)
+			}
+		}
+	})
+}
+
+func TestParseDate(t *testing.T) {
+	start, end, err := ParseDate("2019-06")
+
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC), end)
+
+	_, _, err = ParseDate("June")
+
+	assert.Error(t, err)
+}
+
+func TestPhotoSearch_ParseQueryString_Expr(t *testing.T) {
+	t.Run("simple query", func(t *testing.T) {
+		form := &PhotoSearch{Query: "label:cat favorites:true"}
+
+		assert.Nil(t, form.ParseQueryString())
+		assert.Nil(t, form.Expr)
+		assert.Equal(t, "cat", form.Label)
+	})
+	t.Run("event", func(t *testing.T) {
+		form := &PhotoSearch{Query: "event:barcelona-june-2019"}
+
+		assert.Nil(t, form.ParseQueryString())
+		assert.Nil(t, form.Expr)
+		assert.Equal(t, "barcelona-june-2019", form.Event)
+	})
+	t.Run("options", func(t *testing.T) {
+		form := &PhotoSearch{Query: "label:cat|dog count:10 before:2019-01-15"}
+
+		assert.Nil(t, form.ParseQueryString())
+		assert.Equal(t, "label:cat|dog", form.Expr.String())
+		assert.Equal(t, 10, form.Count)
+		assert.Equal(t, time.Date(2019, 01, 15, 0, 0, 0, 0, time.UTC), form.Before)
+		assert.Equal(t, "", form.Label)
+	})
+	t.Run("option in or", func(t *testing.T) {
+		form := &PhotoSearch{Query: "iso:100 OR count:10"}
+		err := form.ParseQueryString()
+
+		assert.EqualError(t, err, "count can't be combined with OR or NOT at position 12")
+	})
+	t.Run("unknown filter", func(t *testing.T) {
+		form := &PhotoSearch{Query: "cat OR xxx:1"}
+		err := form.ParseQueryString()
+
+		assert.EqualError(t, err, "unknown filter xxx at position 8")
+	})
+}
+
+func TestParseDate_Relative(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	start, end, err := ParseDate("30d")
+
+	assert.Nil(t, err)
+	assert.Equal(t, today.AddDate(0, 0, -30), start)
+	assert.Equal(t, today.AddDate(0, 0, 1), end)
+
+	start, _, err = ParseDate("2w")
+
+	assert.Nil(t, err)
+	assert.Equal(t, today.AddDate(0, 0, -14), start)
+
+	_, _, err = ParseDate("30x")
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,7 @@
+package form
+
+// Share represents a form to change the visibility of photos and albums.
+type Share struct {
+	Visibility string   `json:"Visibility"`
+	Users      []string `json:"Users"`
+}
@@ -0,0 +1,8 @@
+package form
+
+// Token represents a form to create a new API token, it never expires if ExpiresDays is 0.
+type Token struct {
+	Name        string   `json:"Name"`
+	Scopes      []string `json:"Scopes"`
+	ExpiresDays int      `json:"ExpiresDays"`
+}
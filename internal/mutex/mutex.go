@@ -5,8 +5,11 @@ import (
 )
 
 var (
-	Db     = sync.Mutex{}
-	Worker = Busy{}
-	Sync   = Busy{}
-	Share  = Busy{}
+	Db       = sync.Mutex{}
+	Worker   = Busy{}
+	Sync     = Busy{}
+	Share    = Busy{}
+	Thumbs   = Busy{}
+	Memories = Busy{}
+	Events   = Busy{}
 )
@@ -0,0 +1,28 @@
+package photoprism
+
+import (
+	"errors"
+
+	"github.com/disintegration/imaging"
+	"github.com/photoprism/photoprism/pkg/blurhash"
+)
+
+// BlurHashSize is the edge length of the image used to compute BlurHash placeholders.
+var BlurHashSize = 32
+
+// BlurHash returns a compact placeholder string for the image (only JPEG supported).
+func (m *MediaFile) BlurHash(thumbPath string) (string, error) {
+	if !m.IsJpeg() {
+		return "", errors.New("no blurhash: not a JPEG file")
+	}
+
+	img, err := m.Resample(thumbPath, "tile_224")
+
+	if err != nil {
+		return "", err
+	}
+
+	img = imaging.Resize(img, BlurHashSize, BlurHashSize, imaging.Box)
+
+	return blurhash.Encode(img, 4, 4)
+}
@@ -0,0 +1,33 @@
+package photoprism
+
+import (
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMediaFile_BlurHash(t *testing.T) {
+	conf := config.TestConfig()
+
+	t.Run("cat_brown.jpg", func(t *testing.T) {
+		if mediaFile, err := NewMediaFile(conf.ExamplesPath() + "/cat_brown.jpg"); err == nil {
+			hash, err := mediaFile.BlurHash(conf.ThumbnailsPath())
+
+			t.Log(hash, err)
+
+			assert.Nil(t, err)
+			assert.Len(t, hash, 36)
+			assert.Equal(t, "U", hash[:1])
+		} else {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Random.docx", func(t *testing.T) {
+		mediaFile, err := NewMediaFile(conf.ExamplesPath() + "/Random.docx")
+		hash, err := mediaFile.BlurHash(conf.ThumbnailsPath())
+		assert.Error(t, err, "no blurhash: not a JPEG file")
+		assert.Empty(t, hash)
+	})
+}
@@ -0,0 +1,349 @@
+package photoprism
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/gosimple/slug"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/maps"
+	"github.com/photoprism/photoprism/internal/mutex"
+	"github.com/photoprism/photoprism/internal/query"
+)
+
+var (
+	EventGap       = 12 * time.Hour // Max time between photos of an event at home
+	TripGap        = 72 * time.Hour // Max time between photos of a trip
+	EventMinPhotos = 10             // Min number of photos in an event
+	HomeRadius     = 50.0           // Photos taken within this distance in km from home are not part of a trip
+	homeCellSize   = 0.1            // Grid size in degrees used to find the area with most photos
+)
+
+// earthRadius is the mean radius of the earth in km.
+const earthRadius = 6371.0
+
+// Events detects events based on the time and location photos were taken.
+type Events struct {
+	conf *config.Config
+}
+
+// EventCluster contains photos of the same owner that belong to the same event sorted by time taken.
+type EventCluster struct {
+	Type      string
+	OwnerUUID string
+	Photos    []query.EventPhoto
+}
+
+// NewEvents returns a new event detection worker.
+func NewEvents(conf *config.Config) *Events {
+	return &Events{conf: conf}
+}
+
+// Start replaces all detected events and assigns photos to them, UUIDs of events
+// that overlap with a new event are kept so that links and filters keep working.
+func (e *Events) Start() error {
+	db := e.conf.Db()
+
+	photos, err := query.New(db).EventPhotos()
+
+	if err != nil {
+		return err
+	}
+
+	clusters := ClusterEvents(photos)
+
+	var existing, other []entity.Event
+
+	if err := db.Where("event_type IN (?)", entity.EventTypesDetected).Find(&existing).Error; err != nil {
+		return err
+	}
+
+	// Slugs must be unique, also when events were added by users.
+	if err := db.Unscoped().Where("event_type NOT IN (?)", entity.EventTypesDetected).Find(&other).Error; err != nil {
+		return err
+	}
+
+	mutex.Db.Lock()
+	defer mutex.Db.Unlock()
+
+	tx := db.Begin()
+
+	if err := tx.Unscoped().Where("event_type IN (?)", entity.EventTypesDetected).Delete(&entity.Event{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Model(&entity.Photo{}).Where("event_uuid <> ''").UpdateColumn("event_uuid", "").Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	used := make(map[string]bool)
+	slugs := make(map[string]int)
+
+	for _, m := range other {
+		slugs[m.EventSlug] = 1
+	}
+
+	for _, c := range clusters {
+		m := c.Event()
+
+		for _, old := range existing {
+			if !used[old.EventUUID] && old.OwnerUUID == m.OwnerUUID && !old.EventBegin.After(m.EventEnd) && !old.EventEnd.Before(m.EventBegin) {
+				used[old.EventUUID] = true
+				m.EventUUID = old.EventUUID
+				m.CreatedAt = old.CreatedAt
+				break
+			}
+		}
+
+		if n := slugs[m.EventSlug]; n > 0 {
+			slugs[m.EventSlug] = n + 1
+			m.EventSlug = fmt.Sprintf("%s-%d", m.EventSlug, n+1)
+		} else {
+			slugs[m.EventSlug] = 1
+		}
+
+		if err := tx.Create(&m).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		uuids := make([]string, len(c.Photos))
+
+		for i, p := range c.Photos {
+			uuids[i] = p.PhotoUUID
+		}
+
+		if err := tx.Model(&entity.Photo{}).Where("photo_uuid IN (?)", uuids).UpdateColumn("event_uuid", m.EventUUID).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	log.Infof("events: found %d events in %d photos", len(clusters), len(photos))
+
+	return nil
+}
+
+// ClusterEvents groups photos sorted by time taken into events of each owner, so that events don't mix
+// photos of different users that may be private.
+func ClusterEvents(photos []query.EventPhoto) (results []EventCluster) {
+	var owners []string
+	byOwner := make(map[string][]query.EventPhoto)
+
+	for _, p := range photos {
+		if _, ok := byOwner[p.OwnerUUID]; !ok {
+			owners = append(owners, p.OwnerUUID)
+		}
+
+		byOwner[p.OwnerUUID] = append(byOwner[p.OwnerUUID], p)
+	}
+
+	for _, owner := range owners {
+		results = append(results, clusterOwnerEvents(owner, byOwner[owner])...)
+	}
+
+	return results
+}
+
+// clusterOwnerEvents groups photos of one owner into events. A new event starts after a gap of more
+// than EventGap, or TripGap while away from the owner's home, and when leaving or returning home.
+// Photos without location are considered to be taken at the same place as the photo before.
+func clusterOwnerEvents(ownerUUID string, photos []query.EventPhoto) (results []EventCluster) {
+	homeLat, homeLng, hasHome := homeLocation(photos)
+
+	current := EventCluster{OwnerUUID: ownerUUID}
+	var last time.Time
+	away := false
+
+	flush := func() {
+		if len(current.Photos) >= EventMinPhotos {
+			results = append(results, current)
+		}
+
+		current = EventCluster{OwnerUUID: ownerUUID}
+	}
+
+	for _, p := range photos {
+		if hasHome && p.HasLocation() {
+			away = distance(homeLat, homeLng, p.PhotoLat, p.PhotoLng) > HomeRadius
+		}
+
+		eventType := entity.EventTypeEvent
+		gap := EventGap
+
+		if away {
+			eventType = entity.EventTypeTrip
+			gap = TripGap
+		}
+
+		if len(current.Photos) > 0 && (current.Type != eventType || p.TakenAt.Sub(last) > gap) {
+			flush()
+		}
+
+		current.Type = eventType
+		current.Photos = append(current.Photos, p)
+		last = p.TakenAt
+	}
+
+	flush()
+
+	return results
+}
+
+// homeLocation returns the center of the area with most photos, which is assumed to be home.
+// Photos in neighboring grid cells are counted as well, so that home isn't split by a cell border.
+func homeLocation(photos []query.EventPhoto) (lat, lng float64, ok bool) {
+	type cell struct{ lat, lng int }
+
+	counts := make(map[cell]int)
+
+	for _, p := range photos {
+		if p.HasLocation() {
+			counts[cell{int(math.Floor(p.PhotoLat / homeCellSize)), int(math.Floor(p.PhotoLng / homeCellSize))}]++
+		}
+	}
+
+	var best cell
+	bestScore := 0
+
+	for c := range counts {
+		score := 0
+
+		for dLat := -1; dLat <= 1; dLat++ {
+			for dLng := -1; dLng <= 1; dLng++ {
+				score += counts[cell{c.lat + dLat, c.lng + dLng}]
+			}
+		}
+
+		if score > bestScore || score == bestScore && (c.lat < best.lat || c.lat == best.lat && c.lng < best.lng) {
+			best, bestScore = c, score
+		}
+	}
+
+	if bestScore == 0 {
+		return 0, 0, false
+	}
+
+	return (float64(best.lat) + 0.5) * homeCellSize, (float64(best.lng) + 0.5) * homeCellSize, true
+}
+
+// Event returns a new event for the cluster, named after the most common city or country and the time.
+func (c EventCluster) Event() entity.Event {
+	begin := c.Photos[0].TakenAt
+	end := c.Photos[len(c.Photos)-1].TakenAt
+
+	var lat, lng float64
+	var located int
+	cities := make(map[string]int)
+	countries := make(map[string]int)
+
+	for _, p := range c.Photos {
+		if p.HasLocation() {
+			lat += p.PhotoLat
+			lng += p.PhotoLng
+			located++
+		}
+
+		if p.LocCity != "" && p.LocCity != entity.UnknownPlace.LocCity {
+			cities[p.LocCity]++
+		}
+
+		if name, ok := maps.CountryNames[p.PhotoCountry]; ok && p.PhotoCountry != entity.UnknownPlace.LocCountry {
+			countries[name]++
+		}
+	}
+
+	var dist float64
+
+	if located > 0 {
+		lat /= float64(located)
+		lng /= float64(located)
+
+		for _, p := range c.Photos {
+			if p.HasLocation() {
+				dist = math.Max(dist, distance(lat, lng, p.PhotoLat, p.PhotoLng))
+			}
+		}
+	}
+
+	place := mostCommon(cities)
+
+	if place == "" {
+		place = mostCommon(countries)
+	}
+
+	name := eventName(place, begin, end)
+
+	return entity.Event{
+		EventSlug:  slug.Make(name),
+		EventName:  name,
+		EventType:  c.Type,
+		EventBegin: begin,
+		EventEnd:   end,
+		EventLat:   lat,
+		EventLng:   lng,
+		EventDist:  dist,
+		OwnerUUID:  c.OwnerUUID,
+	}
+}
+
+// eventName returns a name like "Barcelona, June 2019" or "June - July 2019" if the place is unknown.
+func eventName(place string, begin, end time.Time) string {
+	var date string
+
+	switch {
+	case begin.Year() != end.Year():
+		date = fmt.Sprintf("%s - %s", begin.Format("January 2006"), end.Format("January 2006"))
+	case begin.Month() != end.Month():
+		date = fmt.Sprintf("%s - %s", begin.Format("January"), end.Format("January 2006"))
+	default:
+		date = begin.Format("January 2006")
+	}
+
+	if place == "" {
+		return date
+	}
+
+	return place + ", " + date
+}
+
+// mostCommon returns the name with the highest count, ties are resolved alphabetically.
+func mostCommon(counts map[string]int) string {
+	names := make([]string, 0, len(counts))
+
+	for name := range counts {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var result string
+
+	for _, name := range names {
+		if result == "" || counts[name] > counts[result] {
+			result = name
+		}
+	}
+
+	return result
+}
+
+// distance returns the great circle distance between two coordinates in km.
+func distance(lat1, lng1, lat2, lng2 float64) float64 {
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLng := (lng2 - lng1) * rad
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLng/2)*math.Sin(dLng/2)
+
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
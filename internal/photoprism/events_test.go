@@ -0,0 +1,114 @@
+package photoprism
+
+import (
+	"testing"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/stretchr/testify/assert"
+)
+
+// eventPhotos returns n photos taken every interval starting at begin at the given location.
+func eventPhotos(begin time.Time, n int, interval time.Duration, lat, lng float64, city, country string) (result []query.EventPhoto) {
+	for i := 0; i < n; i++ {
+		result = append(result, query.EventPhoto{
+			PhotoUUID:    begin.Add(time.Duration(i) * interval).Format("20060102150405"),
+			TakenAt:      begin.Add(time.Duration(i) * interval),
+			PhotoLat:     lat,
+			PhotoLng:     lng,
+			PhotoCountry: country,
+			LocCity:      city,
+		})
+	}
+
+	return result
+}
+
+func TestClusterEvents(t *testing.T) {
+	berlin := time.Date(2019, 5, 1, 10, 0, 0, 0, time.UTC)
+	barcelona := time.Date(2019, 6, 10, 10, 0, 0, 0, time.UTC)
+
+	var photos []query.EventPhoto
+
+	// Home with a birthday party and a few unrelated photos.
+	photos = append(photos, eventPhotos(berlin, 20, 10*time.Minute, 52.52, 13.40, "Berlin", "de")...)
+	photos = append(photos, eventPhotos(berlin.AddDate(0, 0, 5), 3, time.Hour, 52.52, 13.40, "Berlin", "de")...)
+	photos = append(photos, eventPhotos(berlin.AddDate(0, 0, 20), 15, 10*time.Minute, 52.51, 13.39, "Berlin", "de")...)
+
+	// Trip with two days without photos.
+	photos = append(photos, eventPhotos(barcelona, 12, time.Hour, 41.38, 2.17, "Barcelona", "es")...)
+	photos = append(photos, eventPhotos(barcelona.AddDate(0, 0, 2), 12, time.Hour, 41.39, 2.16, "Barcelona", "es")...)
+
+	clusters := ClusterEvents(photos)
+
+	if len(clusters) != 3 {
+		t.Fatalf("expected 3 events, found %d", len(clusters))
+	}
+
+	assert.Equal(t, entity.EventTypeEvent, clusters[0].Type)
+	assert.Len(t, clusters[0].Photos, 20)
+	assert.Equal(t, entity.EventTypeEvent, clusters[1].Type)
+	assert.Len(t, clusters[1].Photos, 15)
+	assert.Equal(t, entity.EventTypeTrip, clusters[2].Type)
+	assert.Len(t, clusters[2].Photos, 24)
+
+	m := clusters[2].Event()
+
+	assert.Equal(t, "Barcelona, June 2019", m.EventName)
+	assert.Equal(t, "barcelona-june-2019", m.EventSlug)
+	assert.Equal(t, barcelona, m.EventBegin)
+	assert.InDelta(t, 41.385, m.EventLat, 0.001)
+	assert.True(t, m.EventDist > 0 && m.EventDist < 2)
+}
+
+func TestClusterEvents_NoLocation(t *testing.T) {
+	photos := eventPhotos(time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC), 10, time.Minute, 0, 0, "", "zz")
+
+	clusters := ClusterEvents(photos)
+
+	if len(clusters) != 1 {
+		t.Fatalf("expected 1 event, found %d", len(clusters))
+	}
+
+	assert.Equal(t, "January 2020", clusters[0].Event().EventName)
+}
+
+func TestClusterEvents_Owners(t *testing.T) {
+	begin := time.Date(2019, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	alice := eventPhotos(begin, 6, 10*time.Minute, 52.52, 13.40, "Berlin", "de")
+	bob := eventPhotos(begin.Add(5*time.Minute), 10, 10*time.Minute, 48.14, 11.58, "Munich", "de")
+
+	for i := range alice {
+		alice[i].OwnerUUID = "uqxetse3cy5eo9z2"
+	}
+
+	for i := range bob {
+		bob[i].OwnerUUID = "uqxc08w3d0ej2283"
+	}
+
+	clusters := ClusterEvents(append(alice, bob...))
+
+	if len(clusters) != 1 {
+		t.Fatalf("expected 1 event, found %d", len(clusters))
+	}
+
+	assert.Equal(t, "uqxc08w3d0ej2283", clusters[0].OwnerUUID)
+	assert.Len(t, clusters[0].Photos, 10)
+	assert.Equal(t, "uqxc08w3d0ej2283", clusters[0].Event().OwnerUUID)
+	assert.Equal(t, "Munich, May 2019", clusters[0].Event().EventName)
+}
+
+func TestEventName(t *testing.T) {
+	begin := time.Date(2019, 6, 28, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "Barcelona, June 2019", eventName("Barcelona", begin, begin))
+	assert.Equal(t, "Spain, June - July 2019", eventName("Spain", begin, begin.AddDate(0, 0, 5)))
+	assert.Equal(t, "December 2019 - January 2020", eventName("", begin.AddDate(0, 6, 0), begin.AddDate(0, 7, 0)))
+}
+
+func TestDistance(t *testing.T) {
+	assert.InDelta(t, 1500, distance(41.38, 2.17, 52.52, 13.40), 50)
+	assert.Equal(t, 0.0, distance(52.52, 13.40, 52.52, 13.40))
+}
@@ -77,6 +77,7 @@ func (imp *Import) Start(opt ImportOptions) {
 	}
 
 	indexOpt := IndexOptionsAll()
+	indexOpt.OwnerUUID = opt.OwnerUUID
 
 	err := filepath.Walk(importPath, func(fileName string, fileInfo os.FileInfo, err error) error {
 		defer func() {
@@ -6,6 +6,7 @@ type ImportOptions struct {
 	RemoveDotFiles         bool
 	RemoveExistingFiles    bool
 	RemoveEmptyDirectories bool
+	OwnerUUID              string
 }
 
 // ImportOptionsCopy returns import options for copying files to originals (read-only).
@@ -293,6 +293,15 @@ func (ind *Index) MediaFile(m *MediaFile, o IndexOptions, originalName string) (
 			file.FileDiff = p.Luminance.Diff()
 			file.FileChroma = p.Chroma.Value()
 		}
+
+		file.FileColorProfile = m.ColorProfile()
+
+		// Placeholder shown while thumbnails are loading
+		if hash, err := m.BlurHash(ind.thumbnailsPath()); err != nil {
+			log.Errorf("index: %s", err.Error())
+		} else {
+			file.FileBlurHash = hash
+		}
 	}
 
 	if m.IsJpeg() && (fileChanged || o.UpdateSize) {
@@ -341,6 +350,12 @@ func (ind *Index) MediaFile(m *MediaFile, o IndexOptions, originalName string) (
 	} else {
 		photo.PhotoFavorite = false
 
+		// New photos imported by a user are private until shared.
+		if o.OwnerUUID != "" {
+			photo.OwnerUUID = o.OwnerUUID
+			photo.PhotoVisibility = entity.VisibilityPrivate
+		}
+
 		if err := ind.db.Create(&photo).Error; err != nil {
 			log.Errorf("index: %s", err)
 			result.Status = IndexFailed
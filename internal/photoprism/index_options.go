@@ -15,13 +15,14 @@ type IndexOptions struct {
 	UpdateKeywords bool
 	UpdateXMP      bool
 	UpdateExif     bool
+	OwnerUUID      string
 }
 
 func (o *IndexOptions) UpdateAny() bool {
 	v := reflect.ValueOf(o).Elem()
 
 	for i := 0; i < v.NumField(); i++ {
-		if v.Field(i).Bool() {
+		if f := v.Field(i); f.Kind() == reflect.Bool && f.Bool() {
 			return true
 		}
 	}
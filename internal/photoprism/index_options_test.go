@@ -22,6 +22,12 @@ func TestIndexOptions_UpdateAny(t *testing.T) {
 		result := IndexOptionsNone()
 		assert.False(t, result.UpdateAny())
 	})
+
+	t.Run("owner", func(t *testing.T) {
+		result := IndexOptionsNone()
+		result.OwnerUUID = "u000000000000001"
+		assert.False(t, result.UpdateAny())
+	})
 }
 
 func TestIndexOptions_SkipUnchanged(t *testing.T) {
@@ -675,6 +675,26 @@ func (m *MediaFile) Orientation() int {
 	return 1
 }
 
+// ColorProfile returns the color space of the embedded ICC profile, or an empty string if there is none.
+func (m *MediaFile) ColorProfile() string {
+	if !m.IsJpeg() {
+		return ""
+	}
+
+	profile, err := thumb.FileProfile(m.FileName())
+
+	if err != nil {
+		log.Warnf("mediafile: %s", err)
+		return ""
+	}
+
+	if profile == nil {
+		return ""
+	}
+
+	return profile.ColorSpace()
+}
+
 // Thumbnail returns a thumbnail filename.
 func (m *MediaFile) Thumbnail(path string, typeName string) (filename string, err error) {
 	thumbType, ok := thumb.Types[typeName]
@@ -744,7 +764,7 @@ func (m *MediaFile) ResampleDefault(thumbPath string, force bool) (err error) {
 			}
 
 			if originalImg == nil {
-				img, err := imaging.Open(m.FileName(), imaging.AutoOrientation(true))
+				img, err := thumb.Open(m.FileName())
 
 				if err != nil {
 					log.Errorf("mediafile: can't open \"%s\" (%s)", m.FileName(), err.Error())
@@ -23,6 +23,8 @@ type AlbumResult struct {
 	AlbumFavorite    bool
 	AlbumDescription string
 	AlbumNotes       string
+	AlbumFilter      string
+	AlbumBlurHash    string
 	LinkCount        int
 }
 
@@ -39,7 +41,21 @@ func (q *Query) AlbumByUUID(albumUUID string) (album entity.Album, err error) {
 func (q *Query) AlbumThumbByUUID(albumUUID string) (file entity.File, err error) {
 	// q.db.LogMode(true)
 
-	if err := q.db.Where("files.file_primary AND files.deleted_at IS NULL").
+	if album, err := q.AlbumByUUID(albumUUID); err == nil && album.IsSmart() {
+		results, err := q.Photos(form.PhotoSearch{Album: albumUUID, Count: 1})
+
+		if err != nil {
+			return file, err
+		}
+
+		if len(results) == 0 {
+			return file, fmt.Errorf("album %s is empty", albumUUID)
+		}
+
+		return q.FileByUUID(results[0].FileUUID)
+	}
+
+	if err := q.visibleFiles(q.db).Where("files.file_primary AND files.deleted_at IS NULL").
 		Joins("JOIN albums ON albums.album_uuid = ?", albumUUID).
 		Joins("JOIN photos_albums pa ON pa.album_uuid = albums.album_uuid AND pa.photo_uuid = files.photo_uuid").
 		First(&file).Error; err != nil {
@@ -59,15 +75,27 @@ func (q *Query) Albums(f form.AlbumSearch) (results []AlbumResult, err error) {
 
 	s := q.db.NewScope(nil).DB()
 
+	// The placeholder must be of the same photo as the cover, see AlbumThumbByUUID.
+	blurWhere, blurArgs := q.blurHashVisibility()
+
 	s = s.Table("albums").
 		Select(`albums.*, 
 			COUNT(photos_albums.album_uuid) AS album_count,
-			COUNT(links.link_token) AS link_count`).
+			COUNT(links.link_token) AS link_count,
+			(SELECT f.file_blur_hash FROM files f 
+			JOIN photos_albums pa ON pa.photo_uuid = f.photo_uuid 
+			JOIN photos ON photos.id = f.photo_id
+			WHERE pa.album_uuid = albums.album_uuid AND f.file_primary AND f.deleted_at IS NULL`+blurWhere+` 
+			ORDER BY f.id LIMIT 1) AS album_blur_hash`, blurArgs...).
 		Joins("LEFT JOIN photos_albums ON photos_albums.album_uuid = albums.album_uuid").
 		Joins("LEFT JOIN links ON links.share_uuid = albums.album_uuid").
 		Where("albums.deleted_at IS NULL").
 		Group("albums.id")
 
+	if where, args := q.albumVisibility(); where != "" {
+		s = s.Where(where, args...)
+	}
+
 	if f.ID != "" {
 		s = s.Where("albums.album_uuid = ?", f.ID)
 
@@ -75,7 +103,7 @@ func (q *Query) Albums(f form.AlbumSearch) (results []AlbumResult, err error) {
 			return results, result.Error
 		}
 
-		return results, nil
+		return q.smartAlbumCounts(results)
 	}
 
 	if f.Query != "" {
@@ -104,5 +132,33 @@ func (q *Query) Albums(f form.AlbumSearch) (results []AlbumResult, err error) {
 		return results, result.Error
 	}
 
+	return q.smartAlbumCounts(results)
+}
+
+// smartAlbumCounts sets the number of photos visible to the user in smart albums, as they have no album photos.
+func (q *Query) smartAlbumCounts(results []AlbumResult) ([]AlbumResult, error) {
+	for i, album := range results {
+		if album.AlbumFilter == "" {
+			continue
+		}
+
+		where, args, _, err := q.smartAlbumCondition(album.AlbumFilter)
+
+		if err != nil {
+			log.Errorf("albums: %s", err)
+			continue
+		}
+
+		s := q.db.Table("photos").Where("photos.deleted_at IS NULL").Where(where, args...)
+
+		if visWhere, visArgs := q.photoVisibility(); visWhere != "" {
+			s = s.Where(visWhere, visArgs...)
+		}
+
+		if err := s.Count(&results[i].AlbumCount).Error; err != nil {
+			return results, err
+		}
+	}
+
 	return results, nil
 }
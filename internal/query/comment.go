@@ -0,0 +1,40 @@
+package query
+
+import (
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/form"
+)
+
+// CommentByUUID returns a comment based on the UUID.
+func (q *Query) CommentByUUID(commentUUID string) (comment entity.Comment, err error) {
+	if err := q.db.Where("comment_uuid = ?", commentUUID).First(&comment).Error; err != nil {
+		return comment, err
+	}
+
+	return comment, nil
+}
+
+// Comments returns the comments on a photo or album, oldest first. Hidden comments are only included if requested.
+func (q *Query) Comments(f form.CommentSearch) (results []entity.Comment, err error) {
+	s := q.db.Order("created_at, id").Offset(f.Offset)
+
+	if f.Photo != "" {
+		s = s.Where("photo_uuid = ?", f.Photo)
+	} else {
+		s = s.Where("album_uuid = ? AND photo_uuid = ''", f.Album)
+	}
+
+	if !f.Hidden {
+		s = s.Where("comment_hidden = 0")
+	}
+
+	if f.Count > 0 {
+		s = s.Limit(f.Count)
+	}
+
+	if err := s.Find(&results).Error; err != nil {
+		return results, err
+	}
+
+	return results, nil
+}
@@ -0,0 +1,58 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery_Comments(t *testing.T) {
+	conf := config.TestConfig()
+
+	search := New(conf.Db())
+
+	visible, _ := entity.NewComment("Jane", "Visible")
+	visible.AlbumUUID = "3"
+	hidden, _ := entity.NewComment("Jane", "Hidden")
+	hidden.AlbumUUID = "3"
+	hidden.CommentHidden = true
+
+	for _, m := range []*entity.Comment{visible, hidden} {
+		if err := conf.Db().Create(m).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("visible only", func(t *testing.T) {
+		results, err := search.Comments(form.CommentSearch{Album: "3"})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, r := range results {
+			assert.False(t, r.CommentHidden)
+		}
+	})
+	t.Run("including hidden", func(t *testing.T) {
+		results, err := search.Comments(form.CommentSearch{Album: "3", Hidden: true})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.GreaterOrEqual(t, len(results), 2)
+	})
+	t.Run("by uuid", func(t *testing.T) {
+		m, err := search.CommentByUUID(visible.CommentUUID)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "Visible", m.CommentText)
+	})
+}
@@ -0,0 +1,121 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/pkg/capture"
+)
+
+// EventPhoto contains the time and location of a photo, which are used to detect events.
+type EventPhoto struct {
+	PhotoUUID    string
+	OwnerUUID    string
+	TakenAt      time.Time
+	PhotoLat     float64
+	PhotoLng     float64
+	PhotoCountry string
+	LocCity      string
+}
+
+// HasLocation returns true if the photo has coordinates.
+func (m EventPhoto) HasLocation() bool {
+	return m.PhotoLat != 0 || m.PhotoLng != 0
+}
+
+// EventResult contains an event and the number of photos visible to the user.
+type EventResult struct {
+	EventUUID        string
+	EventSlug        string
+	EventName        string
+	EventType        string
+	EventDescription string
+	EventBegin       time.Time
+	EventEnd         time.Time
+	EventLat         float64
+	EventLng         float64
+	EventDist        float64
+	OwnerUUID        string
+	PhotoCount       int
+}
+
+// EventPhotos returns all photos that are not archived sorted by owner and time taken.
+func (q *Query) EventPhotos() (results []EventPhoto, err error) {
+	err = q.db.Table("photos").
+		Select("photos.photo_uuid, photos.owner_uuid, photos.taken_at, photos.photo_lat, photos.photo_lng, photos.photo_country, places.loc_city").
+		Joins("LEFT JOIN places ON places.id = photos.place_id").
+		Where("photos.deleted_at IS NULL").
+		Order("photos.owner_uuid, photos.taken_at, photos.id").
+		Scan(&results).Error
+
+	return results, err
+}
+
+// EventByUUID returns an event based on the UUID.
+func (q *Query) EventByUUID(eventUUID string) (event entity.Event, err error) {
+	if err := q.db.Where("event_uuid = ?", eventUUID).First(&event).Error; err != nil {
+		return event, err
+	}
+
+	return event, nil
+}
+
+// Events searches events with photos visible to the user, the most recent first. Since names, locations
+// and dates of detected events are based on all their photos, events of other owners are only found if
+// all photos are visible to the user.
+func (q *Query) Events(f form.EventSearch) (results []EventResult, err error) {
+	defer log.Debug(capture.Time(time.Now(), fmt.Sprintf("events: %+v", f)))
+
+	visible := "1 = 1"
+	var args []interface{}
+
+	if where, visArgs := q.photoVisibility(); where != "" {
+		visible = where
+		args = visArgs
+	}
+
+	counts := `SELECT photos.event_uuid, SUM(CASE WHEN ` + visible + ` THEN 1 ELSE 0 END) AS photo_count, COUNT(*) AS photo_total
+		FROM photos WHERE photos.deleted_at IS NULL AND photos.event_uuid <> '' GROUP BY photos.event_uuid`
+
+	s := q.db.Table("events").
+		Select("events.*, c.photo_count").
+		Joins("JOIN ("+counts+") c ON c.event_uuid = events.event_uuid AND c.photo_count > 0", args...).
+		Where("events.deleted_at IS NULL")
+
+	if q.restricted() {
+		s = s.Where("events.owner_uuid = ? OR c.photo_count = c.photo_total", q.userUUID())
+	}
+
+	if f.Query != "" {
+		s = s.Where("LOWER(events.event_name) LIKE ?", "%"+strings.ToLower(f.Query)+"%")
+	}
+
+	if f.Type != "" {
+		s = s.Where("events.event_type = ?", f.Type)
+	}
+
+	if f.Year > 0 {
+		s = s.Where("YEAR(events.event_begin) = ?", f.Year)
+	}
+
+	s = s.Order("events.event_begin DESC").Offset(f.Offset)
+
+	if f.Count > 0 {
+		s = s.Limit(f.Count)
+	}
+
+	if err := s.Scan(&results).Error; err != nil {
+		return results, err
+	}
+
+	return results, nil
+}
+
+// eventCondition returns the condition for photos of an event with the given UUID or slug.
+func eventCondition(event string) (string, []interface{}) {
+	return "photos.event_uuid IN (SELECT e.event_uuid FROM events e WHERE e.deleted_at IS NULL AND (e.event_uuid = ? OR e.event_slug = ?))",
+		[]interface{}{event, event}
+}
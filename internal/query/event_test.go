@@ -0,0 +1,33 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventPhoto_HasLocation(t *testing.T) {
+	assert.False(t, EventPhoto{}.HasLocation())
+	assert.True(t, EventPhoto{PhotoLat: 41.38, PhotoLng: 2.17}.HasLocation())
+}
+
+func TestQuery_Events(t *testing.T) {
+	q := New(config.TestConfig().Db())
+
+	t.Run("all", func(t *testing.T) {
+		_, err := q.Events(form.EventSearch{Count: 10})
+
+		assert.Nil(t, err)
+	})
+	t.Run("trips", func(t *testing.T) {
+		results, err := q.Events(form.EventSearch{Type: "trip", Year: 2019, Count: 10})
+
+		assert.Nil(t, err)
+
+		for _, r := range results {
+			assert.Equal(t, "trip", r.EventType)
+		}
+	})
+}
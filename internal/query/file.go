@@ -1,6 +1,9 @@
 package query
 
-import "github.com/photoprism/photoprism/internal/entity"
+import (
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
 
 // Files finds files returning maximum results defined by limit
 // and finding them from an offest defined by offset.
@@ -14,7 +17,7 @@ func (q *Query) Files(limit int, offset int) (files []entity.File, err error) {
 
 // FilesByUUID
 func (q *Query) FilesByUUID(u []string, limit int, offset int) (files []entity.File, err error) {
-	if err := q.db.Where("(photo_uuid IN (?) AND file_primary = 1) OR file_uuid IN (?)", u, u).Preload("Photo").Limit(limit).Offset(offset).Find(&files).Error; err != nil {
+	if err := q.visibleFiles(q.db).Where("(files.photo_uuid IN (?) AND files.file_primary = 1) OR files.file_uuid IN (?)", u, u).Preload("Photo").Limit(limit).Offset(offset).Find(&files).Error; err != nil {
 		return files, err
 	}
 
@@ -47,3 +50,18 @@ func (q *Query) FileByHash(fileHash string) (file entity.File, err error) {
 
 	return file, nil
 }
+
+// BlurHashFiles returns JPEG files after the given ID that need a BlurHash, or all of them if force is true.
+func (q *Query) BlurHashFiles(afterID uint, limit int, force bool) (files []entity.File, err error) {
+	s := q.db.Where("id > ? AND file_type = ? AND file_missing = 0 AND deleted_at IS NULL", afterID, string(fs.TypeJpeg))
+
+	if !force {
+		s = s.Where("file_blur_hash = '' OR file_blur_hash IS NULL")
+	}
+
+	if err := s.Order("id").Limit(limit).Find(&files).Error; err != nil {
+		return files, err
+	}
+
+	return files, nil
+}
@@ -100,3 +100,25 @@ func TestQuery_FileByHash(t *testing.T) {
 		t.Log(file)
 	})
 }
+
+func TestQuery_BlurHashFiles(t *testing.T) {
+	conf := config.TestConfig()
+
+	search := New(conf.Db())
+
+	t.Run("all files", func(t *testing.T) {
+		files, err := search.BlurHashFiles(0, 1000, true)
+
+		assert.Nil(t, err)
+
+		for _, f := range files {
+			assert.Equal(t, "jpg", f.FileType)
+		}
+	})
+	t.Run("after last id", func(t *testing.T) {
+		files, err := search.BlurHashFiles(1000000, 1000, true)
+
+		assert.Nil(t, err)
+		assert.Empty(t, files)
+	})
+}
@@ -0,0 +1,127 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/pkg/capture"
+)
+
+// FolderResult contains a subfolder with the number of photos it contains including nested folders,
+// the date range of these photos and the file hash of a cover photo, favorites first.
+type FolderResult struct {
+	FolderUUID        string
+	FolderPath        string
+	FolderName        string
+	FolderTitle       string
+	FolderDescription string
+	PhotoCount        int
+	TakenAtMin        time.Time
+	TakenAtMax        time.Time
+	FileHash          string
+}
+
+// FolderByUUID returns a folder based on the UUID.
+func (q *Query) FolderByUUID(folderUUID string) (folder entity.Folder, err error) {
+	if err := q.db.Where("folder_uuid = ?", folderUUID).Preload("Links").First(&folder).Error; err != nil {
+		return folder, err
+	}
+
+	return folder, nil
+}
+
+// Folders returns the subfolders of a path that contain photos.
+func (q *Query) Folders(f form.FolderSearch) (results []FolderResult, err error) {
+	defer log.Debug(capture.Time(time.Now(), fmt.Sprintf("folders: %+v", f)))
+
+	folderPath := entity.FolderPath(f.Path)
+
+	// Use the same filters as photo search, so that counts match the photos shown when opening a folder.
+	s, err := q.photoSearch(form.PhotoSearch{Path: "/" + folderPath, Recursive: true, Public: f.Public, Safe: f.Safe, Count: 1})
+
+	if err != nil {
+		return results, err
+	}
+
+	prefix := ""
+
+	if folderPath != "" {
+		prefix = folderPath + "/"
+	}
+
+	s = s.Select(`SUBSTRING_INDEX(SUBSTR(photos.photo_path, ?), '/', 1) AS folder_name,
+		COUNT(DISTINCT photos.id) AS photo_count,
+		MIN(photos.taken_at) AS taken_at_min, MAX(photos.taken_at) AS taken_at_max,
+		SUBSTR(MIN(CONCAT(CASE WHEN photos.photo_favorite THEN '0' ELSE '1' END, files.file_hash)), 2) AS file_hash`, len(prefix)+1).
+		Where("photos.photo_path LIKE ?", likeEscape(prefix)+"_%").
+		Group("folder_name").
+		Order("folder_name", true).
+		Offset(f.Offset)
+
+	if f.Count > 0 {
+		s = s.Limit(f.Count)
+	} else {
+		s = s.Limit(-1)
+	}
+
+	if err := s.Scan(&results).Error; err != nil {
+		return results, err
+	}
+
+	if len(results) == 0 {
+		return results, nil
+	}
+
+	paths := make([]string, len(results))
+
+	for i := range results {
+		results[i].FolderPath = prefix + results[i].FolderName
+		paths[i] = results[i].FolderPath
+	}
+
+	// Add title and description of folders with metadata.
+	var folders []entity.Folder
+
+	if err := q.db.Where("folder_path IN (?)", paths).Find(&folders).Error; err != nil {
+		return results, err
+	}
+
+	meta := make(map[string]entity.Folder, len(folders))
+
+	for _, m := range folders {
+		meta[m.FolderPath] = m
+	}
+
+	for i := range results {
+		if m, ok := meta[results[i].FolderPath]; ok {
+			results[i].FolderUUID = m.FolderUUID
+			results[i].FolderTitle = m.FolderTitle
+			results[i].FolderDescription = m.FolderDescription
+		}
+	}
+
+	return results, nil
+}
+
+// folderCondition returns the condition for photos in a folder, optionally including subfolders.
+func folderCondition(folderPath string, recursive bool) (where string, args []interface{}) {
+	folderPath = entity.FolderPath(folderPath)
+
+	if !recursive {
+		return "photos.photo_path = ?", []interface{}{folderPath}
+	}
+
+	if folderPath == "" {
+		return "1 = 1", nil
+	}
+
+	return "(photos.photo_path = ? OR photos.photo_path LIKE ?)", []interface{}{folderPath, likeEscape(folderPath) + "/%"}
+}
+
+// likeEscape escapes wildcard characters, so that a string can be used as literal LIKE pattern.
+func likeEscape(s string) string {
+	return strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_").Replace(s)
+}
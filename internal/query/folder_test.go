@@ -0,0 +1,65 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery_Folders(t *testing.T) {
+	conf := config.TestConfig()
+
+	search := New(conf.Db())
+
+	t.Run("root", func(t *testing.T) {
+		results, err := search.Folders(form.FolderSearch{})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, r := range results {
+			assert.NotEmpty(t, r.FolderName)
+			assert.Equal(t, r.FolderName, r.FolderPath)
+			assert.True(t, r.PhotoCount > 0)
+			assert.False(t, r.TakenAtMin.After(r.TakenAtMax))
+		}
+	})
+
+	t.Run("subfolder", func(t *testing.T) {
+		results, err := search.Folders(form.FolderSearch{Path: "/2790/02/", Count: 10})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, r := range results {
+			assert.Equal(t, "2790/02/"+r.FolderName, r.FolderPath)
+		}
+	})
+}
+
+func TestFolderCondition(t *testing.T) {
+	t.Run("folder only", func(t *testing.T) {
+		where, args := folderCondition("/2020/Holiday/", false)
+		assert.Equal(t, "photos.photo_path = ?", where)
+		assert.Equal(t, []interface{}{"2020/Holiday"}, args)
+	})
+	t.Run("root folder only", func(t *testing.T) {
+		where, args := folderCondition("/", false)
+		assert.Equal(t, "photos.photo_path = ?", where)
+		assert.Equal(t, []interface{}{""}, args)
+	})
+	t.Run("recursive", func(t *testing.T) {
+		where, args := folderCondition("2020/50%_off", true)
+		assert.Equal(t, "(photos.photo_path = ? OR photos.photo_path LIKE ?)", where)
+		assert.Equal(t, []interface{}{"2020/50%_off", "2020/50\\%\\_off/%"}, args)
+	})
+	t.Run("root recursive", func(t *testing.T) {
+		where, args := folderCondition("", true)
+		assert.Equal(t, "1 = 1", where)
+		assert.Empty(t, args)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jinzhu/gorm"
 	"github.com/photoprism/photoprism/internal/form"
 	"github.com/photoprism/photoprism/pkg/capture"
 	"github.com/photoprism/photoprism/pkg/pluscode"
@@ -13,35 +14,69 @@ import (
 
 // GeoResult represents a photo for displaying it on a map.
 type GeoResult struct {
-	ID         string    `json:"ID"`
-	PhotoLat   float64   `json:"Lat"`
-	PhotoLng   float64   `json:"Lng"`
-	PhotoUUID  string    `json:"PhotoUUID"`
-	PhotoTitle string    `json:"PhotoTitle"`
-	FileHash   string    `json:"FileHash"`
-	FileWidth  int       `json:"FileWidth"`
-	FileHeight int       `json:"FileHeight"`
-	TakenAt    time.Time `json:"TakenAt"`
+	ID           string    `json:"ID"`
+	PhotoLat     float64   `json:"Lat"`
+	PhotoLng     float64   `json:"Lng"`
+	PhotoUUID    string    `json:"PhotoUUID"`
+	PhotoTitle   string    `json:"PhotoTitle"`
+	FileHash     string    `json:"FileHash"`
+	FileWidth    int       `json:"FileWidth"`
+	FileHeight   int       `json:"FileHeight"`
+	FileBlurHash string    `json:"FileBlurHash"`
+	TakenAt      time.Time `json:"TakenAt"`
+	Distance     float64   `json:"Distance,omitempty"`
 }
 
 // Geo searches for photos based on a Form and returns a PhotoResult slice.
 func (q *Query) Geo(f form.GeoSearch) (results []GeoResult, err error) {
-	if err := f.ParseQueryString(); err != nil {
+	s, err := q.geoSearch(f)
+
+	if err != nil {
 		return results, err
 	}
 
+	if result := s.Scan(&results); result.Error != nil {
+		return results, result.Error
+	}
+
+	return results, nil
+}
+
+// geoSearch returns the database scope of a map search including sort order.
+func (q *Query) geoSearch(f form.GeoSearch) (s *gorm.DB, err error) {
+	if err := f.ParseQueryString(); err != nil {
+		return nil, err
+	}
+
 	defer log.Debug(capture.Time(time.Now(), fmt.Sprintf("search: %+v", f)))
 
-	s := q.db.NewScope(nil).DB()
+	s = q.db.NewScope(nil).DB()
+
+	geo := geoFilter{Lat: f.Lat, Lng: f.Lng, Dist: f.Dist, BBox: f.BBox, Polygon: f.Polygon}
+
+	distance := "0"
+	var distanceArgs []interface{}
+
+	// Cell filters replace the radius search.
+	if f.S2 != "" || f.Olc != "" {
+		geo.Lat, geo.Lng = 0, 0
+	} else if geo.radius() {
+		distance, distanceArgs = distanceExpr(f.Lat, f.Lng)
+	}
 
 	s = s.Table("photos").
 		Select(`photos.id, photos.photo_uuid, photos.photo_lat, photos.photo_lng, photos.photo_title, photos.taken_at, 
-		files.file_hash, files.file_width, files.file_height`).
+		files.file_hash, files.file_width, files.file_height, files.file_blur_hash, `+distance+` AS distance`, distanceArgs...).
 		Joins(`JOIN files ON files.photo_id = photos.id 
 		AND files.file_missing = 0 AND files.file_primary AND files.deleted_at IS NULL`).
 		Where("photos.deleted_at IS NULL").
-		Where("photos.photo_lat <> 0").
-		Group("photos.id, files.id")
+		Where("photos.photo_lat <> 0")
+
+	if where, args := q.photoVisibility(); where != "" {
+		s = s.Where(where, args...)
+	}
+
+	s = s.Group("photos.id, files.id")
 
 	if f.Query != "" {
 		s = s.Joins("LEFT JOIN photos_keywords ON photos_keywords.photo_id = photos.id").
@@ -55,19 +90,10 @@ func (q *Query) Geo(f form.GeoSearch) (results []GeoResult, err error) {
 	} else if f.Olc != "" {
 		s2Min, s2Max := s2.Range(pluscode.S2(f.Olc), 7)
 		s = s.Where("photos.location_id BETWEEN ? AND ?", s2Min, s2Max)
-	} else {
-		// Inaccurate distance search, but probably 'good enough' for now
-		if f.Lat > 0 {
-			latMin := f.Lat - SearchRadius*float64(f.Dist)
-			latMax := f.Lat + SearchRadius*float64(f.Dist)
-			s = s.Where("photos.photo_lat BETWEEN ? AND ?", latMin, latMax)
-		}
-
-		if f.Lng > 0 {
-			lngMin := f.Lng - SearchRadius*float64(f.Dist)
-			lngMax := f.Lng + SearchRadius*float64(f.Dist)
-			s = s.Where("photos.photo_lng BETWEEN ? AND ?", lngMin, lngMax)
-		}
+	}
+
+	if s, _, _, err = geo.apply(s); err != nil {
+		return nil, err
 	}
 
 	if !f.Before.IsZero() {
@@ -78,11 +104,11 @@ func (q *Query) Geo(f form.GeoSearch) (results []GeoResult, err error) {
 		s = s.Where("photos.taken_at >= ?", f.After.Format("2006-01-02"))
 	}
 
-	s = s.Order("taken_at, photos.photo_uuid")
-
-	if result := s.Scan(&results); result.Error != nil {
-		return results, result.Error
+	if geo.radius() {
+		s = s.Order("distance, taken_at, photos.photo_uuid")
+	} else {
+		s = s.Order("taken_at, photos.photo_uuid")
 	}
 
-	return results, nil
+	return s, nil
 }
@@ -0,0 +1,107 @@
+package query
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/pkg/s2"
+)
+
+// Map zoom levels are mapped to S2 cell levels within this range.
+const (
+	MinClusterLevel = 2
+	MaxClusterLevel = 20
+)
+
+// GeoCluster represents photos in the same S2 cell, so that they can be displayed as a single map marker.
+type GeoCluster struct {
+	CellID   string  `json:"CellID"`
+	Lat      float64 `json:"Lat"`
+	Lng      float64 `json:"Lng"`
+	Count    int     `json:"Count"`
+	FileHash string  `json:"FileHash"`
+}
+
+// geoClusterRow represents photos with the same location token prefix.
+type geoClusterRow struct {
+	LocationID string
+	Lat        float64
+	Lng        float64
+	Count      int
+	FileHash   string
+}
+
+// GeoClusterLevel returns the S2 cell level for a map zoom level, so that a cell is about a quarter tile wide.
+func GeoClusterLevel(zoom int) int {
+	level := zoom
+
+	if level < MinClusterLevel {
+		return MinClusterLevel
+	} else if level > MaxClusterLevel {
+		return MaxClusterLevel
+	}
+
+	return level
+}
+
+// GeoClusters groups photos matching the search form by S2 cell at the given level and returns one cluster
+// per cell with the number of photos, their centroid and the file hash of a representative photo, favorites first.
+func (q *Query) GeoClusters(f form.GeoSearch, level int) (results []GeoCluster, err error) {
+	s, err := q.geoSearch(f)
+
+	if err != nil {
+		return results, err
+	}
+
+	// Group by location token prefix first, so that the location index can be used.
+	prefix := fmt.Sprintf("LEFT(photos.location_id, %d)", s2.PrefixLength(level))
+
+	var rows []geoClusterRow
+
+	if err := s.Select(`MIN(photos.location_id) AS location_id, COUNT(DISTINCT photos.id) AS count,
+		AVG(photos.photo_lat) AS lat, AVG(photos.photo_lng) AS lng,
+		SUBSTR(MIN(CONCAT(CASE WHEN photos.photo_favorite THEN '0' ELSE '1' END, files.file_hash)), 2) AS file_hash`).
+		Where("photos.location_id <> ''").
+		Group(prefix).
+		Order("count DESC", true).
+		Scan(&rows).Error; err != nil {
+		return results, err
+	}
+
+	return mergeClusters(rows, level), nil
+}
+
+// mergeClusters merges rows in the same cell, rows must be sorted by count so that the representative
+// photo of the largest group is used.
+func mergeClusters(rows []geoClusterRow, level int) (results []GeoCluster) {
+	cells := make(map[string]int)
+
+	for _, row := range rows {
+		cellID := s2.Parent(row.LocationID, level)
+
+		if cellID == "" {
+			continue
+		}
+
+		i, ok := cells[cellID]
+
+		if !ok {
+			cells[cellID] = len(results)
+			results = append(results, GeoCluster{CellID: cellID, Lat: row.Lat, Lng: row.Lng, Count: row.Count, FileHash: row.FileHash})
+			continue
+		}
+
+		c := &results[i]
+		total := float64(c.Count + row.Count)
+		c.Lat = (c.Lat*float64(c.Count) + row.Lat*float64(row.Count)) / total
+		c.Lng = (c.Lng*float64(c.Count) + row.Lng*float64(row.Count)) / total
+		c.Count += row.Count
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Count > results[j].Count
+	})
+
+	return results
+}
@@ -0,0 +1,44 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/photoprism/photoprism/pkg/s2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeoClusterLevel(t *testing.T) {
+	assert.Equal(t, MinClusterLevel, GeoClusterLevel(0))
+	assert.Equal(t, 12, GeoClusterLevel(12))
+	assert.Equal(t, MaxClusterLevel, GeoClusterLevel(22))
+}
+
+func TestMergeClusters(t *testing.T) {
+	berlin := s2.TokenLevel(52.5, 13.4, 21)
+	potsdam := s2.TokenLevel(52.4, 13.06, 21)
+	paris := s2.TokenLevel(48.86, 2.35, 21)
+
+	rows := []geoClusterRow{
+		{LocationID: paris, Lat: 48.86, Lng: 2.35, Count: 6, FileHash: "a"},
+		{LocationID: berlin, Lat: 52.5, Lng: 13.4, Count: 3, FileHash: "b"},
+		{LocationID: potsdam, Lat: 52.4, Lng: 13.06, Count: 2, FileHash: "c"},
+		{LocationID: "invalid", Count: 1},
+	}
+
+	t.Run("merged", func(t *testing.T) {
+		result := mergeClusters(rows, 5)
+
+		assert.Len(t, result, 2)
+		assert.Equal(t, s2.Parent(paris, 5), result[0].CellID)
+		assert.Equal(t, 6, result[0].Count)
+		assert.Equal(t, 5, result[1].Count)
+		assert.Equal(t, "b", result[1].FileHash)
+		assert.InDelta(t, 52.46, result[1].Lat, 0.001)
+		assert.InDelta(t, 13.264, result[1].Lng, 0.001)
+	})
+	t.Run("separate", func(t *testing.T) {
+		result := mergeClusters(rows, 15)
+
+		assert.Len(t, result, 3)
+	})
+}
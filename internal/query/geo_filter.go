@@ -0,0 +1,155 @@
+package query
+
+import (
+	"math"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/internal/form"
+)
+
+// EarthRadius is the mean radius of the earth in km.
+const EarthRadius = 6371.0
+
+// DefaultDist and MaxDist limit the search radius in km.
+const (
+	DefaultDist = 20
+	MaxDist     = 5000
+)
+
+// geoFilter contains the location filters shared by photo and map searches.
+type geoFilter struct {
+	Lat     float64
+	Lng     float64
+	Dist    uint // Radius in km
+	BBox    string
+	Polygon string
+}
+
+// radius returns true if photos are searched within a radius around a position.
+func (g geoFilter) radius() bool {
+	return g.Lat != 0 || g.Lng != 0
+}
+
+// apply adds the location conditions to a search. If a radius is given, it also returns an SQL expression
+// for the distance in km, so that results can be sorted by distance.
+func (g geoFilter) apply(s *gorm.DB) (result *gorm.DB, distance string, distanceArgs []interface{}, err error) {
+	if g.BBox != "" {
+		bbox, err := form.ParseBBox(g.BBox)
+
+		if err != nil {
+			return s, distance, distanceArgs, err
+		}
+
+		where, args := bboxCondition(bbox)
+		s = s.Where(where, args...)
+	}
+
+	if g.Polygon != "" {
+		polygon, err := form.ParsePolygon(g.Polygon)
+
+		if err != nil {
+			return s, distance, distanceArgs, err
+		}
+
+		where, args := bboxCondition(polygon.Bounds())
+		s = s.Where(where, args...)
+
+		where, args = polygonCondition(polygon)
+		s = s.Where(where, args...)
+	}
+
+	if !g.radius() {
+		return s, distance, distanceArgs, nil
+	}
+
+	if g.Dist == 0 {
+		g.Dist = DefaultDist
+	} else if g.Dist > MaxDist {
+		g.Dist = MaxDist
+	}
+
+	// Use the bounding box of the circle first, so that indexes can be used.
+	where, args := bboxCondition(radiusBounds(g.Lat, g.Lng, float64(g.Dist)))
+	s = s.Where(where, args...)
+
+	distance, distanceArgs = distanceExpr(g.Lat, g.Lng)
+	s = s.Where(distance+" <= ?", append(distanceArgs, float64(g.Dist))...)
+
+	return s, distance, distanceArgs, nil
+}
+
+// distanceExpr returns an SQL expression for the great-circle distance in km using the haversine formula.
+func distanceExpr(lat, lng float64) (string, []interface{}) {
+	return "(2 * ? * ASIN(LEAST(1, SQRT(POWER(SIN(RADIANS(photos.photo_lat - ?) / 2), 2) + " +
+			"COS(RADIANS(?)) * COS(RADIANS(photos.photo_lat)) * POWER(SIN(RADIANS(photos.photo_lng - ?) / 2), 2)))))",
+		[]interface{}{EarthRadius, lat, lat, lng}
+}
+
+// radiusBounds returns the bounding box of a circle around a position, the radius is given in km.
+func radiusBounds(lat, lng, dist float64) form.BBox {
+	latDelta := dist / EarthRadius * 180 / math.Pi
+	south, north := math.Max(lat-latDelta, -90), math.Min(lat+latDelta, 90)
+
+	// Include all longitudes if the circle contains a pole.
+	if south == -90 || north == 90 {
+		return form.BBox{-180, south, 180, north}
+	}
+
+	lngDelta := math.Asin(math.Min(1, math.Sin(dist/EarthRadius)/math.Cos(lat*math.Pi/180))) * 180 / math.Pi
+
+	if lngDelta >= 180 {
+		return form.BBox{-180, south, 180, north}
+	}
+
+	west, east := lng-lngDelta, lng+lngDelta
+
+	if west < -180 {
+		west += 360
+	}
+
+	if east > 180 {
+		east -= 360
+	}
+
+	return form.BBox{west, south, east, north}
+}
+
+// bboxCondition returns the SQL condition for a bounding box, which may cross the antimeridian.
+func bboxCondition(b form.BBox) (string, []interface{}) {
+	if b[0] <= b[2] {
+		return "photos.photo_lat BETWEEN ? AND ? AND photos.photo_lng BETWEEN ? AND ?",
+			[]interface{}{b[1], b[3], b[0], b[2]}
+	}
+
+	return "photos.photo_lat BETWEEN ? AND ? AND (photos.photo_lng >= ? OR photos.photo_lng <= ?)",
+		[]interface{}{b[1], b[3], b[0], b[2]}
+}
+
+// polygonCondition returns the SQL condition for a polygon using the crossing number algorithm:
+// a position is inside if a ray starting at the position crosses an odd number of edges.
+func polygonCondition(p form.Polygon) (string, []interface{}) {
+	var crossings []string
+	var args []interface{}
+
+	for i := range p {
+		a, b := p[i], p[(i+len(p)-1)%len(p)]
+
+		// Horizontal edges are never crossed.
+		if a[1] == b[1] {
+			continue
+		}
+
+		slope := (b[0] - a[0]) / (b[1] - a[1])
+
+		crossings = append(crossings, "CASE WHEN (? > photos.photo_lat) <> (? > photos.photo_lat) "+
+			"AND photos.photo_lng < ? * (photos.photo_lat - ?) + ? THEN 1 ELSE 0 END")
+		args = append(args, a[1], b[1], slope, a[1], a[0])
+	}
+
+	if len(crossings) == 0 {
+		return "1 = 0", args
+	}
+
+	return "MOD(" + strings.Join(crossings, " + ") + ", 2) = 1", args
+}
@@ -0,0 +1,67 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBBoxCondition(t *testing.T) {
+	t.Run("regular", func(t *testing.T) {
+		where, args := bboxCondition(form.BBox{13.2, 52.4, 13.6, 52.6})
+
+		assert.Equal(t, "photos.photo_lat BETWEEN ? AND ? AND photos.photo_lng BETWEEN ? AND ?", where)
+		assert.Equal(t, []interface{}{52.4, 52.6, 13.2, 13.6}, args)
+	})
+	t.Run("antimeridian", func(t *testing.T) {
+		where, args := bboxCondition(form.BBox{170, -20, -170, -10})
+
+		assert.Equal(t, "photos.photo_lat BETWEEN ? AND ? AND (photos.photo_lng >= ? OR photos.photo_lng <= ?)", where)
+		assert.Equal(t, []interface{}{-20.0, -10.0, 170.0, -170.0}, args)
+	})
+}
+
+func TestRadiusBounds(t *testing.T) {
+	t.Run("berlin", func(t *testing.T) {
+		result := radiusBounds(52.5, 13.4, 10)
+
+		assert.InDelta(t, 52.41, result[1], 0.01)
+		assert.InDelta(t, 52.59, result[3], 0.01)
+		assert.InDelta(t, 13.25, result[0], 0.01)
+		assert.InDelta(t, 13.55, result[2], 0.01)
+	})
+	t.Run("antimeridian", func(t *testing.T) {
+		result := radiusBounds(0, 179.95, 20)
+
+		assert.Greater(t, result[0], result[2])
+	})
+	t.Run("pole", func(t *testing.T) {
+		result := radiusBounds(89.95, 0, 20)
+
+		assert.Equal(t, form.BBox{-180, result[1], 180, 90}, result)
+	})
+}
+
+func TestPolygonCondition(t *testing.T) {
+	t.Run("triangle", func(t *testing.T) {
+		where, args := polygonCondition(form.Polygon{{0, 0}, {10, 0}, {5, 10}})
+
+		// The horizontal edge is skipped.
+		assert.Equal(t, "MOD(CASE WHEN (? > photos.photo_lat) <> (? > photos.photo_lat) AND photos.photo_lng < ? * (photos.photo_lat - ?) + ? THEN 1 ELSE 0 END + "+
+			"CASE WHEN (? > photos.photo_lat) <> (? > photos.photo_lat) AND photos.photo_lng < ? * (photos.photo_lat - ?) + ? THEN 1 ELSE 0 END, 2) = 1", where)
+		assert.Equal(t, []interface{}{0.0, 10.0, 0.5, 0.0, 0.0, 10.0, 0.0, -0.5, 10.0, 5.0}, args)
+	})
+	t.Run("flat", func(t *testing.T) {
+		where, _ := polygonCondition(form.Polygon{{0, 0}, {10, 0}, {5, 0}})
+
+		assert.Equal(t, "1 = 0", where)
+	})
+}
+
+func TestDistanceExpr(t *testing.T) {
+	where, args := distanceExpr(52.5, 13.4)
+
+	assert.Contains(t, where, "ASIN(")
+	assert.Equal(t, []interface{}{EarthRadius, 52.5, 52.5, 13.4}, args)
+}
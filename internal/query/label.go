@@ -26,6 +26,7 @@ type LabelResult struct {
 	LabelFavorite    bool
 	LabelDescription string
 	LabelNotes       string
+	LabelBlurHash    string
 }
 
 // LabelBySlug returns a Label based on the slug name.
@@ -64,7 +65,7 @@ func (q *Query) LabelThumbBySlug(labelSlug string) (file entity.File, err error)
 // LabelThumbByUUID returns a label preview file based on the label UUID.
 func (q *Query) LabelThumbByUUID(labelUUID string) (file entity.File, err error) {
 	// Search matching label
-	err = q.db.Where("files.file_primary AND files.deleted_at IS NULL").
+	err = q.visibleFiles(q.db).Where("files.file_primary AND files.deleted_at IS NULL").
 		Joins("JOIN labels ON labels.label_uuid = ?", labelUUID).
 		Joins("JOIN photos_labels ON photos_labels.label_id = labels.id AND photos_labels.photo_id = files.photo_id").
 		Order("photos_labels.label_uncertainty ASC").
@@ -75,7 +76,7 @@ func (q *Query) LabelThumbByUUID(labelUUID string) (file entity.File, err error)
 	}
 
 	// If failed, search for category instead
-	err = q.db.Where("files.file_primary AND files.deleted_at IS NULL").
+	err = q.visibleFiles(q.db).Where("files.file_primary AND files.deleted_at IS NULL").
 		Joins("JOIN photos_labels ON photos_labels.photo_id = files.photo_id").
 		Joins("JOIN categories c ON photos_labels.label_id = c.label_id").
 		Joins("JOIN labels ON c.category_id = labels.id AND labels.label_uuid= ?", labelUUID).
@@ -97,8 +98,15 @@ func (q *Query) Labels(f form.LabelSearch) (results []LabelResult, err error) {
 
 	// s.LogMode(true)
 
+	blurWhere, blurArgs := q.blurHashVisibility()
+
 	s = s.Table("labels").
-		Select(`labels.*`).
+		Select(`labels.*,
+			(SELECT f.file_blur_hash FROM files f 
+			JOIN photos_labels pl ON pl.photo_id = f.photo_id 
+			JOIN photos ON photos.id = f.photo_id
+			WHERE pl.label_id = labels.id AND f.file_primary AND f.deleted_at IS NULL`+blurWhere+` 
+			ORDER BY pl.label_uncertainty ASC, f.id LIMIT 1) AS label_blur_hash`, blurArgs...).
 		Where("labels.deleted_at IS NULL").
 		Group("labels.id")
 
@@ -0,0 +1,85 @@
+package query
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/pkg/capture"
+)
+
+// LinkResult contains a share link with the type and title of the shared item,
+// the password itself is never returned.
+type LinkResult struct {
+	LinkToken     string
+	ShareUUID     string
+	ShareType     string
+	ShareTitle    string
+	OwnerUUID     string
+	HasPassword   bool
+	LinkExpires   *time.Time
+	CanComment    bool
+	CanEdit       bool
+	LinkViews     uint
+	LinkDownloads uint
+	LastAccess    *time.Time
+	CreatedAt     time.Time
+}
+
+// LinkByToken returns a share link based on the URL token.
+func (q *Query) LinkByToken(token string) (link entity.Link, err error) {
+	if err := q.db.Where("link_token = ?", token).First(&link).Error; err != nil {
+		return link, err
+	}
+
+	return link, nil
+}
+
+// linkOwner is the owner of a shared item, folders have no owner and can only be shared by admins,
+// so their links belong to the default admin.
+const linkOwner = `CASE WHEN folders.id IS NOT NULL THEN ?
+	ELSE COALESCE(albums.owner_uuid, labels.owner_uuid, photos.owner_uuid, '') END`
+
+// Links searches share links, users who aren't admins only find links of their own items and items without owner.
+func (q *Query) Links(f form.LinkSearch) (results []LinkResult, err error) {
+	defer log.Debug(capture.Time(time.Now(), fmt.Sprintf("links: %+v", f)))
+
+	s := q.db.Table("links").
+		Select(`links.link_token, links.share_uuid, links.link_expires, links.can_comment, links.can_edit,
+		links.link_views, links.link_downloads, links.last_access, links.created_at,
+		links.link_password <> '' AS has_password,
+		CASE WHEN albums.id IS NOT NULL THEN 'album' WHEN labels.id IS NOT NULL THEN 'label'
+			WHEN folders.id IS NOT NULL THEN 'folder' WHEN photos.id IS NOT NULL THEN 'photo' ELSE '' END AS share_type,
+		COALESCE(albums.album_name, labels.label_name, NULLIF(folders.folder_title, ''), folders.folder_path, photos.photo_title, '') AS share_title,
+		`+linkOwner+` AS owner_uuid`, entity.Admin.UserUUID).
+		Joins("LEFT JOIN albums ON albums.album_uuid = links.share_uuid AND albums.deleted_at IS NULL").
+		Joins("LEFT JOIN labels ON labels.label_uuid = links.share_uuid AND labels.deleted_at IS NULL").
+		Joins("LEFT JOIN folders ON folders.folder_uuid = links.share_uuid AND folders.deleted_at IS NULL").
+		Joins("LEFT JOIN photos ON photos.photo_uuid = links.share_uuid AND photos.deleted_at IS NULL").
+		Where("links.deleted_at IS NULL")
+
+	if q.user != nil && q.restricted() {
+		s = s.Where(linkOwner+" IN ('', ?)", entity.Admin.UserUUID, q.user.UserUUID)
+	}
+
+	if f.Token != "" {
+		s = s.Where("links.link_token = ?", f.Token)
+	}
+
+	if f.Share != "" {
+		s = s.Where("links.share_uuid = ?", f.Share)
+	}
+
+	s = s.Order("links.created_at DESC").Offset(f.Offset)
+
+	if f.Count > 0 {
+		s = s.Limit(f.Count)
+	}
+
+	if err := s.Scan(&results).Error; err != nil {
+		return results, err
+	}
+
+	return results, nil
+}
@@ -0,0 +1,36 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery_LinkByToken(t *testing.T) {
+	conf := config.TestConfig()
+
+	search := New(conf.Db())
+
+	t.Run("not existing token", func(t *testing.T) {
+		_, err := search.LinkByToken("xxxxxxxxxx")
+		assert.Error(t, err)
+	})
+}
+
+func TestQuery_Links(t *testing.T) {
+	conf := config.TestConfig()
+
+	search := New(conf.Db())
+
+	t.Run("not existing token", func(t *testing.T) {
+		results, err := search.Links(form.LinkSearch{Token: "xxxxxxxxxx", Count: 10})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Empty(t, results)
+	})
+}
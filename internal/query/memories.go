@@ -0,0 +1,144 @@
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/pkg/capture"
+)
+
+// MemoriesCount is the default max number of photos per year.
+var MemoriesCount = 10
+
+// MemoriesWeek is the number of days before and after the date that belong to the same week.
+const MemoriesWeek = 3
+
+// Memory contains photos taken in a previous year, the best photo comes first.
+type Memory struct {
+	Year     int           `json:"year"`
+	YearsAgo int           `json:"yearsAgo"`
+	Photos   []PhotoResult `json:"photos"`
+}
+
+// MemoriesResult contains photos taken on the same day and in the same week in previous years.
+type MemoriesResult struct {
+	Date string   `json:"date"`
+	Day  []Memory `json:"day"`
+	Week []Memory `json:"week"`
+}
+
+// Count returns the number of photos taken on the same day in previous years.
+func (m MemoriesResult) Count() (count int) {
+	for _, memory := range m.Day {
+		count += len(memory.Photos)
+	}
+
+	return count
+}
+
+// Memories returns photos taken on the month and day of the form date in previous years, and photos taken
+// in the same week grouped by "N years ago". Private and NSFW photos are excluded unless requested.
+// As there is no rating yet, favorites and stories are considered best, followed by photos with higher resolution.
+func (q *Query) Memories(f form.Memories) (result MemoriesResult, err error) {
+	defer log.Debug(capture.Time(time.Now(), fmt.Sprintf("memories: %+v", f)))
+
+	if f.Date.IsZero() {
+		f.Date = time.Now()
+	}
+
+	if f.Count <= 0 || f.Count > 100 {
+		f.Count = MemoriesCount
+	}
+
+	date := time.Date(f.Date.Year(), f.Date.Month(), f.Date.Day(), 0, 0, 0, 0, time.UTC)
+	result.Date = date.Format("2006-01-02")
+
+	// Weeks around New Year span two years, so photos are compared with the date in the previous and next year too.
+	var conditions []string
+	var args []interface{}
+
+	for _, year := range []int{date.Year() - 1, date.Year(), date.Year() + 1} {
+		conditions = append(conditions, "ABS(DATEDIFF(DATE_ADD(DATE(photos.taken_at_local), INTERVAL (? - YEAR(photos.taken_at_local)) YEAR), ?)) <= ?")
+		args = append(args, year, result.Date, MemoriesWeek)
+	}
+
+	week, err := q.memoryPhotos(f, date, "("+strings.Join(conditions, " OR ")+")", args...)
+
+	if err != nil {
+		return result, err
+	}
+
+	result.Week = groupMemories(week, date, f.Count, func(p PhotoResult) bool { return true })
+	result.Day = groupMemories(week, date, f.Count, func(p PhotoResult) bool {
+		return p.TakenAtLocal.Month() == date.Month() && p.TakenAtLocal.Day() == date.Day()
+	})
+
+	return result, nil
+}
+
+// memoryPhotos returns photos taken at least a year before the date matching an additional condition, best first.
+func (q *Query) memoryPhotos(f form.Memories, date time.Time, where string, args ...interface{}) (results []PhotoResult, err error) {
+	s, err := q.photoSearch(form.PhotoSearch{Public: !f.Private, Safe: !f.Nsfw})
+
+	if err != nil {
+		return results, err
+	}
+
+	s = s.Where("DATE(photos.taken_at_local) < ?", date.AddDate(-1, 0, MemoriesWeek+1).Format("2006-01-02")).
+		Where(where, args...).
+		Order("YEAR(photos.taken_at_local) DESC, photos.photo_favorite DESC, photos.photo_story DESC, "+
+			"files.file_width * files.file_height DESC, photos.taken_at_local", true).
+		Limit(1000)
+
+	if err := s.Scan(&results); err.Error != nil {
+		return results, err.Error
+	}
+
+	return results, nil
+}
+
+// groupMemories groups photos by the number of years since they were taken, keeping the order within each group.
+func groupMemories(photos []PhotoResult, date time.Time, count int, include func(p PhotoResult) bool) (result []Memory) {
+	groups := make(map[int]int)
+
+	for _, p := range photos {
+		yearsAgo := memoryYearsAgo(p.TakenAtLocal, date)
+
+		if yearsAgo < 1 || !include(p) {
+			continue
+		}
+
+		i, ok := groups[yearsAgo]
+
+		if !ok {
+			i = len(result)
+			groups[yearsAgo] = i
+			result = append(result, Memory{Year: p.TakenAtLocal.Year(), YearsAgo: yearsAgo})
+		}
+
+		if m := &result[i]; len(m.Photos) < count {
+			m.Photos = append(m.Photos, p)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].YearsAgo < result[j].YearsAgo })
+
+	return result
+}
+
+// memoryYearsAgo returns the number of years between the date and the anniversary of the time taken
+// in the same week, which may be in the previous or next year around New Year.
+func memoryYearsAgo(taken, date time.Time) int {
+	for _, year := range []int{date.Year(), date.Year() - 1, date.Year() + 1} {
+		anniversary := time.Date(year, taken.Month(), taken.Day(), 0, 0, 0, 0, time.UTC)
+
+		if days := anniversary.Sub(date).Hours() / 24; days >= -MemoriesWeek && days <= MemoriesWeek {
+			return year - taken.Year()
+		}
+	}
+
+	return date.Year() - taken.Year()
+}
@@ -0,0 +1,68 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupMemories(t *testing.T) {
+	photos := []PhotoResult{
+		{ID: 1, TakenAtLocal: time.Date(2019, 7, 14, 10, 0, 0, 0, time.UTC)},
+		{ID: 2, TakenAtLocal: time.Date(2019, 7, 12, 10, 0, 0, 0, time.UTC)},
+		{ID: 3, TakenAtLocal: time.Date(2019, 7, 14, 12, 0, 0, 0, time.UTC)},
+		{ID: 4, TakenAtLocal: time.Date(2015, 7, 14, 8, 0, 0, 0, time.UTC)},
+	}
+
+	t.Run("week", func(t *testing.T) {
+		result := groupMemories(photos, time.Date(2020, 7, 14, 0, 0, 0, 0, time.UTC), 2, func(p PhotoResult) bool { return true })
+
+		assert.Len(t, result, 2)
+		assert.Equal(t, 2019, result[0].Year)
+		assert.Equal(t, 1, result[0].YearsAgo)
+		assert.Len(t, result[0].Photos, 2)
+		assert.Equal(t, uint(1), result[0].Photos[0].ID)
+		assert.Equal(t, 5, result[1].YearsAgo)
+	})
+	t.Run("day", func(t *testing.T) {
+		result := groupMemories(photos, time.Date(2020, 7, 14, 0, 0, 0, 0, time.UTC), 10, func(p PhotoResult) bool { return p.TakenAtLocal.Day() == 14 })
+
+		assert.Len(t, result, 2)
+		assert.Len(t, result[0].Photos, 2)
+		assert.Equal(t, uint(3), result[0].Photos[1].ID)
+
+		memories := MemoriesResult{Day: result}
+
+		assert.Equal(t, 3, memories.Count())
+	})
+}
+
+func TestGroupMemories_NewYear(t *testing.T) {
+	date := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+	photos := []PhotoResult{
+		{ID: 1, TakenAtLocal: time.Date(2020, 12, 30, 10, 0, 0, 0, time.UTC)},
+		{ID: 2, TakenAtLocal: time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{ID: 3, TakenAtLocal: time.Date(2019, 12, 30, 10, 0, 0, 0, time.UTC)},
+		{ID: 4, TakenAtLocal: time.Date(2019, 1, 2, 10, 0, 0, 0, time.UTC)},
+	}
+
+	result := groupMemories(photos, date, 10, func(p PhotoResult) bool { return true })
+
+	assert.Len(t, result, 2)
+	assert.Equal(t, 1, result[0].YearsAgo)
+	assert.Len(t, result[0].Photos, 2)
+	assert.Equal(t, uint(2), result[0].Photos[0].ID)
+	assert.Equal(t, uint(3), result[0].Photos[1].ID)
+	assert.Equal(t, 2, result[1].YearsAgo)
+	assert.Equal(t, uint(4), result[1].Photos[0].ID)
+}
+
+func TestMemoryYearsAgo(t *testing.T) {
+	date := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 0, memoryYearsAgo(time.Date(2020, 12, 30, 10, 0, 0, 0, time.UTC), date))
+	assert.Equal(t, 1, memoryYearsAgo(time.Date(2019, 12, 30, 10, 0, 0, 0, time.UTC), date))
+	assert.Equal(t, 1, memoryYearsAgo(time.Date(2020, 1, 2, 10, 0, 0, 0, time.UTC), date))
+	assert.Equal(t, 1, memoryYearsAgo(time.Date(2020, 1, 5, 10, 0, 0, 0, time.UTC), date))
+}
@@ -40,6 +40,7 @@ type PhotoResult struct {
 	PhotoIso         int
 	PhotoFNumber     float64
 	PhotoExposure    string
+	EventUUID        string
 
 	// Camera
 	CameraID    uint
@@ -76,8 +77,14 @@ type PhotoResult struct {
 	FileAspectRatio float64
 	FileColors      string // todo: remove from result?
 	FileChroma      uint8  // todo: remove from result?
-	FileLuminance   string  // todo: remove from result?
-	FileDiff        uint32  // todo: remove from result?
+	FileLuminance   string // todo: remove from result?
+	FileDiff        uint32 // todo: remove from result?
+	FileBlurHash    string
+	FileMainColor   string
+
+	// Search
+	SearchScore     int
+	PaletteDistance int
 }
 
 func (m *PhotoResult) DownloadFileName() string {
@@ -98,26 +105,115 @@ func (m *PhotoResult) DownloadFileName() string {
 
 // Photos searches for photos based on a Form and returns a PhotoResult slice.
 func (q *Query) Photos(f form.PhotoSearch) (results []PhotoResult, err error) {
-	if err := f.ParseQueryString(); err != nil {
+	s, err := q.photoSearch(f)
+
+	if err != nil {
 		return results, err
 	}
 
+	if result := s.Scan(&results); result.Error != nil {
+		return results, result.Error
+	}
+
+	return results, nil
+}
+
+// PhotoPage searches for photos like Photos and returns an opaque cursor pointing to the next page,
+// the cursor is empty if there are no more results.
+func (q *Query) PhotoPage(f form.PhotoSearch) (results []PhotoResult, next string, err error) {
+	s, err := q.photoSearch(f)
+
+	if err != nil {
+		return results, "", err
+	}
+
+	// One more row than requested is fetched to find out if there is a next page.
+	count := pageSize(f.Count)
+
+	if result := s.Limit(count + 1).Scan(&results); result.Error != nil {
+		return results, "", result.Error
+	}
+
+	if len(results) > count {
+		results = results[:count]
+		next = NewPhotoCursor(f.Order, results[count-1])
+	}
+
+	return results, next, nil
+}
+
+// pageSize returns the number of results per page, the default is 100.
+func pageSize(count int) int {
+	if count > 0 && count <= 1000 {
+		return count
+	}
+
+	return 100
+}
+
+// photoSearch returns the database scope of a photo search including sort order and limit.
+func (q *Query) photoSearch(f form.PhotoSearch) (s *gorm.DB, err error) {
+	if err := f.ParseQueryString(); err != nil {
+		return nil, err
+	}
+
 	defer log.Debug(capture.Time(time.Now(), fmt.Sprintf("photos: %+v", f)))
 
-	s := q.db.NewScope(nil).DB()
+	var cursor PhotoCursor
+
+	if f.Cursor != "" {
+		if cursor, err = ParsePhotoCursor(f.Cursor, f.Order); err != nil {
+			return nil, err
+		}
+	}
+
+	s = q.db.NewScope(nil).DB()
 
 	// s.LogMode(true)
 
+	// Text queries are ranked by relevance unless photos are sorted by date or another order,
+	// the free text terms of parsed queries are ranked as well.
+	rankQuery := f.Query
+
+	if rankQuery == "" && f.Expr != nil {
+		rankQuery = exprText(f.Expr)
+	}
+
+	ranked := rankQuery != "" && !f.Location && (f.Order == "" || f.Order == "relevance")
+
+	var rankScore, rankWhere string
+	var rankScoreArgs, rankWhereArgs []interface{}
+
+	if ranked {
+		rankScore, rankScoreArgs, rankWhere, rankWhereArgs = q.photoRank(rankQuery)
+	} else {
+		rankScore = "0"
+	}
+
+	// Photos with a similar color palette are sorted by distance unless another order is given.
+	palette := f.Similar != "" || f.Palette != ""
+	paletteExpr := "0"
+
+	if palette {
+		p, err := q.searchPalette(f.Similar, f.Palette)
+
+		if err != nil {
+			return nil, err
+		}
+
+		paletteExpr = paletteDistance(p)
+	}
+
 	s = s.Table("photos").
 		Select(`photos.*,
 		files.id AS file_id, files.file_uuid, files.file_primary, files.file_missing, files.file_name, files.file_hash, 
 		files.file_type, files.file_mime, files.file_width, files.file_height, files.file_aspect_ratio, 
 		files.file_orientation, files.file_main_color, files.file_colors, files.file_luminance, files.file_chroma,
-		files.file_diff,
+		files.file_diff, files.file_blur_hash,
 		cameras.camera_make, cameras.camera_model,
 		lenses.lens_make, lenses.lens_model,
-		places.loc_label, places.loc_city, places.loc_state, places.loc_country
-		`).
+		places.loc_label, places.loc_city, places.loc_state, places.loc_country,
+		`+rankScore+` AS search_score, `+paletteExpr+` AS palette_distance`, rankScoreArgs...).
 		Joins("JOIN files ON files.photo_id = photos.id AND files.file_primary AND files.deleted_at IS NULL").
 		Joins("JOIN cameras ON cameras.id = photos.camera_id").
 		Joins("JOIN lenses ON lenses.id = photos.lens_id").
@@ -126,14 +222,12 @@ func (q *Query) Photos(f form.PhotoSearch) (results []PhotoResult, err error) {
 		Where("files.file_missing = 0").
 		Group("photos.id, files.id")
 
-	if f.ID != "" {
-		s = s.Where("photos.photo_uuid = ?", f.ID)
-
-		if result := s.Scan(&results); result.Error != nil {
-			return results, result.Error
-		}
+	if where, args := q.photoVisibility(); where != "" {
+		s = s.Where(where, args...)
+	}
 
-		return results, nil
+	if f.ID != "" {
+		return s.Where("photos.photo_uuid = ?", f.ID), nil
 	}
 
 	var categories []entity.Category
@@ -143,7 +237,7 @@ func (q *Query) Photos(f form.PhotoSearch) (results []PhotoResult, err error) {
 	if f.Label != "" {
 		if result := q.db.First(&label, "label_slug = ?", strings.ToLower(f.Label)); result.Error != nil {
 			log.Errorf("search: label \"%s\" not found", f.Label)
-			return results, fmt.Errorf("label \"%s\" not found", f.Label)
+			return nil, fmt.Errorf("label \"%s\" not found", f.Label)
 		} else {
 			labelIds = append(labelIds, label.ID)
 
@@ -167,33 +261,47 @@ func (q *Query) Photos(f form.PhotoSearch) (results []PhotoResult, err error) {
 		}
 	} else if f.Query != "" {
 		if len(f.Query) < 2 {
-			return results, fmt.Errorf("query too short")
+			return nil, fmt.Errorf("query too short")
 		}
 
-		slugString := slug.Make(f.Query)
-		lowerString := strings.ToLower(f.Query)
-		likeString := lowerString + "%"
+		if ranked {
+			s = s.Where(rankWhere, rankWhereArgs...)
+		} else {
+			slugString := slug.Make(f.Query)
+			lowerString := strings.ToLower(f.Query)
+			likeString := lowerString + "%"
 
-		s = s.Joins("LEFT JOIN photos_keywords ON photos_keywords.photo_id = photos.id").
-			Joins("LEFT JOIN keywords ON photos_keywords.keyword_id = keywords.id")
+			s = s.Joins("LEFT JOIN photos_keywords ON photos_keywords.photo_id = photos.id").
+				Joins("LEFT JOIN keywords ON photos_keywords.keyword_id = keywords.id")
 
-		if result := q.db.First(&label, "label_slug = ?", slugString); result.Error != nil {
-			log.Infof("search: label \"%s\" not found, using fuzzy search", f.Query)
+			if result := q.db.First(&label, "label_slug = ?", slugString); result.Error != nil {
+				log.Infof("search: label \"%s\" not found, using fuzzy search", f.Query)
 
-			s = s.Where("keywords.keyword LIKE ?", likeString)
-		} else {
-			labelIds = append(labelIds, label.ID)
+				s = s.Where("keywords.keyword LIKE ?", likeString)
+			} else {
+				labelIds = append(labelIds, label.ID)
 
-			q.db.Where("category_id = ?", label.ID).Find(&categories)
+				q.db.Where("category_id = ?", label.ID).Find(&categories)
 
-			for _, category := range categories {
-				labelIds = append(labelIds, category.LabelID)
+				for _, category := range categories {
+					labelIds = append(labelIds, category.LabelID)
+				}
+
+				log.Infof("search: label \"%s\" includes %d categories", label.LabelName, len(labelIds))
+
+				s = s.Where("photos_labels.label_id IN (?) OR keywords.keyword LIKE ?", labelIds, likeString)
 			}
+		}
+	}
 
-			log.Infof("search: label \"%s\" includes %d categories", label.LabelName, len(labelIds))
+	if f.Expr != nil {
+		where, args, err := q.photoExpr(f.Expr)
 
-			s = s.Where("photos_labels.label_id IN (?) OR keywords.keyword LIKE ?", labelIds, likeString)
+		if err != nil {
+			return nil, err
 		}
+
+		s = s.Where(where, args...)
 	}
 
 	if f.Archived {
@@ -207,7 +315,31 @@ func (q *Query) Photos(f form.PhotoSearch) (results []PhotoResult, err error) {
 	}
 
 	if f.Album != "" {
-		s = s.Joins("JOIN photos_albums ON photos_albums.photo_uuid = photos.photo_uuid").Where("photos_albums.album_uuid = ?", f.Album)
+		if album, err := q.AlbumByUUID(f.Album); err == nil && album.IsSmart() {
+			// Smart albums are resolved live using their saved search filter.
+			where, args, order, err := q.smartAlbumCondition(album.AlbumFilter)
+
+			if err != nil {
+				return nil, err
+			}
+
+			s = s.Where(where, args...)
+
+			if f.Order == "" {
+				f.Order = order
+			}
+
+			if f.Order == "" {
+				f.Order = album.AlbumOrder
+			}
+		} else {
+			s = s.Joins("JOIN photos_albums ON photos_albums.photo_uuid = photos.photo_uuid").Where("photos_albums.album_uuid = ?", f.Album)
+		}
+	}
+
+	if f.Event != "" {
+		where, args := eventCondition(f.Event)
+		s = s.Where(where, args...)
 	}
 
 	if f.Camera > 0 {
@@ -262,6 +394,11 @@ func (q *Query) Photos(f form.PhotoSearch) (results []PhotoResult, err error) {
 		s = s.Where("files.file_hash = ?", f.Hash)
 	}
 
+	if f.Path != "" {
+		where, args := folderCondition(f.Path, f.Recursive)
+		s = s.Where(where, args...)
+	}
+
 	if f.Duplicate {
 		s = s.Where("files.file_duplicate = 1")
 	}
@@ -290,23 +427,10 @@ func (q *Query) Photos(f form.PhotoSearch) (results []PhotoResult, err error) {
 		s = s.Where("photos.photo_f_number <= ?", f.Fmax)
 	}
 
-	if f.Dist == 0 {
-		f.Dist = 20
-	} else if f.Dist > 5000 {
-		f.Dist = 5000
-	}
+	geo := geoFilter{Lat: f.Lat, Lng: f.Lng, Dist: f.Dist, BBox: f.BBox, Polygon: f.Polygon}
 
-	// Inaccurate distance search, but probably 'good enough' for now
-	if f.Lat > 0 {
-		latMin := f.Lat - SearchRadius*float64(f.Dist)
-		latMax := f.Lat + SearchRadius*float64(f.Dist)
-		s = s.Where("photos.photo_lat BETWEEN ? AND ?", latMin, latMax)
-	}
-
-	if f.Lng > 0 {
-		lngMin := f.Lng - SearchRadius*float64(f.Dist)
-		lngMax := f.Lng + SearchRadius*float64(f.Dist)
-		s = s.Where("photos.photo_lng BETWEEN ? AND ?", lngMin, lngMax)
+	if s, _, _, err = geo.apply(s); err != nil {
+		return nil, err
 	}
 
 	if !f.Before.IsZero() {
@@ -317,32 +441,37 @@ func (q *Query) Photos(f form.PhotoSearch) (results []PhotoResult, err error) {
 		s = s.Where("photos.taken_at >= ?", f.After.Format("2006-01-02"))
 	}
 
-	switch f.Order {
-	case "relevance":
-		s = s.Order("photo_story DESC, photo_favorite DESC, taken_at DESC")
-	case "newest":
-		s = s.Order("taken_at DESC, photos.photo_uuid")
-	case "oldest":
-		s = s.Order("taken_at, photos.photo_uuid")
-	case "imported":
-		s = s.Order("photos.id DESC")
-	case "similar":
-		s = s.Order("files.file_main_color, photos.location_id, files.file_diff, taken_at DESC")
-	default:
-		s = s.Order("taken_at DESC, photos.photo_uuid")
+	if f.Similar != "" {
+		s = s.Where("photos.photo_uuid <> ?", f.Similar)
+	}
+
+	var first []sortKey
+
+	if palette && f.Order == "" {
+		first = append(first, sortPalette(paletteExpr))
+	}
+
+	if ranked {
+		first = append(first, sortScore(rankScore, rankScoreArgs))
 	}
 
+	keys := photoOrder(f.Order, first...)
+
+	if f.Cursor != "" {
+		where, args := keysetCondition(keys, cursor)
+		s = s.Where(where, args...)
+		f.Offset = 0
+	}
+
+	s = s.Order(orderBy(keys))
+
 	if f.Count > 0 && f.Count <= 1000 {
 		s = s.Limit(f.Count).Offset(f.Offset)
 	} else {
 		s = s.Limit(100).Offset(0)
 	}
 
-	if result := s.Scan(&results); result.Error != nil {
-		return results, result.Error
-	}
-
-	return results, nil
+	return s, nil
 }
 
 // PhotoByID returns a Photo based on the ID.
@@ -382,3 +511,41 @@ func (q *Query) PreloadPhotoByUUID(photoUUID string) (photo entity.Photo, err er
 
 	return photo, nil
 }
+
+// smartAlbumCondition returns the condition for photos matching the saved search filter of a smart album
+// and the order it contains. Filters may refer to the primary file, so that the condition can also be
+// used in queries without files.
+func (q *Query) smartAlbumCondition(filter string) (where string, args []interface{}, order string, err error) {
+	f := form.PhotoSearch{Query: filter}
+
+	if err := f.ParseFilter(); err != nil {
+		return "", nil, "", err
+	}
+
+	conditions := []string{"files.photo_id = photos.id AND files.file_primary AND files.deleted_at IS NULL"}
+
+	if f.Expr != nil {
+		exprWhere, exprArgs, err := q.photoExpr(f.Expr)
+
+		if err != nil {
+			return "", nil, "", err
+		}
+
+		conditions = append(conditions, "("+exprWhere+")")
+		args = append(args, exprArgs...)
+	}
+
+	if !f.Before.IsZero() {
+		conditions = append(conditions, "photos.taken_at <= ?")
+		args = append(args, f.Before.Format("2006-01-02"))
+	}
+
+	if !f.After.IsZero() {
+		conditions = append(conditions, "photos.taken_at >= ?")
+		args = append(args, f.After.Format("2006-01-02"))
+	}
+
+	where = fmt.Sprintf("EXISTS (SELECT 1 FROM files WHERE %s)", strings.Join(conditions, " AND "))
+
+	return where, args, f.Order, nil
+}
@@ -0,0 +1,168 @@
+package query
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PhotoCursor contains the sort key values of the last photo on a result page,
+// so that the next page can be found without an offset.
+type PhotoCursor struct {
+	Order    string    `json:"o,omitempty"`
+	Score    int       `json:"s,omitempty"`
+	Distance int       `json:"p,omitempty"`
+	Story    bool      `json:"st,omitempty"`
+	Favorite bool      `json:"fa,omitempty"`
+	TakenAt  time.Time `json:"t"`
+	Color    string    `json:"c,omitempty"`
+	Location string    `json:"l,omitempty"`
+	Diff     uint32    `json:"d,omitempty"`
+	ID       uint      `json:"i"`
+}
+
+// NewPhotoCursor returns an opaque cursor pointing behind the given photo.
+func NewPhotoCursor(order string, last PhotoResult) string {
+	c := PhotoCursor{
+		Order:    order,
+		Score:    last.SearchScore,
+		Distance: last.PaletteDistance,
+		Story:    last.PhotoStory,
+		Favorite: last.PhotoFavorite,
+		TakenAt:  last.TakenAt,
+		Color:    last.FileMainColor,
+		Location: last.LocationID,
+		Diff:     last.FileDiff,
+		ID:       last.ID,
+	}
+
+	data, err := json.Marshal(c)
+
+	if err != nil {
+		log.Errorf("photos: %s", err)
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// ParsePhotoCursor decodes a cursor and checks that it was created for the same sort order.
+func ParsePhotoCursor(cursor, order string) (c PhotoCursor, err error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+
+	if err != nil {
+		return c, fmt.Errorf("invalid cursor")
+	}
+
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("invalid cursor")
+	}
+
+	if c.Order != order {
+		return c, fmt.Errorf("cursor doesn't match sort order")
+	}
+
+	return c, nil
+}
+
+// sortKey represents a column of a sort order.
+type sortKey struct {
+	column string        // Column or alias used in ORDER BY
+	expr   string        // Expression used in WHERE, defaults to column
+	args   []interface{} // Arguments of the expression
+	desc   bool
+	value  func(c PhotoCursor) interface{}
+}
+
+var (
+	sortScore = func(score string, args []interface{}) sortKey {
+		return sortKey{column: "search_score", expr: score, args: args, desc: true, value: func(c PhotoCursor) interface{} { return c.Score }}
+	}
+	sortPalette = func(distance string) sortKey {
+		return sortKey{column: "palette_distance", expr: distance, value: func(c PhotoCursor) interface{} { return c.Distance }}
+	}
+	sortStory    = sortKey{column: "photos.photo_story", desc: true, value: func(c PhotoCursor) interface{} { return c.Story }}
+	sortFavorite = sortKey{column: "photos.photo_favorite", desc: true, value: func(c PhotoCursor) interface{} { return c.Favorite }}
+	sortNewest   = sortKey{column: "photos.taken_at", desc: true, value: func(c PhotoCursor) interface{} { return c.TakenAt }}
+	sortOldest   = sortKey{column: "photos.taken_at", value: func(c PhotoCursor) interface{} { return c.TakenAt }}
+	sortColor    = sortKey{column: "files.file_main_color", value: func(c PhotoCursor) interface{} { return c.Color }}
+	sortLocation = sortKey{column: "photos.location_id", value: func(c PhotoCursor) interface{} { return c.Location }}
+	sortDiff     = sortKey{column: "files.file_diff", value: func(c PhotoCursor) interface{} { return c.Diff }}
+	sortIdDesc   = sortKey{column: "photos.id", desc: true, value: func(c PhotoCursor) interface{} { return c.ID }}
+	sortIdAsc    = sortKey{column: "photos.id", value: func(c PhotoCursor) interface{} { return c.ID }}
+)
+
+// photoOrder returns the sort keys of an order, the photo id is always the last key so that the order is unique.
+// The default and relevance orders start with the given keys, like the score of ranked full-text searches.
+func photoOrder(order string, first ...sortKey) (keys []sortKey) {
+	keys = append(keys, first...)
+
+	switch order {
+	case "relevance":
+		return append(keys, sortStory, sortFavorite, sortNewest, sortIdDesc)
+	case "newest":
+		return []sortKey{sortNewest, sortIdDesc}
+	case "oldest":
+		return []sortKey{sortOldest, sortIdAsc}
+	case "imported":
+		return []sortKey{sortIdDesc}
+	case "similar":
+		return []sortKey{sortColor, sortLocation, sortDiff, sortNewest, sortIdDesc}
+	default:
+		return append(keys, sortNewest, sortIdDesc)
+	}
+}
+
+// orderBy returns the ORDER BY clause for a list of sort keys.
+func orderBy(keys []sortKey) string {
+	var result []string
+
+	for _, k := range keys {
+		if k.desc {
+			result = append(result, k.column+" DESC")
+		} else {
+			result = append(result, k.column)
+		}
+	}
+
+	return strings.Join(result, ", ")
+}
+
+// keysetCondition returns the condition for photos sorted behind the cursor.
+func keysetCondition(keys []sortKey, c PhotoCursor) (where string, args []interface{}) {
+	var alternatives []string
+
+	for i, k := range keys {
+		var conditions []string
+
+		for _, prev := range keys[:i] {
+			conditions = append(conditions, prev.where()+" = ?")
+			args = append(args, prev.args...)
+			args = append(args, prev.value(c))
+		}
+
+		if k.desc {
+			conditions = append(conditions, k.where()+" < ?")
+		} else {
+			conditions = append(conditions, k.where()+" > ?")
+		}
+
+		args = append(args, k.args...)
+		args = append(args, k.value(c))
+
+		alternatives = append(alternatives, "("+strings.Join(conditions, " AND ")+")")
+	}
+
+	return "(" + strings.Join(alternatives, " OR ") + ")", args
+}
+
+// where returns the expression of a sort key that can be used in a WHERE clause.
+func (k sortKey) where() string {
+	if k.expr != "" {
+		return k.expr
+	}
+
+	return k.column
+}
@@ -0,0 +1,79 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhotoCursor(t *testing.T) {
+	takenAt := time.Date(2019, 7, 1, 12, 30, 0, 0, time.UTC)
+	last := PhotoResult{ID: 42, TakenAt: takenAt, PhotoFavorite: true, FileMainColor: "blue"}
+
+	t.Run("round trip", func(t *testing.T) {
+		cursor := NewPhotoCursor("oldest", last)
+
+		result, err := ParsePhotoCursor(cursor, "oldest")
+
+		assert.Nil(t, err)
+		assert.Equal(t, uint(42), result.ID)
+		assert.True(t, takenAt.Equal(result.TakenAt))
+		assert.True(t, result.Favorite)
+		assert.Equal(t, "blue", result.Color)
+	})
+	t.Run("order changed", func(t *testing.T) {
+		cursor := NewPhotoCursor("oldest", last)
+
+		_, err := ParsePhotoCursor(cursor, "newest")
+
+		assert.EqualError(t, err, "cursor doesn't match sort order")
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ParsePhotoCursor("foo!", "")
+
+		assert.EqualError(t, err, "invalid cursor")
+	})
+}
+
+func TestPhotoOrder(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		assert.Equal(t, "photos.taken_at DESC, photos.id DESC", orderBy(photoOrder("")))
+	})
+	t.Run("oldest", func(t *testing.T) {
+		assert.Equal(t, "photos.taken_at, photos.id", orderBy(photoOrder("oldest")))
+	})
+	t.Run("ranked", func(t *testing.T) {
+		assert.Equal(t, "search_score DESC, photos.taken_at DESC, photos.id DESC", orderBy(photoOrder("", sortScore("(1)", nil))))
+	})
+	t.Run("similar", func(t *testing.T) {
+		assert.Equal(t, "files.file_main_color, photos.location_id, files.file_diff, photos.taken_at DESC, photos.id DESC",
+			orderBy(photoOrder("similar", sortScore("(1)", nil))))
+	})
+}
+
+func TestKeysetCondition(t *testing.T) {
+	takenAt := time.Date(2019, 7, 1, 12, 30, 0, 0, time.UTC)
+	cursor := PhotoCursor{ID: 42, TakenAt: takenAt, Score: 12}
+
+	t.Run("newest", func(t *testing.T) {
+		where, args := keysetCondition(photoOrder("newest"), cursor)
+
+		assert.Equal(t, "((photos.taken_at < ?) OR (photos.taken_at = ? AND photos.id < ?))", where)
+		assert.Equal(t, []interface{}{takenAt, takenAt, uint(42)}, args)
+	})
+	t.Run("ranked", func(t *testing.T) {
+		where, args := keysetCondition(photoOrder("", sortScore("(CASE WHEN x LIKE ? THEN 8 ELSE 0 END)", []interface{}{"%cat%"})), cursor)
+
+		assert.Equal(t, "(((CASE WHEN x LIKE ? THEN 8 ELSE 0 END) < ?) OR "+
+			"((CASE WHEN x LIKE ? THEN 8 ELSE 0 END) = ? AND photos.taken_at < ?) OR "+
+			"((CASE WHEN x LIKE ? THEN 8 ELSE 0 END) = ? AND photos.taken_at = ? AND photos.id < ?))", where)
+		assert.Equal(t, []interface{}{"%cat%", 12, "%cat%", 12, takenAt, "%cat%", 12, takenAt, uint(42)}, args)
+	})
+}
+
+func TestPageSize(t *testing.T) {
+	assert.Equal(t, 50, pageSize(50))
+	assert.Equal(t, 100, pageSize(0))
+	assert.Equal(t, 100, pageSize(5000))
+}
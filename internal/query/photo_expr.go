@@ -0,0 +1,268 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gosimple/slug"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// numberColumns maps numeric search filters to columns.
+var numberColumns = map[string]string{
+	"camera": "photos.camera_id",
+	"lens":   "photos.lens_id",
+	"year":   "photos.photo_year",
+	"month":  "photos.photo_month",
+	"iso":    "photos.photo_iso",
+	"f":      "photos.photo_f_number",
+	"mm":     "photos.photo_focal_length",
+	"chroma": "files.file_chroma",
+}
+
+// boolColumns maps boolean search filters to columns.
+var boolColumns = map[string]string{
+	"favorites": "photos.photo_favorite",
+	"story":     "photos.photo_story",
+	"private":   "photos.photo_private",
+	"nsfw":      "photos.photo_nsfw",
+	"portrait":  "files.file_portrait",
+	"duplicate": "files.file_duplicate",
+}
+
+// textColumns maps search filters to columns that must match exactly.
+var textColumns = map[string]string{
+	"country": "photos.photo_country",
+	"color":   "files.file_main_color",
+	"hash":    "files.file_hash",
+}
+
+// photoExpr compiles a search query syntax tree to an SQL condition with arguments.
+func (q *Query) photoExpr(expr form.Expr) (where string, args []interface{}, err error) {
+	switch e := expr.(type) {
+	case form.And:
+		return q.photoExprList(e, " AND ")
+	case form.Or:
+		return q.photoExprList(e, " OR ")
+	case form.Not:
+		where, args, err = q.photoExpr(e.Expr)
+
+		return "NOT " + where, args, err
+	case *form.Term:
+		return q.photoTerm(e)
+	default:
+		return "", args, fmt.Errorf("unsupported expression %T", expr)
+	}
+}
+
+// photoExprList compiles a list of expressions joined by an operator.
+func (q *Query) photoExprList(list []form.Expr, op string) (string, []interface{}, error) {
+	var conditions []string
+	var args []interface{}
+
+	for _, e := range list {
+		where, a, err := q.photoExpr(e)
+
+		if err != nil {
+			return "", args, err
+		}
+
+		conditions = append(conditions, where)
+		args = append(args, a...)
+	}
+
+	return "(" + strings.Join(conditions, op) + ")", args, nil
+}
+
+// photoTerm compiles a single term, alternatives are joined with OR.
+func (q *Query) photoTerm(t *form.Term) (string, []interface{}, error) {
+	var conditions []string
+	var args []interface{}
+
+	for _, v := range t.Values {
+		where, a, err := q.photoValue(t, v)
+
+		if err != nil {
+			return "", args, err
+		}
+
+		conditions = append(conditions, where)
+		args = append(args, a...)
+	}
+
+	return "(" + strings.Join(conditions, " OR ") + ")", args, nil
+}
+
+// photoValue returns the SQL condition for a single term value.
+func (q *Query) photoValue(t *form.Term, v form.Value) (string, []interface{}, error) {
+	if col, ok := numberColumns[t.Key]; ok {
+		return numberCondition(col, v)
+	}
+
+	if col, ok := boolColumns[t.Key]; ok {
+		if txt.Bool(v.Text) {
+			return col + " = 1", nil, nil
+		}
+
+		return col + " = 0", nil, nil
+	}
+
+	if col, ok := textColumns[t.Key]; ok {
+		return "LOWER(" + col + ") = ?", []interface{}{strings.ToLower(v.Text)}, nil
+	}
+
+	switch t.Key {
+	case "":
+		return q.textCondition(v.Text, t.Phrase)
+	case "label":
+		return q.labelCondition(v.Text)
+	case "title":
+		return "LOWER(photos.photo_title) LIKE ?", []interface{}{"%" + strings.ToLower(v.Text) + "%"}, nil
+	case "album":
+		return "EXISTS (SELECT 1 FROM photos_albums pa WHERE pa.photo_uuid = photos.photo_uuid AND pa.album_uuid = ?)",
+			[]interface{}{v.Text}, nil
+	case "event":
+		where, args := eventCondition(v.Text)
+		return where, args, nil
+	case "mono":
+		if txt.Bool(v.Text) {
+			return "files.file_chroma = 0", nil, nil
+		}
+
+		return "files.file_chroma > 0", nil, nil
+	case "taken":
+		return takenCondition(v)
+	}
+
+	return "", nil, fmt.Errorf("unknown filter %s", t.Key)
+}
+
+// numberCondition returns the SQL condition for a numeric value or range.
+func numberCondition(col string, v form.Value) (string, []interface{}, error) {
+	number := func(s string) (float64, error) {
+		return strconv.ParseFloat(s, 64)
+	}
+
+	if !v.Range {
+		n, err := number(v.Text)
+		return col + " = ?", []interface{}{n}, err
+	}
+
+	var conditions []string
+	var args []interface{}
+
+	if v.Min != "" {
+		n, err := number(v.Min)
+
+		if err != nil {
+			return "", args, err
+		}
+
+		conditions = append(conditions, col+" >= ?")
+		args = append(args, n)
+	}
+
+	if v.Max != "" {
+		n, err := number(v.Max)
+
+		if err != nil {
+			return "", args, err
+		}
+
+		conditions = append(conditions, col+" <= ?")
+		args = append(args, n)
+	}
+
+	return "(" + strings.Join(conditions, " AND ") + ")", args, nil
+}
+
+// takenCondition returns the SQL condition for a date or date range, the end date is inclusive.
+func takenCondition(v form.Value) (string, []interface{}, error) {
+	min, max := v.Min, v.Max
+
+	if !v.Range {
+		min, max = v.Text, v.Text
+	}
+
+	var conditions []string
+	var args []interface{}
+
+	if min != "" {
+		start, _, err := form.ParseDate(min)
+
+		if err != nil {
+			return "", args, err
+		}
+
+		conditions = append(conditions, "photos.taken_at >= ?")
+		args = append(args, start)
+	}
+
+	if max != "" {
+		_, end, err := form.ParseDate(max)
+
+		if err != nil {
+			return "", args, err
+		}
+
+		conditions = append(conditions, "photos.taken_at < ?")
+		args = append(args, end)
+	}
+
+	return "(" + strings.Join(conditions, " AND ") + ")", args, nil
+}
+
+// textCondition returns the SQL condition for a free text word or phrase, matching keywords, labels and titles.
+func (q *Query) textCondition(text string, phrase bool) (string, []interface{}, error) {
+	lowerString := strings.ToLower(text)
+
+	where := "EXISTS (SELECT 1 FROM photos_keywords pk JOIN keywords k ON k.id = pk.keyword_id WHERE pk.photo_id = photos.id AND k.keyword LIKE ?)"
+	args := []interface{}{lowerString + "%"}
+
+	if phrase {
+		where += " OR LOWER(photos.photo_title) LIKE ?"
+		args = append(args, "%"+lowerString+"%")
+	}
+
+	if labelIds := q.labelIds(slug.Make(text)); len(labelIds) > 0 {
+		where += " OR EXISTS (SELECT 1 FROM photos_labels pl WHERE pl.photo_id = photos.id AND pl.label_id IN (?))"
+		args = append(args, labelIds)
+	}
+
+	return "(" + where + ")", args, nil
+}
+
+// labelCondition returns the SQL condition for a label including its categories, unknown labels match nothing.
+func (q *Query) labelCondition(labelSlug string) (string, []interface{}, error) {
+	labelIds := q.labelIds(strings.ToLower(labelSlug))
+
+	if len(labelIds) == 0 {
+		log.Infof("search: label \"%s\" not found", labelSlug)
+		return "1 = 0", nil, nil
+	}
+
+	return "EXISTS (SELECT 1 FROM photos_labels pl WHERE pl.photo_id = photos.id AND pl.label_id IN (?))", []interface{}{labelIds}, nil
+}
+
+// labelIds returns the ID of the label with the given slug and the IDs of labels in this category.
+func (q *Query) labelIds(labelSlug string) (result []uint) {
+	var label entity.Label
+	var categories []entity.Category
+
+	if err := q.db.First(&label, "label_slug = ?", labelSlug).Error; err != nil {
+		return result
+	}
+
+	result = append(result, label.ID)
+
+	q.db.Where("category_id = ?", label.ID).Find(&categories)
+
+	for _, category := range categories {
+		result = append(result, category.LabelID)
+	}
+
+	return result
+}
@@ -0,0 +1,57 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery_PhotoExpr(t *testing.T) {
+	search := New(nil)
+
+	t.Run("ranges and negation", func(t *testing.T) {
+		expr, err := form.ParseExpr("iso:100-400|800 -favorites:true (f:..2.8 OR mono:true)")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		where, args, err := search.photoExpr(expr)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "(((photos.photo_iso >= ? AND photos.photo_iso <= ?) OR photos.photo_iso = ?) AND "+
+			"NOT (photos.photo_favorite = 1) AND (((photos.photo_f_number <= ?)) OR (files.file_chroma = 0)))", where)
+		assert.Equal(t, []interface{}{100.0, 400.0, 800.0, 2.8}, args)
+	})
+	t.Run("taken", func(t *testing.T) {
+		expr, err := form.ParseExpr("taken:2019-06..2019-08")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		where, args, err := search.photoExpr(expr)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "((photos.taken_at >= ? AND photos.taken_at < ?))", where)
+		assert.Equal(t, []interface{}{
+			time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2019, 9, 1, 0, 0, 0, 0, time.UTC),
+		}, args)
+	})
+	t.Run("title and country", func(t *testing.T) {
+		expr, err := form.ParseExpr(`title:"New York" OR country:US`)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		where, args, err := search.photoExpr(expr)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "((LOWER(photos.photo_title) LIKE ?) OR (LOWER(photos.photo_country) = ?))", where)
+		assert.Equal(t, []interface{}{"%new york%", "us"}, args)
+	})
+}
@@ -0,0 +1,86 @@
+package query
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/pkg/capture"
+)
+
+// FacetLimit is the default number of values returned per facet.
+var FacetLimit = 10
+
+// FacetResult contains a facet value, its display title and the number of matching photos.
+type FacetResult struct {
+	Value string `json:"value"`
+	Title string `json:"title"`
+	Count int    `json:"count"`
+}
+
+// PhotoFacets maps facet names to their most frequent values.
+type PhotoFacets map[string][]FacetResult
+
+// photoFacet describes the columns of a facet.
+type photoFacet struct {
+	value string
+	title string
+	joins string
+}
+
+// photoFacets contains the supported facets.
+var photoFacets = map[string]photoFacet{
+	"camera":  {value: "cameras.id", title: "cameras.camera_model"},
+	"lens":    {value: "lenses.id", title: "lenses.lens_model"},
+	"country": {value: "photos.photo_country", title: "photos.photo_country"},
+	"year":    {value: "photos.photo_year", title: "photos.photo_year"},
+	"month":   {value: "photos.photo_month", title: "photos.photo_month"},
+	"label":   {value: "labels.label_slug", title: "labels.label_name", joins: "JOIN labels ON labels.id = photos_labels.label_id"},
+	"color":   {value: "files.file_main_color", title: "files.file_main_color"},
+	"type":    {value: "files.file_type", title: "files.file_type"},
+}
+
+// PhotoFacets returns the number of photos per camera, lens, country, year, month, label, color and file type
+// for the same filters as Photos, limited to the most frequent values of each facet.
+func (q *Query) PhotoFacets(f form.PhotoSearch, limit int) (results PhotoFacets, err error) {
+	defer log.Debug(capture.Time(time.Now(), fmt.Sprintf("facets: %+v", f)))
+
+	if limit <= 0 {
+		limit = FacetLimit
+	}
+
+	// Count all matching photos, not just the requested page.
+	f.Cursor = ""
+	f.Offset = 0
+
+	s, err := q.photoSearch(f)
+
+	if err != nil {
+		return results, err
+	}
+
+	s = s.Limit(limit).Offset(-1)
+
+	results = make(PhotoFacets, len(photoFacets))
+
+	for name, facet := range photoFacets {
+		var values []FacetResult
+
+		scope := s
+
+		if facet.joins != "" {
+			scope = scope.Joins(facet.joins)
+		}
+
+		if err := scope.Select(fmt.Sprintf("%s AS value, %s AS title, COUNT(DISTINCT photos.id) AS count", facet.value, facet.title)).
+			Group(facet.value+", "+facet.title).
+			Order("count DESC, value", true).
+			Scan(&values).Error; err != nil {
+			return results, err
+		}
+
+		results[name] = values
+	}
+
+	return results, nil
+}
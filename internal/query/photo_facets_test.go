@@ -0,0 +1,31 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery_PhotoFacets(t *testing.T) {
+	conf := config.TestConfig()
+
+	search := New(conf.Db())
+
+	t.Run("all photos", func(t *testing.T) {
+		result, err := search.PhotoFacets(form.PhotoSearch{Count: 10}, 3)
+
+		assert.Nil(t, err)
+		assert.Len(t, result, len(photoFacets))
+
+		for name, values := range result {
+			assert.LessOrEqual(t, len(values), 3, name)
+		}
+	})
+	t.Run("invalid query", func(t *testing.T) {
+		_, err := search.PhotoFacets(form.PhotoSearch{Query: "label:cat OR", Count: 10}, 0)
+
+		assert.Error(t, err)
+	})
+}
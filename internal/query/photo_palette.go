@@ -0,0 +1,68 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/photoprism/photoprism/pkg/colors"
+)
+
+// paletteDistance returns an SQL expression for the distance between the color and luminance maps
+// of the primary file and a palette, see colors.Palette.Distance.
+func paletteDistance(p colors.Palette) string {
+	var cells []string
+
+	for i, c := range p.Colors {
+		var cases []string
+
+		for _, other := range colors.All {
+			cases = append(cases, fmt.Sprintf("WHEN '%s' THEN %d", other.Hex(), c.Distance(other)))
+		}
+
+		cells = append(cells, fmt.Sprintf("CASE SUBSTR(files.file_colors, %d, 1) %s ELSE %d END",
+			i+1, strings.Join(cases, " "), colors.MaxColorDistance))
+	}
+
+	for i, l := range p.Luminance {
+		var cases []string
+
+		for v := 0; v < 16; v++ {
+			diff := v - int(l)
+
+			if diff < 0 {
+				diff = -diff
+			}
+
+			cases = append(cases, fmt.Sprintf("WHEN '%X' THEN %d", v, colors.LuminanceWeight*diff))
+		}
+
+		cells = append(cells, fmt.Sprintf("CASE SUBSTR(files.file_luminance, %d, 1) %s ELSE %d END",
+			i+1, strings.Join(cases, " "), colors.LuminanceWeight*15))
+	}
+
+	if len(cells) == 0 {
+		return "0"
+	}
+
+	return "(" + strings.Join(cells, " + ") + ")"
+}
+
+// searchPalette returns the palette of a photo if a UUID is given, or parses a hex encoded palette.
+func (q *Query) searchPalette(photoUUID, palette string) (colors.Palette, error) {
+	if photoUUID == "" {
+		return colors.ParsePalette(palette)
+	}
+
+	// Photos the user can't see must not reveal their colors or existence.
+	if !q.PhotoVisible(photoUUID) {
+		return colors.Palette{}, fmt.Errorf("photo %s not found", photoUUID)
+	}
+
+	file, err := q.FileByPhotoUUID(photoUUID)
+
+	if err != nil {
+		return colors.Palette{}, fmt.Errorf("photo %s not found", photoUUID)
+	}
+
+	return colors.NewPalette(file.FileColors, file.FileLuminance)
+}
@@ -0,0 +1,59 @@
+package query
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/pkg/colors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaletteDistance(t *testing.T) {
+	t.Run("colors and luminance", func(t *testing.T) {
+		p, err := colors.ParsePalette("666666666:888888888")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result := paletteDistance(p)
+
+		assert.Equal(t, 18, strings.Count(result, "CASE SUBSTR"))
+		assert.True(t, strings.HasPrefix(result, "(CASE SUBSTR(files.file_colors, 1, 1) "))
+		assert.Contains(t, result, "WHEN '6' THEN 0")
+		assert.Contains(t, result, "CASE SUBSTR(files.file_luminance, 9, 1) WHEN '0' THEN 24 ")
+	})
+	t.Run("colors only", func(t *testing.T) {
+		p, err := colors.ParsePalette("#2196F3")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 9, strings.Count(paletteDistance(p), "CASE SUBSTR"))
+	})
+	t.Run("empty", func(t *testing.T) {
+		assert.Equal(t, "0", paletteDistance(colors.Palette{}))
+	})
+}
+
+func TestQuery_SearchPalette(t *testing.T) {
+	t.Run("palette", func(t *testing.T) {
+		search := New(nil)
+
+		p, err := search.searchPalette("", "333333333")
+
+		assert.Nil(t, err)
+		assert.Equal(t, colors.White, p.Colors[0])
+	})
+	t.Run("not visible", func(t *testing.T) {
+		search := New(config.TestConfig().Db()).WithUser(&entity.User{UserUUID: "u1", Role: acl.RoleViewer})
+
+		_, err := search.searchPalette("xxx", "")
+
+		assert.EqualError(t, err, "photo xxx not found")
+	})
+}
@@ -0,0 +1,164 @@
+package query
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// Ranked search weights, so that title matches score higher than matches in labels, locations and file names.
+const (
+	rankTitle    = 8
+	rankLabel    = 4
+	rankLocation = 2
+	rankFileName = 1
+)
+
+// FuzzySimilarity is the minimum trigram similarity of keywords that are included as fuzzy matches.
+var FuzzySimilarity = 0.4
+
+// rankCondition describes how a search word is matched against a column.
+type rankCondition struct {
+	where  string
+	prefix bool
+	weight int
+}
+
+var rankConditions = []rankCondition{
+	{where: "LOWER(photos.photo_title) LIKE ?", weight: rankTitle},
+	{where: "EXISTS (SELECT 1 FROM photos_labels pl JOIN labels l ON l.id = pl.label_id WHERE pl.photo_id = photos.id AND l.label_slug LIKE ?)", prefix: true, weight: rankLabel},
+	{where: "LOWER(places.loc_label) LIKE ?", weight: rankLocation},
+	{where: "LOWER(files.file_name) LIKE ?", weight: rankFileName},
+	{where: "EXISTS (SELECT 1 FROM photos_keywords pk JOIN keywords k ON k.id = pk.keyword_id WHERE pk.photo_id = photos.id AND k.keyword LIKE ?)", prefix: true},
+}
+
+// photoRank returns the score expression and the condition of a ranked full-text search.
+func (q *Query) photoRank(query string) (score string, scoreArgs []interface{}, where string, whereArgs []interface{}) {
+	var variants [][]string
+
+	for _, w := range searchWords(query) {
+		variants = append(variants, append([]string{txt.Stem(w)}, q.similarKeywords(w)...))
+	}
+
+	return rankExpr(variants)
+}
+
+// exprText returns the free text words and phrases of a parsed search query for ranking,
+// negated terms are skipped since they can't match.
+func exprText(expr form.Expr) string {
+	var words []string
+
+	switch e := expr.(type) {
+	case form.And:
+		for _, sub := range e {
+			words = append(words, exprText(sub))
+		}
+	case form.Or:
+		for _, sub := range e {
+			words = append(words, exprText(sub))
+		}
+	case *form.Term:
+		if e.Key != "" {
+			break
+		}
+
+		for _, v := range e.Values {
+			words = append(words, v.Text)
+		}
+	}
+
+	return strings.Join(strings.Fields(strings.Join(words, " ")), " ")
+}
+
+// searchWords returns the lowercase words of a search query, stopwords are only removed if other words remain.
+func searchWords(query string) (results []string) {
+	if results = txt.UniqueWords(txt.Keywords(query)); len(results) > 0 {
+		return results
+	}
+
+	if results = txt.UniqueWords(strings.Fields(strings.ToLower(query))); len(results) > 0 {
+		return results
+	}
+
+	return []string{strings.ToLower(strings.TrimSpace(query))}
+}
+
+// rankExpr returns the score expression and the condition for a list of search words, each with its variants.
+// Every word must match at least one column, its score is the sum of the weights of all matching columns.
+func rankExpr(words [][]string) (score string, scoreArgs []interface{}, where string, whereArgs []interface{}) {
+	var scores, conditions []string
+
+	for _, variants := range words {
+		var matches []string
+
+		for _, c := range rankConditions {
+			var alternatives []string
+			var args []interface{}
+
+			for _, v := range variants {
+				alternatives = append(alternatives, c.where)
+
+				if c.prefix {
+					args = append(args, v+"%")
+				} else {
+					args = append(args, "%"+v+"%")
+				}
+			}
+
+			cond := "(" + strings.Join(alternatives, " OR ") + ")"
+			matches = append(matches, cond)
+			whereArgs = append(whereArgs, args...)
+
+			if c.weight > 0 {
+				scores = append(scores, "CASE WHEN "+cond+" THEN "+strconv.Itoa(c.weight)+" ELSE 0 END")
+				scoreArgs = append(scoreArgs, args...)
+			}
+		}
+
+		conditions = append(conditions, "("+strings.Join(matches, " OR ")+")")
+	}
+
+	if len(conditions) == 0 {
+		return "0", scoreArgs, "1 = 1", whereArgs
+	}
+
+	return "(" + strings.Join(scores, " + ") + ")", scoreArgs, strings.Join(conditions, " AND "), whereArgs
+}
+
+// similarKeywords returns indexed keywords that are similar to a word, so that typos can be found.
+func (q *Query) similarKeywords(word string) (results []string) {
+	var conditions []string
+	var args []interface{}
+
+	for _, t := range txt.Trigrams(word) {
+		if strings.Contains(t, " ") {
+			continue
+		}
+
+		conditions = append(conditions, "keyword LIKE ?")
+		args = append(args, "%"+t+"%")
+	}
+
+	if len(conditions) < 2 {
+		return results
+	}
+
+	var keywords []string
+
+	if err := q.db.Model(&entity.Keyword{}).Where(strings.Join(conditions, " OR "), args...).
+		Limit(1000).Pluck("keyword", &keywords).Error; err != nil {
+		log.Errorf("search: %s", err)
+		return results
+	}
+
+	for _, k := range keywords {
+		if k != word && txt.Similarity(word, k) >= FuzzySimilarity {
+			results = append(results, k)
+		}
+	}
+
+	return results
+}
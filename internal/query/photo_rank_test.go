@@ -0,0 +1,66 @@
+package query
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchWords(t *testing.T) {
+	t.Run("keywords", func(t *testing.T) {
+		assert.Equal(t, []string{"bicycles", "red"}, searchWords("Red Bicycles"))
+	})
+	t.Run("short", func(t *testing.T) {
+		assert.Equal(t, []string{"ny"}, searchWords("NY"))
+	})
+}
+
+func TestExprText(t *testing.T) {
+	t.Run("words", func(t *testing.T) {
+		expr, err := form.ParseExpr("red bicycle label:cat")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "red bicycle", exprText(expr))
+	})
+	t.Run("not", func(t *testing.T) {
+		expr, err := form.ParseExpr("beach OR sunset NOT dog")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "beach sunset", exprText(expr))
+	})
+	t.Run("filters only", func(t *testing.T) {
+		expr, err := form.ParseExpr("year:2019 country:de")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "", exprText(expr))
+	})
+}
+
+func TestRankExpr(t *testing.T) {
+	t.Run("one word", func(t *testing.T) {
+		score, scoreArgs, where, whereArgs := rankExpr([][]string{{"bicycl", "bicycle"}})
+
+		assert.Equal(t, 4, strings.Count(score, "CASE WHEN"))
+		assert.True(t, strings.HasPrefix(score, "(CASE WHEN (LOWER(photos.photo_title) LIKE ? OR LOWER(photos.photo_title) LIKE ?) THEN 8 ELSE 0 END + "))
+		assert.Equal(t, []interface{}{"%bicycl%", "%bicycle%", "bicycl%", "bicycle%"}, scoreArgs[:4])
+		assert.Len(t, scoreArgs, 8)
+		assert.Equal(t, 10, strings.Count(where, "?"))
+		assert.Len(t, whereArgs, 10)
+		assert.Equal(t, "bicycle%", whereArgs[9])
+	})
+	t.Run("two words", func(t *testing.T) {
+		_, scoreArgs, where, whereArgs := rankExpr([][]string{{"red"}, {"bicycl"}})
+
+		assert.Equal(t, 1, strings.Count(where, ") AND ("))
+		assert.Len(t, scoreArgs, 8)
+		assert.Len(t, whereArgs, 10)
+	})
+	t.Run("empty", func(t *testing.T) {
+		score, _, where, _ := rankExpr(nil)
+
+		assert.Equal(t, "0", score)
+		assert.Equal(t, "1 = 1", where)
+	})
+}
@@ -82,6 +82,42 @@ func TestQuery_PreloadPhotoByUUID(t *testing.T) {
 	})
 }
 
+func TestQuery_PhotoPage(t *testing.T) {
+	conf := config.TestConfig()
+
+	search := New(conf.Db())
+
+	t.Run("next page", func(t *testing.T) {
+		first, next, err := search.PhotoPage(form.PhotoSearch{Count: 1})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, first, 1)
+		assert.NotEmpty(t, next)
+
+		second, _, err := search.PhotoPage(form.PhotoSearch{Count: 1, Cursor: next})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, second, 1)
+		assert.NotEqual(t, first[0].ID, second[0].ID)
+	})
+	t.Run("last page", func(t *testing.T) {
+		results, next, err := search.PhotoPage(form.PhotoSearch{Count: 1000})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.NotEmpty(t, results)
+		assert.Empty(t, next)
+	})
+}
+
 func TestSearch_Photos(t *testing.T) {
 	conf := config.TestConfig()
 
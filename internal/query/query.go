@@ -8,6 +8,7 @@ https://github.com/photoprism/photoprism/wiki
 package query
 
 import (
+	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/event"
 
 	"github.com/jinzhu/gorm"
@@ -15,12 +16,14 @@ import (
 
 var log = event.Log
 
-// About 1km ('good enough' for now)
-const SearchRadius = 0.009
-
 // Query searches given an originals path and a db instance.
 type Query struct {
-	db *gorm.DB
+	db         *gorm.DB
+	user       *entity.User
+	share      string
+	shareWhere string
+	shareArgs  []interface{}
+	everyone   bool
 }
 
 // SearchCount is the total number of search hits.
@@ -36,3 +39,45 @@ func New(db *gorm.DB) *Query {
 
 	return q
 }
+
+// WithUser returns a query that only finds photos and albums visible to the user, admins can see everything.
+// Queries without user are not restricted, e.g. when used by workers and commands.
+func (q *Query) WithUser(user *entity.User) *Query {
+	result := &Query{
+		db:   q.db,
+		user: user,
+	}
+
+	return result
+}
+
+// WithShare returns a query that only finds the public photos of a shared album, label, folder or photo,
+// which is used for visitors of share links. Shared smart albums contain the photos matching their filter,
+// and shared folders the photos in the folder and its subfolders.
+func (q *Query) WithShare(shareUUID string) *Query {
+	result := &Query{
+		db:    q.db,
+		share: shareUUID,
+	}
+
+	if album, err := q.AlbumByUUID(shareUUID); err == nil && album.IsSmart() {
+		if result.shareWhere, result.shareArgs, _, err = q.smartAlbumCondition(album.AlbumFilter); err != nil {
+			log.Errorf("share: %s", err)
+		}
+	} else if folder, err := q.FolderByUUID(shareUUID); err == nil {
+		result.shareWhere, result.shareArgs = folderCondition(folder.FolderPath, true)
+	}
+
+	return result
+}
+
+// WithEveryone returns a query that only finds photos and albums visible to everyone,
+// which is used for public images like the preview collage.
+func (q *Query) WithEveryone() *Query {
+	result := &Query{
+		db:       q.db,
+		everyone: true,
+	}
+
+	return result
+}
@@ -7,7 +7,7 @@ import (
 	"github.com/photoprism/photoprism/internal/form"
 )
 
-// PhotoSelection returns all selected photos.
+// PhotoSelection returns all selected photos visible to the user.
 func (q *Query) PhotoSelection(f form.Selection) (results []entity.Photo, err error) {
 	if f.Empty() {
 		return results, errors.New("no photos selected")
@@ -24,6 +24,10 @@ func (q *Query) PhotoSelection(f form.Selection) (results []entity.Photo, err er
 
 	s = s.Where("photos.photo_uuid IN (?) OR labels.label_uuid IN (?)", f.Photos, f.Labels)
 
+	if where, args := q.photoVisibility(); where != "" {
+		s = s.Where(where, args...)
+	}
+
 	if result := s.Scan(&results); result.Error != nil {
 		return results, result.Error
 	}
@@ -0,0 +1,30 @@
+package query
+
+import (
+	"github.com/photoprism/photoprism/internal/entity"
+)
+
+// TokenBySecret returns an API token based on its secret.
+func (q *Query) TokenBySecret(secret string) (token entity.Token, err error) {
+	if err := q.db.Where("id = ?", entity.TokenHash(secret)).First(&token).Error; err != nil {
+		return token, err
+	}
+
+	return token, nil
+}
+
+// Tokens returns the API tokens of a user, newest first.
+func (q *Query) Tokens(userUUID string) (results []entity.Token, err error) {
+	if err := q.db.Where("user_uuid = ?", userUUID).Order("created_at DESC").Find(&results).Error; err != nil {
+		return results, err
+	}
+
+	return results, nil
+}
+
+// DeleteToken deletes an API token of a user, it returns false if no such token exists.
+func (q *Query) DeleteToken(userUUID, tokenUUID string) (bool, error) {
+	result := q.db.Where("user_uuid = ? AND token_uuid = ?", userUUID, tokenUUID).Delete(&entity.Token{})
+
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,49 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery_TokenBySecret(t *testing.T) {
+	conf := config.TestConfig()
+
+	q := New(conf.Db())
+
+	m, secret, err := entity.NewToken("u000000000000001", "Backup", acl.Scopes{acl.ScopePhotosRead}, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conf.Db().Create(m).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("existing secret", func(t *testing.T) {
+		token, err := q.TokenBySecret(secret)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "Backup", token.TokenName)
+	})
+	t.Run("not existing secret", func(t *testing.T) {
+		_, err := q.TokenBySecret("xxx")
+		assert.Error(t, err)
+	})
+	t.Run("delete", func(t *testing.T) {
+		found, err := q.DeleteToken("u000000000000002", m.TokenUUID)
+		assert.Nil(t, err)
+		assert.False(t, found)
+
+		found, err = q.DeleteToken("u000000000000001", m.TokenUUID)
+		assert.Nil(t, err)
+		assert.True(t, found)
+	})
+}
@@ -0,0 +1,34 @@
+package query
+
+import (
+	"strings"
+
+	"github.com/photoprism/photoprism/internal/entity"
+)
+
+// UserByName returns a user based on the login name.
+func (q *Query) UserByName(userName string) (user entity.User, err error) {
+	if err := q.db.Where("user_name = ?", strings.ToLower(strings.TrimSpace(userName))).First(&user).Error; err != nil {
+		return user, err
+	}
+
+	return user, nil
+}
+
+// UserByUUID returns a user based on the UUID.
+func (q *Query) UserByUUID(userUUID string) (user entity.User, err error) {
+	if err := q.db.Where("user_uuid = ?", userUUID).First(&user).Error; err != nil {
+		return user, err
+	}
+
+	return user, nil
+}
+
+// Users returns all users sorted by login name.
+func (q *Query) Users() (results []entity.User, err error) {
+	if err := q.db.Order("user_name").Find(&results).Error; err != nil {
+		return results, err
+	}
+
+	return results, nil
+}
@@ -0,0 +1,152 @@
+package query
+
+import (
+	"fmt"
+
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/entity"
+)
+
+// restricted returns true if results must be filtered by visibility.
+func (q *Query) restricted() bool {
+	return q.everyone || q.share != "" || q.user != nil && q.user.Role != acl.RoleAdmin
+}
+
+// userUUID returns the UUID of the user, or an empty string if the query is for everyone, which
+// doesn't match any owner or user share.
+func (q *Query) userUUID() string {
+	if q.user == nil {
+		return ""
+	}
+
+	return q.user.UserUUID
+}
+
+// visibleCondition returns the condition for rows of a table owned by other users that are visible to the user,
+// rows without owner and with an empty visibility were created before users existed and are visible to everyone.
+func visibleCondition(table, uuidColumn, visibilityColumn, userUUID string) (where string, args []interface{}) {
+	where = fmt.Sprintf(`(%[1]s.owner_uuid = '' OR %[1]s.owner_uuid = ? OR %[1]s.%[3]s IN ('', ?)
+		OR (%[1]s.%[3]s = ? AND EXISTS (SELECT 1 FROM user_shares us WHERE us.share_uuid = %[1]s.%[2]s AND us.user_uuid = ?)))`,
+		table, uuidColumn, visibilityColumn)
+
+	args = []interface{}{userUUID, entity.VisibilityEveryone, entity.VisibilityShared, userUUID}
+
+	return where, args
+}
+
+// photoVisibility returns the condition for photos visible to the user, which includes photos in visible
+// albums of the same owner, or an empty string if the query isn't restricted.
+func (q *Query) photoVisibility() (where string, args []interface{}) {
+	if !q.restricted() {
+		return "", nil
+	}
+
+	if q.share != "" {
+		return shareCondition(q.share, q.shareWhere, q.shareArgs)
+	}
+
+	photoWhere, photoArgs := visibleCondition("photos", "photo_uuid", "photo_visibility", q.userUUID())
+	albumWhere, albumArgs := visibleCondition("a", "album_uuid", "album_visibility", q.userUUID())
+
+	where = fmt.Sprintf(`(%s OR EXISTS (SELECT 1 FROM photos_albums pa JOIN albums a ON a.album_uuid = pa.album_uuid
+		WHERE pa.photo_uuid = photos.photo_uuid AND a.deleted_at IS NULL AND a.owner_uuid = photos.owner_uuid AND %s))`,
+		photoWhere, albumWhere)
+
+	return where, append(photoArgs, albumArgs...)
+}
+
+// shareCondition returns the condition for public photos that are shared with a link to the photo itself,
+// or an album or label containing it. Photos of shared smart albums and folders are found with the
+// additional share condition. Private photos of other users than the owner of the shared item are
+// excluded, so that links only share what their owner could share with everyone.
+func shareCondition(shareUUID, shareWhere string, shareArgs []interface{}) (where string, args []interface{}) {
+	if shareWhere == "" {
+		shareWhere = "0 = 1"
+	}
+
+	where = `(photos.photo_private = 0 AND photos.deleted_at IS NULL
+		AND (photos.owner_uuid = '' OR photos.photo_visibility IN ('', ?)
+			OR photos.owner_uuid IN (SELECT a.owner_uuid FROM albums a WHERE a.album_uuid = ?
+				UNION SELECT l.owner_uuid FROM labels l WHERE l.label_uuid = ?
+				UNION SELECT p.owner_uuid FROM photos p WHERE p.photo_uuid = ?))
+		AND (photos.photo_uuid = ? OR ` + shareWhere + `
+		OR EXISTS (SELECT 1 FROM photos_albums pa WHERE pa.photo_uuid = photos.photo_uuid AND pa.album_uuid = ?)
+		OR EXISTS (SELECT 1 FROM photos_labels pl JOIN labels l ON l.id = pl.label_id
+			WHERE pl.photo_id = photos.id AND pl.label_uncertainty < 100 AND l.label_uuid = ?)))`
+
+	args = []interface{}{entity.VisibilityEveryone, shareUUID, shareUUID, shareUUID, shareUUID}
+	args = append(args, shareArgs...)
+
+	return where, append(args, shareUUID, shareUUID)
+}
+
+// albumVisibility returns the condition for albums visible to the user, or an empty string if the query isn't restricted.
+func (q *Query) albumVisibility() (where string, args []interface{}) {
+	if !q.restricted() {
+		return "", nil
+	}
+
+	if q.share != "" {
+		return "albums.album_uuid = ?", []interface{}{q.share}
+	}
+
+	return visibleCondition("albums", "album_uuid", "album_visibility", q.userUUID())
+}
+
+// blurHashVisibility returns the condition for placeholders of album and label covers, so that
+// only photos visible to the user are used. It is empty or starts with AND.
+func (q *Query) blurHashVisibility() (where string, args []interface{}) {
+	if where, args = q.photoVisibility(); where != "" {
+		return " AND " + where, args
+	}
+
+	return "", nil
+}
+
+// visibleFiles restricts a files scope to files of photos visible to the user.
+func (q *Query) visibleFiles(s *gorm.DB) *gorm.DB {
+	where, args := q.photoVisibility()
+
+	if where == "" {
+		return s
+	}
+
+	return s.Joins("JOIN photos ON photos.id = files.photo_id").Where(where, args...)
+}
+
+// PhotoVisible returns true if the photo exists and is visible to the user.
+func (q *Query) PhotoVisible(photoUUID string) bool {
+	s := q.db.Table("photos").Where("photos.photo_uuid = ?", photoUUID)
+
+	if where, args := q.photoVisibility(); where != "" {
+		s = s.Where(where, args...)
+	}
+
+	var count int
+
+	if err := s.Count(&count).Error; err != nil {
+		log.Errorf("photo: %s", err)
+		return false
+	}
+
+	return count > 0
+}
+
+// AlbumVisible returns true if the album exists and is visible to the user.
+func (q *Query) AlbumVisible(albumUUID string) bool {
+	s := q.db.Table("albums").Where("albums.album_uuid = ? AND albums.deleted_at IS NULL", albumUUID)
+
+	if where, args := q.albumVisibility(); where != "" {
+		s = s.Where(where, args...)
+	}
+
+	var count int
+
+	if err := s.Count(&count).Error; err != nil {
+		log.Errorf("album: %s", err)
+		return false
+	}
+
+	return count > 0
+}
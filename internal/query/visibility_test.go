@@ -0,0 +1,91 @@
+package query
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery_PhotoVisibility(t *testing.T) {
+	q := &Query{}
+
+	t.Run("no user", func(t *testing.T) {
+		where, args := q.photoVisibility()
+		assert.Equal(t, "", where)
+		assert.Empty(t, args)
+	})
+	t.Run("admin", func(t *testing.T) {
+		where, args := q.WithUser(&entity.Admin).photoVisibility()
+		assert.Equal(t, "", where)
+		assert.Empty(t, args)
+	})
+	t.Run("viewer", func(t *testing.T) {
+		user := &entity.User{UserUUID: "u1", Role: acl.RoleViewer}
+		where, args := q.WithUser(user).photoVisibility()
+		assert.Contains(t, where, "photos.owner_uuid = ?")
+		assert.Contains(t, where, "a.album_visibility IN ('', ?)")
+		assert.Equal(t, []interface{}{"u1", "everyone", "shared", "u1", "u1", "everyone", "shared", "u1"}, args)
+	})
+	t.Run("everyone", func(t *testing.T) {
+		where, args := q.WithEveryone().photoVisibility()
+		assert.Contains(t, where, "photos.photo_visibility IN ('', ?)")
+		assert.Equal(t, []interface{}{"", "everyone", "shared", "", "", "everyone", "shared", ""}, args)
+	})
+	t.Run("share", func(t *testing.T) {
+		where, args := (&Query{share: "at9lxuqxpogaaba7"}).photoVisibility()
+		assert.Contains(t, where, "photos.photo_private = 0")
+		assert.Contains(t, where, "pa.album_uuid = ?")
+		assert.Contains(t, where, "photos.photo_visibility IN ('', ?)")
+		assert.Equal(t, "everyone", args[0])
+		assert.Len(t, args, 7)
+	})
+	t.Run("shared folder", func(t *testing.T) {
+		folderWhere, folderArgs := folderCondition("2019/06_x", true)
+		where, args := (&Query{share: "dqbevau2zlhxrxww", shareWhere: folderWhere, shareArgs: folderArgs}).photoVisibility()
+		assert.Contains(t, where, "photos.photo_path LIKE ?")
+		assert.Contains(t, args, "2019/06\\_x/%")
+		assert.Len(t, args, 9)
+	})
+}
+
+func TestQuery_AlbumVisibility(t *testing.T) {
+	user := &entity.User{UserUUID: "u1", Role: acl.RoleEditor}
+	where, args := New(nil).WithUser(user).albumVisibility()
+	assert.Contains(t, where, "albums.owner_uuid = ?")
+	assert.Contains(t, where, "us.share_uuid = albums.album_uuid")
+	assert.Equal(t, []interface{}{"u1", "everyone", "shared", "u1"}, args)
+
+	where, args = (&Query{share: "at9lxuqxpogaaba7"}).albumVisibility()
+	assert.Equal(t, "albums.album_uuid = ?", where)
+	assert.Equal(t, []interface{}{"at9lxuqxpogaaba7"}, args)
+}
+
+func TestQuery_BlurHashVisibility(t *testing.T) {
+	where, args := New(nil).blurHashVisibility()
+	assert.Equal(t, "", where)
+	assert.Empty(t, args)
+
+	user := &entity.User{UserUUID: "u1", Role: acl.RoleGuest}
+	where, args = New(nil).WithUser(user).blurHashVisibility()
+	assert.True(t, strings.HasPrefix(where, " AND "))
+	assert.Contains(t, where, "photos.owner_uuid = ?")
+	assert.Len(t, args, 8)
+}
+
+func TestQuery_PhotoVisible(t *testing.T) {
+	conf := config.TestConfig()
+
+	search := New(conf.Db())
+
+	t.Run("no owner", func(t *testing.T) {
+		user := &entity.User{UserUUID: "u1", Role: acl.RoleGuest}
+		assert.True(t, search.WithUser(user).PhotoVisible("654"))
+	})
+	t.Run("not existing", func(t *testing.T) {
+		assert.False(t, search.PhotoVisible("xxx"))
+	})
+}
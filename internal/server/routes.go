@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/photoprism/photoprism/internal/acl"
 	"github.com/photoprism/photoprism/internal/api"
 	"github.com/photoprism/photoprism/internal/config"
 )
@@ -15,81 +16,163 @@ func registerRoutes(router *gin.Engine, conf *config.Config) {
 	// Static assets like js and css files
 	router.Static("/static", conf.HttpStaticPath())
 
+	// Share links open a visitor session.
+	api.VisitLink(router.Group("/s"), conf)
+
 	// JSON-REST API Version 1
 	v1 := router.Group("/api/v1")
 	{
 		api.CreateSession(v1, conf)
 		api.DeleteSession(v1, conf)
 
+		// Images and downloads are requested by the browser without session header.
 		api.GetPreview(v1, conf)
 		api.GetThumbnail(v1, conf)
 		api.GetDownload(v1, conf)
-		api.CreateZip(v1, conf)
 		api.DownloadZip(v1, conf)
-
-		api.GetGeo(v1, conf)
-		api.GetPhoto(v1, conf)
-		api.UpdatePhoto(v1, conf)
-		api.GetPhotos(v1, conf)
 		api.GetPhotoDownload(v1, conf)
-		api.LinkPhoto(v1, conf)
-		api.LikePhoto(v1, conf)
-		api.DislikePhoto(v1, conf)
-		api.AddPhotoLabel(v1, conf)
-		api.RemovePhotoLabel(v1, conf)
-		api.GetMomentsTime(v1, conf)
-		api.GetFile(v1, conf)
-		api.LinkFile(v1, conf)
-
-		api.GetLabels(v1, conf)
-		api.UpdateLabel(v1, conf)
-		api.LinkLabel(v1, conf)
-		api.LikeLabel(v1, conf)
-		api.DislikeLabel(v1, conf)
-		api.LabelThumbnail(v1, conf)
-
-		api.Upload(v1, conf)
-		api.StartImport(v1, conf)
-		api.CancelImport(v1, conf)
-		api.StartIndexing(v1, conf)
-		api.CancelIndexing(v1, conf)
-
-		api.BatchPhotosArchive(v1, conf)
-		api.BatchPhotosRestore(v1, conf)
-		api.BatchPhotosPrivate(v1, conf)
-		api.BatchPhotosStory(v1, conf)
-		api.BatchAlbumsDelete(v1, conf)
-		api.BatchLabelsDelete(v1, conf)
-
-		api.GetAlbum(v1, conf)
-		api.CreateAlbum(v1, conf)
-		api.UpdateAlbum(v1, conf)
-		api.DeleteAlbum(v1, conf)
 		api.DownloadAlbum(v1, conf)
-		api.GetAlbums(v1, conf)
-		api.LinkAlbum(v1, conf)
-		api.LikeAlbum(v1, conf)
-		api.DislikeAlbum(v1, conf)
+		api.LabelThumbnail(v1, conf)
 		api.AlbumThumbnail(v1, conf)
-		api.AddPhotosToAlbum(v1, conf)
-		api.RemovePhotosFromAlbum(v1, conf)
-
-		api.GetAccounts(v1, conf)
-		api.GetAccount(v1, conf)
-		api.GetAccountDirs(v1, conf)
-		api.ShareWithAccount(v1, conf)
-		api.CreateAccount(v1, conf)
-		api.DeleteAccount(v1, conf)
-		api.UpdateAccount(v1, conf)
-
-		api.GetSettings(v1, conf)
-		api.SaveSettings(v1, conf)
 
 		api.GetSvg(v1)
 
 		api.Websocket(v1, conf)
 	}
 
+	// Browsing requires a login with any role, API tokens need the photos:read scope.
+	guest := v1.Group("", api.Auth(conf, acl.RoleGuest, acl.ScopePhotosRead))
+	{
+		api.GetGeo(guest, conf)
+		api.GetGeoClusters(guest, conf)
+		api.GetPhoto(guest, conf)
+		api.GetPhotos(guest, conf)
+		api.GetMomentsTime(guest, conf)
+		api.GetMemories(guest, conf)
+		api.GetEvents(guest, conf)
+		api.GetFile(guest, conf)
+		api.GetLabels(guest, conf)
+		api.GetAlbum(guest, conf)
+		api.GetAlbums(guest, conf)
+		api.GetFolders(guest, conf)
+		api.GetFolder(guest, conf)
+		api.GetSettings(guest, conf)
+		api.GetPhotoComments(guest, conf)
+		api.GetAlbumComments(guest, conf)
+	}
+
+	// Share link visitors may only list what has been shared with them.
+	visitor := v1.Group("", api.Visitor(conf))
+	{
+		api.GetShared(visitor, conf)
+		api.GetSharedPhotos(visitor, conf)
+		api.GetSharedComments(visitor, conf)
+		api.CreateSharedComment(visitor, conf)
+	}
+
+	// Sessions and API tokens can't be managed with API tokens, unless they have the admin scope.
+	account := v1.Group("", api.Auth(conf, acl.RoleGuest, acl.ScopeAdmin))
+	{
+		api.GetSessions(account, conf)
+		api.RevokeSession(account, conf)
+		api.GetTokens(account, conf)
+		api.CreateToken(account, conf)
+		api.DeleteToken(account, conf)
+	}
+
+	// Users with any role may comment, owners of photos and albums moderate comments.
+	comments := v1.Group("", api.Auth(conf, acl.RoleGuest, acl.ScopePhotosWrite))
+	{
+		api.CreatePhotoComment(comments, conf)
+		api.CreateAlbumComment(comments, conf)
+		api.UpdateComment(comments, conf)
+		api.DeleteComment(comments, conf)
+	}
+
+	// Viewers may additionally download multiple originals.
+	viewer := v1.Group("", api.Auth(conf, acl.RoleViewer, acl.ScopePhotosRead))
+	{
+		api.CreateZip(viewer, conf)
+	}
+
+	// Editors may change the library.
+	editor := v1.Group("", api.Auth(conf, acl.RoleEditor, acl.ScopePhotosWrite))
+	{
+		api.UpdatePhoto(editor, conf)
+		api.LinkPhoto(editor, conf)
+		api.SharePhoto(editor, conf)
+		api.LikePhoto(editor, conf)
+		api.DislikePhoto(editor, conf)
+		api.AddPhotoLabel(editor, conf)
+		api.RemovePhotoLabel(editor, conf)
+		api.LinkFile(editor, conf)
+
+		api.UpdateLabel(editor, conf)
+		api.LinkLabel(editor, conf)
+		api.LikeLabel(editor, conf)
+		api.DislikeLabel(editor, conf)
+
+		api.BatchPhotosArchive(editor, conf)
+		api.BatchPhotosRestore(editor, conf)
+		api.BatchPhotosPrivate(editor, conf)
+		api.BatchPhotosStory(editor, conf)
+		api.BatchLabelsDelete(editor, conf)
+
+		api.GetLinks(editor, conf)
+		api.UpdateLink(editor, conf)
+		api.DeleteLink(editor, conf)
+	}
+
+	// Folders have no owner, so only admins may change and share them.
+	folders := v1.Group("", api.Auth(conf, acl.RoleAdmin, acl.ScopePhotosWrite))
+	{
+		api.CreateFolder(folders, conf)
+		api.UpdateFolder(folders, conf)
+		api.LinkFolder(folders, conf)
+	}
+
+	upload := v1.Group("", api.Auth(conf, acl.RoleEditor, acl.ScopeUpload))
+	{
+		api.Upload(upload, conf)
+	}
+
+	library := v1.Group("", api.Auth(conf, acl.RoleEditor, acl.ScopeImport))
+	{
+		api.StartImport(library, conf)
+		api.CancelImport(library, conf)
+		api.StartIndexing(library, conf)
+		api.CancelIndexing(library, conf)
+	}
+
+	albums := v1.Group("", api.Auth(conf, acl.RoleEditor, acl.ScopeAlbums))
+	{
+		api.CreateAlbum(albums, conf)
+		api.UpdateAlbum(albums, conf)
+		api.DeleteAlbum(albums, conf)
+		api.LinkAlbum(albums, conf)
+		api.ShareAlbum(albums, conf)
+		api.LikeAlbum(albums, conf)
+		api.DislikeAlbum(albums, conf)
+		api.AddPhotosToAlbum(albums, conf)
+		api.RemovePhotosFromAlbum(albums, conf)
+		api.BatchAlbumsDelete(albums, conf)
+	}
+
+	// Admins manage accounts and settings.
+	admin := v1.Group("", api.Auth(conf, acl.RoleAdmin, acl.ScopeAdmin))
+	{
+		api.GetAccounts(admin, conf)
+		api.GetAccount(admin, conf)
+		api.GetAccountDirs(admin, conf)
+		api.ShareWithAccount(admin, conf)
+		api.CreateAccount(admin, conf)
+		api.DeleteAccount(admin, conf)
+		api.UpdateAccount(admin, conf)
+
+		api.SaveSettings(admin, conf)
+		api.DeleteSessions(admin, conf)
+	}
+
 	// WebDAV server for file management / sharing
 	if conf.WebDAVPassword() != "" {
 		log.Info("webdav: enabled, username: photoprism")
@@ -1,31 +1,153 @@
 package session
 
 import (
+	"crypto/sha256"
+	"fmt"
 	"time"
 
-	gc "github.com/patrickmn/go-cache"
+	"github.com/jinzhu/gorm"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/mutex"
+	"github.com/photoprism/photoprism/pkg/txt"
 )
 
-var cache = gc.New(72*time.Hour, 30*time.Minute)
+// TTL is the time after which sessions expire if they are not used, every request extends it.
+var TTL = 72 * time.Hour
+
+// touchInterval limits how often last seen and expiry are updated in the database.
+var touchInterval = time.Minute
+
+// Store persists sessions in the database, so that they survive restarts and can be shared by multiple instances.
+type Store struct {
+	db *gorm.DB
+}
+
+// New returns a new session store.
+func New(db *gorm.DB) *Store {
+	return &Store{db: db}
+}
+
+// Hash returns the SHA256 hash of a session token, which is used as session ID.
+func Hash(token string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(token)))
+}
+
+// Create creates a new session for a user and returns the token.
+func (s *Store) Create(userUUID, clientIP, userAgent string) (token string, err error) {
+	return s.create(entity.Session{UserUUID: userUUID, ClientIP: clientIP, UserAgent: userAgent})
+}
+
+// CreateVisitor creates a new session for a share link visitor and returns the token,
+// it can only be used to view what has been shared with the link.
+func (s *Store) CreateVisitor(link entity.Link, clientIP, userAgent string) (token string, err error) {
+	return s.create(entity.Session{LinkToken: link.LinkToken, ShareUUID: link.ShareUUID, ClientIP: clientIP, UserAgent: userAgent})
+}
+
+// create saves a new session and returns the token.
+func (s *Store) create(m entity.Session) (token string, err error) {
+	token = Token()
+	now := time.Now().UTC()
+
+	m.ID = Hash(token)
+	m.ClientIP = txt.Clip(m.ClientIP, 64)
+	m.UserAgent = txt.Clip(m.UserAgent, 512)
+	m.CreatedAt = now
+	m.LastSeen = now
+	m.ExpiresAt = now.Add(TTL)
+
+	mutex.Db.Lock()
+	defer mutex.Db.Unlock()
+
+	// Remove expired sessions, so that the table doesn't grow forever.
+	if err := s.db.Where("expires_at < ?", now).Delete(&entity.Session{}).Error; err != nil {
+		log.Errorf("session: %s", err)
+	}
+
+	if err := s.db.Create(&m).Error; err != nil {
+		return "", err
+	}
 
-func Create(data interface{}) string {
-	token := Token()
-	cache.Set(token, data, gc.DefaultExpiration)
 	log.Debugf("session: created")
-	return token
+
+	return token, nil
+}
+
+// Get returns the session for a token and extends its expiry.
+func (s *Store) Get(token string) (m entity.Session, exists bool) {
+	if token == "" {
+		return m, false
+	}
+
+	if err := s.db.Where("id = ?", Hash(token)).First(&m).Error; err != nil {
+		return m, false
+	}
+
+	now := time.Now().UTC()
+
+	if m.Expired(now) {
+		s.Delete(token)
+		return m, false
+	}
+
+	if now.Sub(m.LastSeen) < touchInterval {
+		return m, true
+	}
+
+	m.LastSeen = now
+	m.ExpiresAt = now.Add(TTL)
+
+	if err := s.db.Model(&m).Updates(map[string]interface{}{"last_seen": m.LastSeen, "expires_at": m.ExpiresAt}).Error; err != nil {
+		log.Errorf("session: %s", err)
+	}
+
+	return m, true
+}
+
+// Exists returns true if the token belongs to a session that has not expired.
+func (s *Store) Exists(token string) bool {
+	_, exists := s.Get(token)
+
+	return exists
 }
 
-func Delete(token string) {
-	cache.Delete(token)
+// Delete deletes the session of a token.
+func (s *Store) Delete(token string) {
+	if err := s.db.Where("id = ?", Hash(token)).Delete(&entity.Session{}).Error; err != nil {
+		log.Errorf("session: %s", err)
+		return
+	}
+
 	log.Debugf("session: deleted")
 }
 
-func Get(token string) (data interface{}, exists bool) {
-	return cache.Get(token)
+// List returns the sessions of a user that have not expired, most recently used first.
+func (s *Store) List(userUUID string) (results []entity.Session, err error) {
+	err = s.db.Where("user_uuid = ? AND expires_at > ?", userUUID, time.Now().UTC()).
+		Order("last_seen DESC").Find(&results).Error
+
+	return results, err
+}
+
+// Revoke deletes a session of a user, it returns false if no such session exists.
+func (s *Store) Revoke(userUUID, sessionUUID string) (bool, error) {
+	result := s.db.Where("user_uuid = ? AND session_uuid = ?", userUUID, sessionUUID).Delete(&entity.Session{})
+
+	return result.RowsAffected > 0, result.Error
+}
+
+// DeleteUser deletes all sessions of a user, for example after the password was changed.
+func (s *Store) DeleteUser(userUUID string) error {
+	return s.db.Where("user_uuid = ?", userUUID).Delete(&entity.Session{}).Error
+}
+
+// DeleteLink deletes all visitor sessions of a share link, for example after it was revoked.
+func (s *Store) DeleteLink(linkToken string) error {
+	return s.db.Where("link_token = ?", linkToken).Delete(&entity.Session{}).Error
 }
 
-func Exists(token string) bool {
-	_, found := cache.Get(token)
+// DeleteAll deletes all sessions except the one with the given token, so that everyone else is logged out.
+func (s *Store) DeleteAll(except string) (int64, error) {
+	result := s.db.Where("id <> ?", Hash(except)).Delete(&entity.Session{})
 
-	return found
+	return result.RowsAffected, result.Error
 }
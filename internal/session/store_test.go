@@ -3,43 +3,118 @@ package session
 import (
 	"testing"
 
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/stretchr/testify/assert"
 )
 
-func TestCreate(t *testing.T) {
-	token := Create(23)
-	t.Logf("token: %s", token)
-	assert.Equal(t, 48, len(token))
+func TestHash(t *testing.T) {
+	assert.Equal(t, 64, len(Hash("abc")))
+	assert.Equal(t, Hash("abc"), Hash("abc"))
+	assert.NotEqual(t, Hash("abc"), Hash("abd"))
 }
 
-func TestDelete(t *testing.T) {
-	Delete("abc")
-}
+func TestStore_Create(t *testing.T) {
+	s := New(config.TestConfig().Db())
+
+	token, err := s.Create("u000000000000001", "127.0.0.1", "test")
+
+	if err != nil {
+		t.Fatal(err)
+	}
 
-func TestGet(t *testing.T) {
-	token := Create(42)
 	t.Logf("token: %s", token)
 	assert.Equal(t, 48, len(token))
 
-	data, exists := Get(token)
+	s.Delete(token)
+}
+
+func TestStore_Get(t *testing.T) {
+	s := New(config.TestConfig().Db())
+
+	token, err := s.Create("u000000000000001", "127.0.0.1", "test")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, exists := s.Get(token)
 
-	assert.Equal(t, 42, data)
 	assert.True(t, exists)
+	assert.Equal(t, "u000000000000001", m.UserUUID)
+	assert.Equal(t, "127.0.0.1", m.ClientIP)
+	assert.Equal(t, "test", m.UserAgent)
 
-	Delete(token)
+	s.Delete(token)
 
-	data, exists = Get(token)
+	_, exists = s.Get(token)
 
-	assert.Nil(t, data)
-	assert.False(t, Exists(token))
+	assert.False(t, exists)
+	assert.False(t, s.Exists(token))
 }
 
-func TestExists(t *testing.T) {
-	assert.False(t, Exists("xyz"))
-	token := Create(23)
-	t.Logf("token: %s", token)
-	assert.Equal(t, 48, len(token))
-	assert.True(t, Exists(token))
-	Delete(token)
-	assert.False(t, Exists(token))
+func TestStore_Exists(t *testing.T) {
+	s := New(config.TestConfig().Db())
+
+	assert.False(t, s.Exists(""))
+	assert.False(t, s.Exists("xyz"))
+}
+
+func TestStore_Revoke(t *testing.T) {
+	s := New(config.TestConfig().Db())
+
+	token, err := s.Create("u000000000000001", "127.0.0.1", "test")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, _ := s.Get(token)
+
+	t.Run("other user", func(t *testing.T) {
+		found, err := s.Revoke("u000000000000002", m.SessionUUID)
+		assert.Nil(t, err)
+		assert.False(t, found)
+		assert.True(t, s.Exists(token))
+	})
+	t.Run("owner", func(t *testing.T) {
+		found, err := s.Revoke("u000000000000001", m.SessionUUID)
+		assert.Nil(t, err)
+		assert.True(t, found)
+		assert.False(t, s.Exists(token))
+	})
+}
+
+func TestStore_DeleteAll(t *testing.T) {
+	s := New(config.TestConfig().Db())
+
+	current, _ := s.Create("u000000000000001", "127.0.0.1", "current")
+	other, _ := s.Create("u000000000000001", "127.0.0.1", "other")
+
+	_, err := s.DeleteAll(current)
+
+	assert.Nil(t, err)
+	assert.True(t, s.Exists(current))
+	assert.False(t, s.Exists(other))
+
+	s.Delete(current)
+}
+
+func TestStore_CreateVisitor(t *testing.T) {
+	s := New(config.TestConfig().Db())
+
+	token, err := s.CreateVisitor(entity.Link{LinkToken: "1jxf3jfn2k", ShareUUID: "at9lxuqxpogaaba7"}, "127.0.0.1", "test")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, exists := s.Get(token)
+
+	assert.True(t, exists)
+	assert.True(t, m.Visitor())
+	assert.Equal(t, "", m.UserUUID)
+	assert.Equal(t, "at9lxuqxpogaaba7", m.ShareUUID)
+
+	s.Delete(token)
 }
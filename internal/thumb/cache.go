@@ -0,0 +1,230 @@
+package thumb
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LegacyType is the type name of thumbnails rendered with a crop method that is no longer used.
+const LegacyType = "legacy"
+
+// legacyPostfixes contains the postfixes of tiles that were center cropped before smart crop was added.
+var legacyPostfixes = map[string]bool{
+	"50x50_center":   true,
+	"100x100_center": true,
+	"224x224_center": true,
+	"500x500_center": true,
+	"224x224_left":   true,
+	"224x224_right":  true,
+}
+
+// CacheFile represents a cached thumbnail file.
+type CacheFile struct {
+	Name     string
+	TypeName string
+	Size     int64
+	Accessed time.Time
+}
+
+// OnDemand returns true if the file was not pre-rendered and may be evicted.
+func (f CacheFile) OnDemand() bool {
+	if f.Legacy() {
+		return true
+	}
+
+	if t, ok := Types[f.TypeName]; ok {
+		return t.SkipPreRender()
+	}
+
+	return false
+}
+
+// Legacy returns true if the file is no longer used and can be removed.
+func (f CacheFile) Legacy() bool {
+	return f.TypeName == LegacyType
+}
+
+// CacheFiles represents a list of cached thumbnail files.
+type CacheFiles []CacheFile
+
+// Size returns the total size of all files in bytes.
+func (files CacheFiles) Size() (result int64) {
+	for _, f := range files {
+		result += f.Size
+	}
+
+	return result
+}
+
+// CacheUsage contains the number of files and their size for a thumbnail type.
+type CacheUsage struct {
+	TypeName string
+	Files    int
+	Size     int64
+}
+
+// Usage returns the cache usage grouped by thumbnail type, files of unknown type are listed as "other".
+func (files CacheFiles) Usage() (result []CacheUsage) {
+	usage := make(map[string]*CacheUsage)
+
+	for _, f := range files {
+		name := f.TypeName
+
+		if name == "" {
+			name = "other"
+		}
+
+		if _, ok := usage[name]; !ok {
+			usage[name] = &CacheUsage{TypeName: name}
+		}
+
+		usage[name].Files++
+		usage[name].Size += f.Size
+	}
+
+	for _, u := range usage {
+		result = append(result, *u)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Size > result[j].Size
+	})
+
+	return result
+}
+
+// TypeName returns the thumbnail type name for a cached file name, or an empty string if unknown.
+func TypeName(fileName string) string {
+	base := filepath.Base(fileName)
+	i := strings.Index(base, "_")
+
+	if i < 0 {
+		return ""
+	}
+
+	postfix := strings.TrimSuffix(base[i+1:], filepath.Ext(base))
+
+	for name, t := range Types {
+		method, _, _ := ResampleOptions(t.Options...)
+
+		if postfix == fmt.Sprintf("%dx%d_%s", t.Width, t.Height, ResampleMethods[method]) {
+			return name
+		}
+	}
+
+	if legacyPostfixes[postfix] {
+		return LegacyType
+	}
+
+	return ""
+}
+
+// Cached returns all files in the thumbnail cache path.
+func Cached(thumbPath string) (result CacheFiles, err error) {
+	err = filepath.Walk(thumbPath, func(fileName string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+
+		result = append(result, CacheFile{
+			Name:     fileName,
+			TypeName: TypeName(fileName),
+			Size:     info.Size(),
+			Accessed: info.ModTime(),
+		})
+
+		return nil
+	})
+
+	return result, err
+}
+
+// Touch updates the access time of a cached thumbnail, the modification time is used as access time
+// as many file systems are mounted with noatime or relatime.
+func Touch(fileName string) error {
+	now := time.Now()
+
+	return os.Chtimes(fileName, now, now)
+}
+
+// Evict removes least recently used on-demand thumbnails until the cache size is within quota (bytes).
+func Evict(thumbPath string, quota int64) (removed int, freed int64, err error) {
+	if quota <= 0 {
+		return 0, 0, nil
+	}
+
+	files, err := Cached(thumbPath)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	size := files.Size()
+
+	if size <= quota {
+		return 0, 0, nil
+	}
+
+	// Legacy thumbnails are removed first.
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].Legacy() != files[j].Legacy() {
+			return files[i].Legacy()
+		}
+
+		return files[i].Accessed.Before(files[j].Accessed)
+	})
+
+	for _, f := range files {
+		if size <= quota {
+			break
+		}
+
+		if !f.OnDemand() {
+			continue
+		}
+
+		if err := os.Remove(f.Name); err != nil {
+			log.Errorf("thumbs: can't remove %s (%s)", f.Name, err)
+			continue
+		}
+
+		removed++
+		freed += f.Size
+		size -= f.Size
+	}
+
+	if size > quota {
+		log.Warnf("thumbs: cache size exceeds quota by %d bytes, pre-rendered thumbnails are not evicted", size-quota)
+	}
+
+	return removed, freed, nil
+}
+
+// RemoveLegacy removes all thumbnails rendered with a crop method that is no longer used.
+func RemoveLegacy(thumbPath string) (removed int, freed int64, err error) {
+	files, err := Cached(thumbPath)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, f := range files {
+		if !f.Legacy() {
+			continue
+		}
+
+		if err := os.Remove(f.Name); err != nil {
+			log.Errorf("thumbs: can't remove %s (%s)", f.Name, err)
+			continue
+		}
+
+		removed++
+		freed += f.Size
+	}
+
+	return removed, freed, nil
+}
@@ -0,0 +1,89 @@
+package thumb
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeName(t *testing.T) {
+	assert.Equal(t, "tile_224", TypeName("/thumbs/a/b/c/abc123_224x224_smart.jpg"))
+	assert.Equal(t, "fit_1280", TypeName("abc123_1280x1024_fit.webp"))
+	assert.Equal(t, "colors", TypeName("abc123_3x3_resize.png"))
+	assert.Equal(t, "", TypeName("preview/2020/04/21.jpg"))
+	assert.Equal(t, LegacyType, TypeName("abc123_224x224_center.jpg"))
+	assert.Equal(t, LegacyType, TypeName("abc123_224x224_left.webp"))
+}
+
+func TestRemoveLegacy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "thumbs")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	files := []string{"abc1_500x500_center.jpg", "abc1_224x224_right.jpg", "abc1_500x500_smart.jpg"}
+
+	for _, name := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), make([]byte, 1000), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, freed, err := RemoveLegacy(dir)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, removed)
+	assert.Equal(t, int64(2000), freed)
+	assert.NoFileExists(t, filepath.Join(dir, files[0]))
+	assert.NoFileExists(t, filepath.Join(dir, files[1]))
+	assert.FileExists(t, filepath.Join(dir, files[2]))
+}
+
+func TestEvict(t *testing.T) {
+	dir, err := ioutil.TempDir("", "thumbs")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	files := []string{"abc1_3840x2400_fit.jpg", "abc2_3840x2400_fit.jpg", "abc3_224x224_smart.jpg"}
+	data := make([]byte, 1000)
+
+	for i, name := range files {
+		fileName := filepath.Join(dir, name)
+
+		if err := ioutil.WriteFile(fileName, data, os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+
+		accessed := time.Now().Add(time.Duration(i-10) * time.Hour)
+
+		if err := os.Chtimes(fileName, accessed, accessed); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	PreRenderSize = 2048
+
+	defer func() {
+		PreRenderSize = 3840
+	}()
+
+	removed, freed, err := Evict(dir, 2500)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, removed)
+	assert.Equal(t, int64(1000), freed)
+	assert.NoFileExists(t, filepath.Join(dir, files[0]))
+	assert.FileExists(t, filepath.Join(dir, files[1]))
+	assert.FileExists(t, filepath.Join(dir, files[2]))
+}
@@ -4,10 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"image"
-	"image/png"
 	"os"
 	"path"
-	"path/filepath"
 
 	"github.com/photoprism/photoprism/pkg/fs"
 
@@ -23,6 +21,10 @@ func ResampleOptions(opts ...ResampleOption) (method ResampleOption, filter imag
 		switch option {
 		case ResamplePng:
 			format = fs.TypePng
+		case ResampleWebp:
+			format = fs.TypeWebP
+		case ResampleAvif:
+			format = fs.TypeAvif
 		case ResampleNearestNeighbor:
 			filter = imaging.NearestNeighbor
 		case ResampleDefault:
@@ -33,6 +35,12 @@ func ResampleOptions(opts ...ResampleOption) (method ResampleOption, filter imag
 			method = ResampleFillCenter
 		case ResampleFillBottomRight:
 			method = ResampleFillBottomRight
+		case ResampleFillSmart:
+			method = ResampleFillSmart
+		case ResampleFillSmartTopLeft:
+			method = ResampleFillSmartTopLeft
+		case ResampleFillSmartBottomRight:
+			method = ResampleFillSmartBottomRight
 		case ResampleFit:
 			method = ResampleFit
 		case ResampleResize:
@@ -58,6 +66,12 @@ func Resample(img *image.Image, width, height int, opts ...ResampleOption) *imag
 		resImg = imaging.Fill(*img, width, height, imaging.TopLeft, filter)
 	} else if method == ResampleFillBottomRight {
 		resImg = imaging.Fill(*img, width, height, imaging.BottomRight, filter)
+	} else if method == ResampleFillSmart {
+		resImg = FillSmart(*img, width, height, imaging.Center, filter)
+	} else if method == ResampleFillSmartTopLeft {
+		resImg = FillSmart(*img, width, height, imaging.TopLeft, filter)
+	} else if method == ResampleFillSmartBottomRight {
+		resImg = FillSmart(*img, width, height, imaging.BottomRight, filter)
 	} else if method == ResampleResize {
 		resImg = imaging.Resize(*img, width, height, filter)
 	}
@@ -122,7 +136,7 @@ func FromFile(imageFilename string, hash string, thumbPath string, width, height
 		return fileName, nil
 	}
 
-	img, err := imaging.Open(imageFilename, imaging.AutoOrientation(true))
+	img, err := Open(imageFilename)
 
 	if err != nil {
 		log.Errorf("thumbs: can't open \"%s\" (%s)", imageFilename, err.Error())
@@ -147,17 +161,13 @@ func Create(img *image.Image, fileName string, width, height int, opts ...Resamp
 
 	result = Resample(img, width, height, opts...)
 
-	var saveOption imaging.EncodeOption
+	quality := JpegQuality
 
-	if filepath.Ext(fileName) == "."+string(fs.TypePng) {
-		saveOption = imaging.PNGCompressionLevel(png.DefaultCompression)
-	} else if width <= 150 && height <= 150 {
-		saveOption = imaging.JPEGQuality(JpegQualitySmall)
-	} else {
-		saveOption = imaging.JPEGQuality(JpegQuality)
+	if width <= 150 && height <= 150 {
+		quality = JpegQualitySmall
 	}
 
-	err = imaging.Save(*result, fileName, saveOption)
+	err = Save(*result, fileName, quality)
 
 	if err != nil {
 		log.Errorf("thumbs: failed to save %s", fileName)
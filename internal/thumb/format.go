@@ -0,0 +1,106 @@
+package thumb
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// Formats lists the negotiable thumbnail output formats in order of preference.
+var Formats = []fs.FileType{fs.TypeAvif, fs.TypeWebP, fs.TypeJpeg}
+
+// FormatMimeTypes maps thumbnail output formats to their mime type.
+var FormatMimeTypes = map[fs.FileType]string{
+	fs.TypeJpeg: fs.MimeTypeJpeg,
+	fs.TypePng:  fs.MimeTypePng,
+	fs.TypeWebP: fs.MimeTypeWebP,
+	fs.TypeAvif: fs.MimeTypeAvif,
+}
+
+// FormatSupported returns true if thumbnails can be saved in the given format.
+func FormatSupported(format fs.FileType) bool {
+	switch format {
+	case fs.TypeJpeg, fs.TypePng:
+		return true
+	case fs.TypeWebP:
+		return WebpBin != ""
+	case fs.TypeAvif:
+		return AvifBin != ""
+	default:
+		return false
+	}
+}
+
+// AcceptFormat returns the best supported output format for an HTTP Accept header (default is JPEG).
+func AcceptFormat(accept string) fs.FileType {
+	result := fs.TypeJpeg
+	best := 0.0
+
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		mimeType := strings.ToLower(strings.TrimSpace(params[0]))
+		q := 1.0
+
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+
+		for _, format := range Formats {
+			if FormatMimeTypes[format] != mimeType || !FormatSupported(format) || q <= 0 {
+				continue
+			}
+
+			if q > best || q == best && formatRank(format) < formatRank(result) {
+				result = format
+				best = q
+			}
+		}
+	}
+
+	return result
+}
+
+// formatRank returns the position of a format in Formats.
+func formatRank(format fs.FileType) int {
+	for i, f := range Formats {
+		if f == format {
+			return i
+		}
+	}
+
+	return len(Formats)
+}
+
+// FormatOptions returns the resample options for creating a thumbnail in the given format.
+func (t Type) FormatOptions(format fs.FileType) []ResampleOption {
+	var formatOption ResampleOption
+
+	switch format {
+	case fs.TypeWebP:
+		formatOption = ResampleWebp
+	case fs.TypeAvif:
+		formatOption = ResampleAvif
+	default:
+		return t.Options
+	}
+
+	result := make([]ResampleOption, 0, len(t.Options)+1)
+
+	for _, option := range t.Options {
+		if option == ResamplePng {
+			// Keep lossless formats as they are.
+			return t.Options
+		}
+
+		result = append(result, option)
+	}
+
+	return append(result, formatOption)
+}
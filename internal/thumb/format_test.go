@@ -0,0 +1,53 @@
+package thumb
+
+import (
+	"testing"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcceptFormat(t *testing.T) {
+	WebpBin = "cwebp"
+	AvifBin = ""
+
+	defer func() {
+		WebpBin = ""
+	}()
+
+	t.Run("empty", func(t *testing.T) {
+		assert.Equal(t, fs.TypeJpeg, AcceptFormat(""))
+	})
+	t.Run("chrome", func(t *testing.T) {
+		assert.Equal(t, fs.TypeWebP, AcceptFormat("image/avif,image/webp,image/apng,image/*,*/*;q=0.8"))
+	})
+	t.Run("safari", func(t *testing.T) {
+		assert.Equal(t, fs.TypeJpeg, AcceptFormat("image/png,image/svg+xml,image/*;q=0.8,video/*;q=0.8,*/*;q=0.5"))
+	})
+	t.Run("webp not acceptable", func(t *testing.T) {
+		assert.Equal(t, fs.TypeJpeg, AcceptFormat("image/webp;q=0, image/*"))
+	})
+	t.Run("avif preferred", func(t *testing.T) {
+		AvifBin = "avifenc"
+		defer func() { AvifBin = "" }()
+
+		assert.Equal(t, fs.TypeAvif, AcceptFormat("image/avif,image/webp,*/*;q=0.8"))
+		assert.Equal(t, fs.TypeWebP, AcceptFormat("image/avif;q=0.5,image/webp,*/*;q=0.8"))
+	})
+}
+
+func TestType_FormatOptions(t *testing.T) {
+	t.Run("webp", func(t *testing.T) {
+		opts := Types["tile_224"].FormatOptions(fs.TypeWebP)
+		assert.Equal(t, []ResampleOption{ResampleFillSmart, ResampleDefault, ResampleWebp}, opts)
+		assert.Equal(t, "224x224_smart.webp", Postfix(224, 224, opts...))
+	})
+	t.Run("jpeg", func(t *testing.T) {
+		opts := Types["fit_720"].FormatOptions(fs.TypeJpeg)
+		assert.Equal(t, Types["fit_720"].Options, opts)
+	})
+	t.Run("png", func(t *testing.T) {
+		opts := Types["colors"].FormatOptions(fs.TypeAvif)
+		assert.Equal(t, Types["colors"].Options, opts)
+	})
+}
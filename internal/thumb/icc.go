@@ -0,0 +1,488 @@
+package thumb
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/disintegration/imaging"
+)
+
+const (
+	ColorSpaceSRGB      = "sRGB"
+	ColorSpaceAdobeRGB  = "Adobe RGB"
+	ColorSpaceDisplayP3 = "Display P3"
+	ColorSpaceProPhoto  = "ProPhoto RGB"
+)
+
+var iccMarker = []byte("ICC_PROFILE\x00")
+
+// ColorSpaces maps well-known color spaces to their D50 adapted RGB to XYZ matrix (columns are red, green and blue).
+var ColorSpaces = map[string][3][3]float64{
+	ColorSpaceSRGB: {
+		{0.4360747, 0.3850649, 0.1430804},
+		{0.2225045, 0.7168786, 0.0606169},
+		{0.0139322, 0.0971045, 0.7141733},
+	},
+	ColorSpaceAdobeRGB: {
+		{0.6097559, 0.2052401, 0.1492240},
+		{0.3111242, 0.6256560, 0.0632197},
+		{0.0194811, 0.0608902, 0.7448387},
+	},
+	ColorSpaceDisplayP3: {
+		{0.5151, 0.2920, 0.1571},
+		{0.2412, 0.6922, 0.0666},
+		{-0.0011, 0.0419, 0.7841},
+	},
+	ColorSpaceProPhoto: {
+		{0.7976749, 0.1351917, 0.0313534},
+		{0.2880402, 0.7118741, 0.0000857},
+		{0.0000000, 0.0000000, 0.8251046},
+	},
+}
+
+// xyzToSRGB converts D50 adapted XYZ values to linear sRGB.
+var xyzToSRGB = [3][3]float64{
+	{3.1338561, -1.6168667, -0.4906146},
+	{-0.9787684, 1.9161415, 0.0334540},
+	{0.0719453, -0.2289914, 1.4052427},
+}
+
+// Profile represents an ICC color profile of the matrix/TRC type.
+type Profile struct {
+	Description string
+	Matrix      [3][3]float64
+	curves      [3]func(float64) float64
+}
+
+// ReadICC returns the ICC profile data embedded in the APP2 segments of a JPEG file, or nil if there is none.
+func ReadICC(r io.Reader) ([]byte, error) {
+	br := bufio.NewReader(r)
+	soi := make([]byte, 2)
+
+	if _, err := io.ReadFull(br, soi); err != nil {
+		return nil, err
+	}
+
+	if soi[0] != 0xFF || soi[1] != 0xD8 {
+		return nil, errors.New("icc: not a jpeg file")
+	}
+
+	chunks := make(map[int][]byte)
+
+	for {
+		b, err := br.ReadByte()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if b != 0xFF {
+			return nil, errors.New("icc: invalid jpeg marker")
+		}
+
+		marker, err := br.ReadByte()
+
+		// Skip fill bytes.
+		for err == nil && marker == 0xFF {
+			marker, err = br.ReadByte()
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		// Start of scan or end of image: no more metadata.
+		if marker == 0xDA || marker == 0xD9 {
+			break
+		}
+
+		// Standalone markers without length.
+		if marker == 0x01 || marker >= 0xD0 && marker <= 0xD7 {
+			continue
+		}
+
+		var length uint16
+
+		if err := binary.Read(br, binary.BigEndian, &length); err != nil {
+			return nil, err
+		}
+
+		if length < 2 {
+			return nil, errors.New("icc: invalid segment length")
+		}
+
+		segment := make([]byte, length-2)
+
+		if _, err := io.ReadFull(br, segment); err != nil {
+			return nil, err
+		}
+
+		if marker == 0xE2 && len(segment) > len(iccMarker)+2 && bytes.HasPrefix(segment, iccMarker) {
+			seq := int(segment[len(iccMarker)])
+			chunks[seq] = segment[len(iccMarker)+2:]
+		}
+	}
+
+	if len(chunks) == 0 {
+		return nil, nil
+	}
+
+	var seqs []int
+
+	for seq := range chunks {
+		seqs = append(seqs, seq)
+	}
+
+	sort.Ints(seqs)
+
+	var result []byte
+
+	for _, seq := range seqs {
+		result = append(result, chunks[seq]...)
+	}
+
+	return result, nil
+}
+
+// FileProfile returns the ICC profile embedded in a JPEG file, or nil if there is none.
+func FileProfile(fileName string) (*Profile, error) {
+	f, err := os.Open(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	data, err := ReadICC(f)
+
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	return ParseProfile(data)
+}
+
+// ParseProfile parses ICC profile data, only RGB matrix/TRC profiles are supported.
+func ParseProfile(data []byte) (*Profile, error) {
+	if len(data) < 132 {
+		return nil, errors.New("icc: profile too short")
+	}
+
+	if string(data[16:20]) != "RGB " {
+		return nil, fmt.Errorf("icc: unsupported color space \"%s\"", strings.TrimSpace(string(data[16:20])))
+	}
+
+	tags := make(map[string][]byte)
+	count := int(binary.BigEndian.Uint32(data[128:132]))
+
+	for i := 0; i < count; i++ {
+		pos := 132 + i*12
+
+		if pos+12 > len(data) {
+			return nil, errors.New("icc: invalid tag table")
+		}
+
+		sig := string(data[pos : pos+4])
+		offset := int(binary.BigEndian.Uint32(data[pos+4 : pos+8]))
+		size := int(binary.BigEndian.Uint32(data[pos+8 : pos+12]))
+
+		if offset < 0 || size < 0 || offset+size > len(data) {
+			return nil, fmt.Errorf("icc: invalid tag %s", sig)
+		}
+
+		tags[sig] = data[offset : offset+size]
+	}
+
+	p := &Profile{Description: parseDescription(tags["desc"])}
+
+	for i, sig := range []string{"rXYZ", "gXYZ", "bXYZ"} {
+		xyz, err := parseXYZ(tags[sig])
+
+		if err != nil {
+			return nil, err
+		}
+
+		for row := 0; row < 3; row++ {
+			if !finite(xyz[row]) {
+				return nil, fmt.Errorf("icc: invalid tag %s", sig)
+			}
+
+			p.Matrix[row][i] = xyz[row]
+		}
+	}
+
+	for i, sig := range []string{"rTRC", "gTRC", "bTRC"} {
+		curve, err := parseCurve(tags[sig])
+
+		if err != nil {
+			return nil, err
+		}
+
+		// Curves with invalid parameters may return NaN or Inf, so all 8 bit input values are checked.
+		for x := 0; x < 256; x++ {
+			if !finite(curve(float64(x) / 255)) {
+				return nil, fmt.Errorf("icc: invalid tag %s", sig)
+			}
+		}
+
+		p.curves[i] = curve
+	}
+
+	return p, nil
+}
+
+// finite returns true if v is neither NaN nor infinite.
+func finite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+// s15Fixed16 decodes a signed 15.16 fixed point number.
+func s15Fixed16(b []byte) float64 {
+	return float64(int32(binary.BigEndian.Uint32(b))) / 65536
+}
+
+// parseXYZ returns the values of an XYZ type tag.
+func parseXYZ(tag []byte) (result [3]float64, err error) {
+	if len(tag) < 20 || string(tag[0:4]) != "XYZ " {
+		return result, errors.New("icc: missing or invalid colorant tag")
+	}
+
+	for i := 0; i < 3; i++ {
+		result[i] = s15Fixed16(tag[8+i*4:])
+	}
+
+	return result, nil
+}
+
+// parseCurve returns a tone reproduction curve from a curv or para type tag.
+func parseCurve(tag []byte) (func(float64) float64, error) {
+	if len(tag) < 12 {
+		return nil, errors.New("icc: missing or invalid tone curve")
+	}
+
+	switch string(tag[0:4]) {
+	case "curv":
+		n := int(binary.BigEndian.Uint32(tag[8:12]))
+
+		if len(tag) < 12+n*2 {
+			return nil, errors.New("icc: invalid tone curve")
+		}
+
+		switch n {
+		case 0:
+			return func(x float64) float64 { return x }, nil
+		case 1:
+			g := float64(binary.BigEndian.Uint16(tag[12:14])) / 256
+
+			return func(x float64) float64 { return math.Pow(x, g) }, nil
+		default:
+			table := make([]float64, n)
+
+			for i := range table {
+				table[i] = float64(binary.BigEndian.Uint16(tag[12+i*2:])) / 65535
+			}
+
+			return func(x float64) float64 {
+				pos := x * float64(n-1)
+				i := int(pos)
+
+				if i >= n-1 {
+					return table[n-1]
+				}
+
+				return table[i] + (table[i+1]-table[i])*(pos-float64(i))
+			}, nil
+		}
+	case "para":
+		kind := binary.BigEndian.Uint16(tag[8:10])
+		params := []int{1, 3, 4, 5, 7}
+
+		if int(kind) >= len(params) || len(tag) < 12+params[kind]*4 {
+			return nil, errors.New("icc: invalid parametric curve")
+		}
+
+		var v [7]float64
+
+		for i := 0; i < params[kind]; i++ {
+			v[i] = s15Fixed16(tag[12+i*4:])
+		}
+
+		g, a, b, c, d, e, f := v[0], v[1], v[2], v[3], v[4], v[5], v[6]
+
+		switch kind {
+		case 0:
+			return func(x float64) float64 { return math.Pow(x, g) }, nil
+		case 1:
+			return func(x float64) float64 {
+				if x >= -b/a {
+					return math.Pow(a*x+b, g)
+				}
+				return 0
+			}, nil
+		case 2:
+			return func(x float64) float64 {
+				if x >= -b/a {
+					return math.Pow(a*x+b, g) + c
+				}
+				return c
+			}, nil
+		case 3:
+			return func(x float64) float64 {
+				if x >= d {
+					return math.Pow(a*x+b, g)
+				}
+				return c * x
+			}, nil
+		default:
+			return func(x float64) float64 {
+				if x >= d {
+					return math.Pow(a*x+b, g) + e
+				}
+				return c*x + f
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("icc: unsupported tone curve type \"%s\"", string(tag[0:4]))
+}
+
+// parseDescription returns the profile description from a desc (v2) or mluc (v4) type tag.
+func parseDescription(tag []byte) string {
+	if len(tag) < 12 {
+		return ""
+	}
+
+	switch string(tag[0:4]) {
+	case "desc":
+		n := int(binary.BigEndian.Uint32(tag[8:12]))
+
+		if n > 0 && len(tag) >= 12+n {
+			return strings.TrimRight(string(tag[12:12+n]), "\x00 ")
+		}
+	case "mluc":
+		if len(tag) < 28 {
+			return ""
+		}
+
+		length := int(binary.BigEndian.Uint32(tag[20:24]))
+		offset := int(binary.BigEndian.Uint32(tag[24:28]))
+
+		if offset+length > len(tag) {
+			return ""
+		}
+
+		var chars []uint16
+
+		for i := offset; i+1 < offset+length; i += 2 {
+			chars = append(chars, binary.BigEndian.Uint16(tag[i:]))
+		}
+
+		return strings.TrimRight(string(utf16.Decode(chars)), "\x00 ")
+	}
+
+	return ""
+}
+
+// ColorSpace returns the name of a well-known color space with the same primaries, or the profile description.
+func (p *Profile) ColorSpace() string {
+	for name, m := range ColorSpaces {
+		if p.hasPrimaries(m) {
+			return name
+		}
+	}
+
+	if len(p.Description) > 64 {
+		return p.Description[:64]
+	}
+
+	return p.Description
+}
+
+// IsSRGB returns true if the profile has sRGB primaries so that no conversion is needed.
+func (p *Profile) IsSRGB() bool {
+	return p.hasPrimaries(ColorSpaces[ColorSpaceSRGB])
+}
+
+// hasPrimaries returns true if the profile matrix is close to m.
+func (p *Profile) hasPrimaries(m [3][3]float64) bool {
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			if math.Abs(p.Matrix[row][col]-m[row][col]) > 0.01 {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// ToSRGB converts the pixels of an image from the profile color space to sRGB.
+func (p *Profile) ToSRGB(img image.Image) *image.NRGBA {
+	result := imaging.Clone(img)
+
+	// Linearization lookup tables for 8 bit input.
+	var lin [3][256]float64
+
+	for c := 0; c < 3; c++ {
+		for i := 0; i < 256; i++ {
+			lin[c][i] = p.curves[c](float64(i) / 255)
+		}
+	}
+
+	// Combined matrix: profile RGB -> XYZ (D50) -> linear sRGB.
+	var m [3][3]float64
+
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			for k := 0; k < 3; k++ {
+				m[row][col] += xyzToSRGB[row][k] * p.Matrix[k][col]
+			}
+		}
+	}
+
+	// sRGB encoding lookup table.
+	const steps = 4096
+	var enc [steps + 1]uint8
+
+	for i := range enc {
+		v := float64(i) / steps
+
+		if v <= 0.0031308 {
+			v *= 12.92
+		} else {
+			v = 1.055*math.Pow(v, 1/2.4) - 0.055
+		}
+
+		enc[i] = uint8(math.Round(v * 255))
+	}
+
+	for i := 0; i+3 < len(result.Pix); i += 4 {
+		r, g, b := lin[0][result.Pix[i]], lin[1][result.Pix[i+1]], lin[2][result.Pix[i+2]]
+
+		for c := 0; c < 3; c++ {
+			v := m[c][0]*r + m[c][1]*g + m[c][2]*b
+
+			// NaN is not >= 0 and must not be used as lookup table index.
+			if !(v >= 0) {
+				v = 0
+			} else if v > 1 {
+				v = 1
+			}
+
+			result.Pix[i+c] = enc[int(v*steps+0.5)]
+		}
+	}
+
+	return result
+}
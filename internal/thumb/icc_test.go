@@ -0,0 +1,171 @@
+package thumb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+// testProfile returns a minimal ICC profile with the given matrix and a shared gamma curve.
+func testProfile(desc string, m [3][3]float64, gamma float64) []byte {
+	type tag struct {
+		sig  string
+		data []byte
+	}
+
+	fixed := func(v float64) []byte {
+		b := make([]byte, 4)
+		binary.BigEndian.PutUint32(b, uint32(int32(v*65536)))
+		return b
+	}
+
+	xyz := func(col int) []byte {
+		b := append([]byte("XYZ "), 0, 0, 0, 0)
+		for row := 0; row < 3; row++ {
+			b = append(b, fixed(m[row][col])...)
+		}
+		return b
+	}
+
+	curve := append([]byte("curv"), 0, 0, 0, 0, 0, 0, 0, 1, byte(int(gamma*256)>>8), byte(int(gamma*256)), 0, 0)
+	text := append([]byte("desc"), 0, 0, 0, 0, 0, 0, 0, byte(len(desc)+1))
+	text = append(append(text, desc...), 0)
+
+	tags := []tag{{"desc", text}, {"rXYZ", xyz(0)}, {"gXYZ", xyz(1)}, {"bXYZ", xyz(2)}, {"rTRC", curve}, {"gTRC", curve}, {"bTRC", curve}}
+
+	header := make([]byte, 128)
+	copy(header[16:], "RGB ")
+	copy(header[20:], "XYZ ")
+
+	table := make([]byte, 4+len(tags)*12)
+	binary.BigEndian.PutUint32(table, uint32(len(tags)))
+
+	var data []byte
+	offset := len(header) + len(table)
+
+	for i, t := range tags {
+		copy(table[4+i*12:], t.sig)
+		binary.BigEndian.PutUint32(table[8+i*12:], uint32(offset+len(data)))
+		binary.BigEndian.PutUint32(table[12+i*12:], uint32(len(t.data)))
+		data = append(data, t.data...)
+	}
+
+	return append(append(header, table...), data...)
+}
+
+// testJpeg returns a JPEG image with the ICC profile embedded in an APP2 segment.
+func testJpeg(t *testing.T, profile []byte) []byte {
+	var buf bytes.Buffer
+
+	if err := jpeg.Encode(&buf, imaging.New(8, 8, color.NRGBA{128, 128, 128, 255}), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	segment := append(append([]byte{}, iccMarker...), 1, 1)
+	segment = append(segment, profile...)
+	app2 := []byte{0xFF, 0xE2, byte((len(segment) + 2) >> 8), byte(len(segment) + 2)}
+
+	result := append([]byte{}, buf.Bytes()[:2]...)
+	result = append(result, app2...)
+	result = append(result, segment...)
+
+	return append(result, buf.Bytes()[2:]...)
+}
+
+func TestReadICC(t *testing.T) {
+	t.Run("adobe rgb", func(t *testing.T) {
+		profile := testProfile("Adobe RGB (1998)", ColorSpaces[ColorSpaceAdobeRGB], 2.2)
+		data, err := ReadICC(bytes.NewReader(testJpeg(t, profile)))
+
+		assert.Nil(t, err)
+		assert.Equal(t, profile, data)
+	})
+	t.Run("no profile", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		if err := jpeg.Encode(&buf, imaging.New(8, 8, color.White), nil); err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := ReadICC(&buf)
+
+		assert.Nil(t, err)
+		assert.Nil(t, data)
+	})
+	t.Run("not a jpeg", func(t *testing.T) {
+		_, err := ReadICC(bytes.NewReader([]byte("GIF89a")))
+
+		assert.Error(t, err)
+	})
+}
+
+func TestParseProfile(t *testing.T) {
+	t.Run("adobe rgb", func(t *testing.T) {
+		p, err := ParseProfile(testProfile("Adobe RGB (1998)", ColorSpaces[ColorSpaceAdobeRGB], 2.2))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "Adobe RGB (1998)", p.Description)
+		assert.Equal(t, ColorSpaceAdobeRGB, p.ColorSpace())
+		assert.False(t, p.IsSRGB())
+	})
+	t.Run("srgb", func(t *testing.T) {
+		p, err := ParseProfile(testProfile("sRGB IEC61966-2.1", ColorSpaces[ColorSpaceSRGB], 2.2))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, ColorSpaceSRGB, p.ColorSpace())
+		assert.True(t, p.IsSRGB())
+	})
+	t.Run("too short", func(t *testing.T) {
+		_, err := ParseProfile([]byte("foo"))
+
+		assert.Error(t, err)
+	})
+	t.Run("infinite curve", func(t *testing.T) {
+		data := testProfile("Invalid", ColorSpaces[ColorSpaceAdobeRGB], 2.2)
+
+		// Replace the shared gamma curve with a parametric curve x^-1, which is infinite for x = 0.
+		para := append([]byte("para"), 0, 0, 0, 0, 0, 0, 0, 0, 0xFF, 0xFF, 0, 0)
+		i := bytes.Index(data, []byte("curv"))
+		copy(data[i:], para)
+
+		_, err := ParseProfile(data)
+
+		assert.EqualError(t, err, "icc: invalid tag rTRC")
+	})
+}
+
+func TestProfile_ToSRGB(t *testing.T) {
+	p, err := ParseProfile(testProfile("Adobe RGB (1998)", ColorSpaces[ColorSpaceAdobeRGB], 2.2))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, color.NRGBA{128, 128, 128, 255})
+	img.Set(1, 0, color.NRGBA{100, 160, 100, 255})
+
+	result := p.ToSRGB(img)
+	gray := result.NRGBAAt(0, 0)
+	green := result.NRGBAAt(1, 0)
+
+	assert.InDelta(t, 128, int(gray.R), 3)
+	assert.InDelta(t, 128, int(gray.G), 3)
+	assert.InDelta(t, 128, int(gray.B), 3)
+
+	// Adobe RGB has a wider gamut, so colors become more saturated in sRGB.
+	assert.True(t, green.G > 160)
+	assert.True(t, green.R < 100)
+}
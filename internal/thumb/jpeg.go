@@ -7,7 +7,7 @@ import (
 )
 
 func Jpeg(srcFilename, jpgFilename string) (result image.Image, err error) {
-	img, err := imaging.Open(srcFilename, imaging.AutoOrientation(true))
+	img, err := Open(srcFilename)
 
 	if err != nil {
 		log.Errorf("thumbs: can't open %s", srcFilename)
@@ -0,0 +1,31 @@
+package thumb
+
+import (
+	"image"
+
+	"github.com/disintegration/imaging"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// Open loads an image, applies the Exif orientation and converts embedded ICC color profiles to sRGB.
+func Open(fileName string) (image.Image, error) {
+	img, err := imaging.Open(fileName, imaging.AutoOrientation(true))
+
+	if err != nil || fs.GetFileType(fileName) != fs.TypeJpeg {
+		return img, err
+	}
+
+	profile, err := FileProfile(fileName)
+
+	if err != nil {
+		log.Debugf("thumbs: can't read color profile of %s (%s)", fileName, err)
+		return img, nil
+	}
+
+	if profile != nil && !profile.IsSRGB() {
+		log.Debugf("thumbs: converting %s from %s to sRGB", fileName, profile.ColorSpace())
+		return profile.ToSRGB(img), nil
+	}
+
+	return img, nil
+}
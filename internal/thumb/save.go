@@ -0,0 +1,105 @@
+package thumb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/disintegration/imaging"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// Save encodes an image depending on the file extension, WebP and AVIF require an external encoder.
+func Save(img image.Image, fileName string, quality int) error {
+	switch fs.FileType(strings.TrimPrefix(strings.ToLower(filepath.Ext(fileName)), ".")) {
+	case fs.TypePng:
+		return imaging.Save(img, fileName, imaging.PNGCompressionLevel(png.DefaultCompression))
+	case fs.TypeWebP:
+		if WebpBin == "" {
+			return errors.New("thumbs: no webp encoder installed")
+		}
+
+		return encode(img, fileName, func(src, dest string) *exec.Cmd {
+			return exec.Command(WebpBin, "-quiet", "-metadata", "none", "-q", strconv.Itoa(quality), src, "-o", dest)
+		})
+	case fs.TypeAvif:
+		if AvifBin == "" {
+			return errors.New("thumbs: no avif encoder installed")
+		}
+
+		// Quantizer range is 0 (lossless) to 63 (worst).
+		q := strconv.Itoa(63 - quality*63/100)
+
+		return encode(img, fileName, func(src, dest string) *exec.Cmd {
+			return exec.Command(AvifBin, "--speed", "6", "--min", q, "--max", q, src, dest)
+		})
+	default:
+		return imaging.Save(img, fileName, imaging.JPEGQuality(quality))
+	}
+}
+
+// encode saves a lossless temporary copy of the image and passes it to an external encoder.
+// Input and output use unique temporary files, so that concurrent requests for the same thumbnail
+// don't interfere and a failed encoder run doesn't leave a truncated file.
+func encode(img image.Image, fileName string, command func(src, dest string) *exec.Cmd) error {
+	dir, base := filepath.Split(fileName)
+
+	src, err := tempFile(dir, base+".*.png")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(src)
+
+	if err := imaging.Save(img, src, imaging.PNGCompressionLevel(png.NoCompression)); err != nil {
+		return err
+	}
+
+	dest, err := tempFile(dir, base+".*"+filepath.Ext(fileName))
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(dest)
+
+	cmd := command(src, dest)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if stderr.String() != "" {
+			return fmt.Errorf("thumbs: %s", strings.TrimSpace(stderr.String()))
+		}
+
+		return err
+	}
+
+	// Temporary files are only readable by the owner.
+	if err := os.Chmod(dest, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(dest, fileName)
+}
+
+// tempFile creates an empty file with a unique name in dir and returns its name.
+func tempFile(dir, pattern string) (string, error) {
+	f, err := ioutil.TempFile(dir, pattern)
+
+	if err != nil {
+		return "", err
+	}
+
+	return f.Name(), f.Close()
+}
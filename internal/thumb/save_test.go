@@ -0,0 +1,49 @@
+package thumb
+
+import (
+	"image/color"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncode(t *testing.T) {
+	dir, err := ioutil.TempDir("", "thumb")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	img := imaging.New(8, 8, color.White)
+	fileName := filepath.Join(dir, "example.webp")
+
+	t.Run("failed", func(t *testing.T) {
+		err := encode(img, fileName, func(src, dest string) *exec.Cmd {
+			return exec.Command("false")
+		})
+
+		assert.Error(t, err)
+		assert.NoFileExists(t, fileName)
+
+		files, _ := ioutil.ReadDir(dir)
+		assert.Empty(t, files)
+	})
+	t.Run("success", func(t *testing.T) {
+		err := encode(img, fileName, func(src, dest string) *exec.Cmd {
+			return exec.Command("cp", src, dest)
+		})
+
+		assert.NoError(t, err)
+		assert.FileExists(t, fileName)
+
+		files, _ := ioutil.ReadDir(dir)
+		assert.Len(t, files, 1)
+	})
+}
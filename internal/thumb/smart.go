@@ -0,0 +1,243 @@
+package thumb
+
+import (
+	"image"
+	"math"
+
+	"github.com/disintegration/imaging"
+)
+
+var (
+	SmartAnalysisSize = 256 // Saliency maps are computed on a downscaled copy.
+	SmartBlockSize    = 8   // Block size in pixels for local entropy.
+	SmartCenterBias   = 0.1 // Penalty for crop windows far from the image center.
+)
+
+// SaliencyMap contains per-pixel saliency values of a downscaled image.
+type SaliencyMap struct {
+	Width  int
+	Height int
+	Values []float64
+}
+
+// NewSaliencyMap computes an edge energy and local entropy saliency map in pure Go.
+func NewSaliencyMap(img image.Image) SaliencyMap {
+	small := imaging.Fit(img, SmartAnalysisSize, SmartAnalysisSize, imaging.Box)
+	bounds := small.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	lum := make([]float64, w*h)
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := small.PixOffset(x, y)
+			r, g, b := float64(small.Pix[i]), float64(small.Pix[i+1]), float64(small.Pix[i+2])
+			lum[y*w+x] = 0.2126*r + 0.7152*g + 0.0722*b
+		}
+	}
+
+	result := SaliencyMap{Width: w, Height: h, Values: make([]float64, w*h)}
+
+	// Edge energy: gradient magnitude using central differences.
+	var maxEdge float64
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			dx := lum[y*w+clamp(x+1, w)] - lum[y*w+clamp(x-1, w)]
+			dy := lum[clamp(y+1, h)*w+x] - lum[clamp(y-1, h)*w+x]
+			e := math.Abs(dx) + math.Abs(dy)
+			result.Values[y*w+x] = e
+
+			if e > maxEdge {
+				maxEdge = e
+			}
+		}
+	}
+
+	if maxEdge > 0 {
+		for i := range result.Values {
+			result.Values[i] /= maxEdge
+		}
+	}
+
+	// Local entropy: luminance histogram per block, added to every pixel of the block.
+	for by := 0; by < h; by += SmartBlockSize {
+		for bx := 0; bx < w; bx += SmartBlockSize {
+			var hist [16]float64
+			var n float64
+
+			for y := by; y < by+SmartBlockSize && y < h; y++ {
+				for x := bx; x < bx+SmartBlockSize && x < w; x++ {
+					hist[int(lum[y*w+x])>>4]++
+					n++
+				}
+			}
+
+			var entropy float64
+
+			for _, c := range hist {
+				if c > 0 {
+					p := c / n
+					entropy -= p * math.Log2(p)
+				}
+			}
+
+			// Maximum entropy for 16 bins is 4 bits.
+			entropy /= 4
+
+			for y := by; y < by+SmartBlockSize && y < h; y++ {
+				for x := bx; x < bx+SmartBlockSize && x < w; x++ {
+					result.Values[y*w+x] += entropy
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// clamp returns i limited to the range [0, n-1].
+func clamp(i, n int) int {
+	if i < 0 {
+		return 0
+	}
+
+	if i >= n {
+		return n - 1
+	}
+
+	return i
+}
+
+// integral returns the summed-area table of the map with an additional zero row and column.
+func (m SaliencyMap) integral() []float64 {
+	w := m.Width + 1
+	result := make([]float64, w*(m.Height+1))
+
+	for y := 1; y <= m.Height; y++ {
+		var row float64
+
+		for x := 1; x <= m.Width; x++ {
+			row += m.Values[(y-1)*m.Width+(x-1)]
+			result[y*w+x] = result[(y-1)*w+x] + row
+		}
+	}
+
+	return result
+}
+
+// SmartCrop returns the crop rectangle with the highest saliency for the aspect ratio of width and height.
+// TopLeft and BottomRight anchors restrict the search to the first or second half of the free axis.
+func SmartCrop(img image.Image, width, height int, anchor imaging.Anchor) image.Rectangle {
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	if srcW == 0 || srcH == 0 || width <= 0 || height <= 0 {
+		return bounds
+	}
+
+	m := NewSaliencyMap(img)
+	sat := m.integral()
+	stride := m.Width + 1
+	aspect := float64(width) / float64(height)
+
+	// Largest window with the requested aspect ratio in analysis coordinates.
+	cw, ch := m.Width, m.Height
+
+	if float64(m.Width)/float64(m.Height) > aspect {
+		cw = int(math.Round(float64(m.Height) * aspect))
+	} else {
+		ch = int(math.Round(float64(m.Width) / aspect))
+	}
+
+	cw, ch = clamp(cw, m.Width+1), clamp(ch, m.Height+1)
+
+	horizontal := cw < m.Width
+	maxOffset := m.Height - ch
+
+	if horizontal {
+		maxOffset = m.Width - cw
+	}
+
+	from, to := 0, maxOffset
+
+	switch anchor {
+	case imaging.TopLeft:
+		to = maxOffset / 2
+	case imaging.BottomRight:
+		from = maxOffset / 2
+	}
+
+	best, bestScore := (from+to)/2, 0.0
+
+	for offset := from; offset <= to; offset++ {
+		x0, y0 := 0, offset
+
+		if horizontal {
+			x0, y0 = offset, 0
+		}
+
+		x1, y1 := x0+cw, y0+ch
+		score := sat[y1*stride+x1] - sat[y0*stride+x1] - sat[y1*stride+x0] + sat[y0*stride+x0]
+
+		if maxOffset > 0 {
+			dist := math.Abs(float64(offset)-float64(maxOffset)/2) / (float64(maxOffset) / 2)
+			score *= 1 - SmartCenterBias*dist
+		}
+
+		if score > bestScore {
+			best, bestScore = offset, score
+		}
+	}
+
+	// Map the window back to source coordinates.
+	var rect image.Rectangle
+
+	srcHorizontal := float64(srcW)/float64(srcH) > aspect
+	useBest := bestScore > 0 && maxOffset > 0 && horizontal == srcHorizontal
+
+	if srcHorizontal {
+		w := int(math.Round(float64(srcH) * aspect))
+		x := (srcW - w) / 2
+
+		if useBest {
+			x = int(math.Round(float64(best) / float64(m.Width) * float64(srcW)))
+		}
+
+		if x+w > srcW {
+			x = srcW - w
+		}
+
+		if x < 0 {
+			x = 0
+		}
+
+		rect = image.Rect(x, 0, x+w, srcH)
+	} else {
+		h := int(math.Round(float64(srcW) / aspect))
+		y := (srcH - h) / 2
+
+		if useBest {
+			y = int(math.Round(float64(best) / float64(m.Height) * float64(srcH)))
+		}
+
+		if y+h > srcH {
+			y = srcH - h
+		}
+
+		if y < 0 {
+			y = 0
+		}
+
+		rect = image.Rect(0, y, srcW, y+h)
+	}
+
+	return rect.Add(bounds.Min)
+}
+
+// FillSmart crops the most salient region with the aspect ratio of width and height and resizes it.
+func FillSmart(img image.Image, width, height int, anchor imaging.Anchor, filter imaging.ResampleFilter) *image.NRGBA {
+	rect := SmartCrop(img, width, height, anchor)
+
+	return imaging.Resize(imaging.Crop(img, rect), width, height, filter)
+}
@@ -0,0 +1,66 @@
+package thumb
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+// testImage returns a flat gray image with a checkerboard pattern in the given rectangle.
+func testImage(width, height int, pattern image.Rectangle) *image.NRGBA {
+	img := imaging.New(width, height, color.NRGBA{128, 128, 128, 255})
+
+	for y := pattern.Min.Y; y < pattern.Max.Y; y++ {
+		for x := pattern.Min.X; x < pattern.Max.X; x++ {
+			if (x/4+y/4)%2 == 0 {
+				img.Set(x, y, color.NRGBA{255, 255, 255, 255})
+			} else {
+				img.Set(x, y, color.NRGBA{0, 0, 0, 255})
+			}
+		}
+	}
+
+	return img
+}
+
+func TestSmartCrop(t *testing.T) {
+	t.Run("landscape", func(t *testing.T) {
+		img := testImage(900, 300, image.Rect(700, 50, 850, 250))
+		rect := SmartCrop(img, 100, 100, imaging.Center)
+
+		assert.Equal(t, 300, rect.Dx())
+		assert.Equal(t, 300, rect.Dy())
+		assert.True(t, rect.Min.X >= 550, "crop should contain the pattern")
+		assert.True(t, rect.Max.X >= 850, "crop should contain the pattern")
+	})
+	t.Run("portrait", func(t *testing.T) {
+		img := testImage(300, 900, image.Rect(50, 20, 250, 200))
+		rect := SmartCrop(img, 100, 100, imaging.Center)
+
+		assert.Equal(t, 300, rect.Dx())
+		assert.Equal(t, 0, rect.Min.Y)
+	})
+	t.Run("anchor", func(t *testing.T) {
+		img := testImage(900, 300, image.Rect(700, 50, 850, 250))
+		rect := SmartCrop(img, 100, 100, imaging.TopLeft)
+
+		assert.True(t, rect.Max.X <= 600, "crop should be restricted to the left half")
+	})
+	t.Run("flat", func(t *testing.T) {
+		img := testImage(900, 300, image.Rectangle{})
+		rect := SmartCrop(img, 100, 100, imaging.Center)
+
+		assert.Equal(t, image.Rect(300, 0, 600, 300), rect)
+	})
+}
+
+func TestFillSmart(t *testing.T) {
+	img := testImage(900, 300, image.Rect(700, 50, 850, 250))
+	result := FillSmart(img, 224, 224, imaging.Center, imaging.Lanczos)
+
+	assert.Equal(t, 224, result.Bounds().Dx())
+	assert.Equal(t, 224, result.Bounds().Dy())
+}
@@ -8,6 +8,8 @@ var (
 	JpegQuality      = 95
 	JpegQualitySmall = 80
 	Filter           = ResampleLanczos
+	WebpBin          = ""
+	AvifBin          = ""
 )
 
 const (
@@ -43,16 +45,24 @@ const (
 	ResampleNearestNeighbor
 	ResampleDefault
 	ResamplePng
+	ResampleWebp
+	ResampleAvif
+	ResampleFillSmart
+	ResampleFillSmartTopLeft
+	ResampleFillSmartBottomRight
 )
 
 type ResampleOption int
 
 var ResampleMethods = map[ResampleOption]string{
-	ResampleFillCenter:      "center",
-	ResampleFillTopLeft:     "left",
-	ResampleFillBottomRight: "right",
-	ResampleFit:             "fit",
-	ResampleResize:          "resize",
+	ResampleFillCenter:           "center",
+	ResampleFillTopLeft:          "left",
+	ResampleFillBottomRight:      "right",
+	ResampleFit:                  "fit",
+	ResampleResize:               "resize",
+	ResampleFillSmart:            "smart",
+	ResampleFillSmartTopLeft:     "smart_left",
+	ResampleFillSmartBottomRight: "smart_right",
 }
 
 type Type struct {
@@ -64,13 +74,13 @@ type Type struct {
 }
 
 var Types = map[string]Type{
-	"tile_50":   {"tile_500", 50, 50, false, []ResampleOption{ResampleFillCenter, ResampleDefault}},
-	"tile_100":  {"tile_500", 100, 100, false, []ResampleOption{ResampleFillCenter, ResampleDefault}},
-	"tile_224":  {"tile_500", 224, 224, false, []ResampleOption{ResampleFillCenter, ResampleDefault}},
-	"tile_500":  {"", 500, 500, false, []ResampleOption{ResampleFillCenter, ResampleDefault}},
+	"tile_50":   {"tile_500", 50, 50, false, []ResampleOption{ResampleFillSmart, ResampleDefault}},
+	"tile_100":  {"tile_500", 100, 100, false, []ResampleOption{ResampleFillSmart, ResampleDefault}},
+	"tile_224":  {"tile_500", 224, 224, false, []ResampleOption{ResampleFillSmart, ResampleDefault}},
+	"tile_500":  {"", 500, 500, false, []ResampleOption{ResampleFillSmart, ResampleDefault}},
 	"colors":    {"fit_720", 3, 3, false, []ResampleOption{ResampleResize, ResampleNearestNeighbor, ResamplePng}},
-	"left_224":  {"fit_720", 224, 224, false, []ResampleOption{ResampleFillTopLeft, ResampleDefault}},
-	"right_224": {"fit_720", 224, 224, false, []ResampleOption{ResampleFillBottomRight, ResampleDefault}},
+	"left_224":  {"fit_720", 224, 224, false, []ResampleOption{ResampleFillSmartTopLeft, ResampleDefault}},
+	"right_224": {"fit_720", 224, 224, false, []ResampleOption{ResampleFillSmartBottomRight, ResampleDefault}},
 	"fit_720":   {"", 720, 720, true, []ResampleOption{ResampleFit, ResampleDefault}},
 	"fit_1280":  {"fit_2048", 1280, 1024, true, []ResampleOption{ResampleFit, ResampleDefault}},
 	"fit_1920":  {"fit_2048", 1920, 1200, true, []ResampleOption{ResampleFit, ResampleDefault}},
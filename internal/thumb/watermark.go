@@ -0,0 +1,233 @@
+package thumb
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/disintegration/imaging"
+	"github.com/photoprism/photoprism/pkg/fs"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+var (
+	WatermarkText     = ""
+	WatermarkImage    = ""
+	WatermarkPosition = "bottom-right"
+	WatermarkOpacity  = 0.5
+	WatermarkMinSize  = 720
+)
+
+// WatermarkAnchors maps watermark position names to image anchors.
+var WatermarkAnchors = map[string]imaging.Anchor{
+	"top-left":     imaging.TopLeft,
+	"top-right":    imaging.TopRight,
+	"bottom-left":  imaging.BottomLeft,
+	"bottom-right": imaging.BottomRight,
+	"center":       imaging.Center,
+}
+
+// WatermarkEnabled returns true if a watermark text or image is configured.
+func WatermarkEnabled() bool {
+	return WatermarkText != "" || WatermarkImage != ""
+}
+
+// WatermarkKey returns a short hash of the watermark settings, so that cached files are invalidated when they change.
+func WatermarkKey() string {
+	settings := fmt.Sprintf("%s|%s|%s|%.2f|%d", WatermarkText, WatermarkImage, WatermarkPosition, WatermarkOpacity, WatermarkMinSize)
+
+	if info, err := os.Stat(WatermarkImage); err == nil {
+		settings += fmt.Sprintf("|%d", info.ModTime().Unix())
+	}
+
+	hash := sha1.Sum([]byte(settings))
+
+	return hex.EncodeToString(hash[:])[:8]
+}
+
+// WatermarkPath returns the cache file name of the watermarked copy of an image.
+func WatermarkPath(fileName, fileHash, thumbPath string) string {
+	var rel string
+
+	if strings.HasPrefix(fileName, thumbPath+"/") {
+		rel = strings.TrimPrefix(fileName, thumbPath+"/")
+	} else if len(fileHash) >= 3 {
+		rel = path.Join(fileHash[0:1], fileHash[1:2], fileHash[2:3], fileHash+"_"+filepath.Base(fileName))
+	} else {
+		rel = filepath.Base(fileName)
+	}
+
+	switch fs.FileType(strings.TrimPrefix(strings.ToLower(filepath.Ext(rel)), ".")) {
+	case fs.TypeJpeg, fs.TypePng, fs.TypeWebP, fs.TypeAvif:
+	default:
+		rel = strings.TrimSuffix(rel, filepath.Ext(rel)) + "." + string(fs.TypeJpeg)
+	}
+
+	return path.Join(thumbPath, "watermarks", WatermarkKey(), rel)
+}
+
+// Watermarked returns the file name of a watermarked copy of an image, which is created if it doesn't exist yet.
+// Images smaller than the minimum size and files that can't be decoded, like videos, RAW images
+// and sidecar files, are returned as they are. WebP and AVIF images can't be decoded either, so they are
+// rejected instead of being sent without watermark.
+func Watermarked(fileName, fileHash, thumbPath string) (string, error) {
+	if !WatermarkEnabled() {
+		return fileName, nil
+	}
+
+	watermarkName := WatermarkPath(fileName, fileHash, thumbPath)
+
+	if fs.FileExists(watermarkName) {
+		return watermarkName, nil
+	}
+
+	if width, height, err := imageSize(fileName); err == image.ErrFormat && undecodable(fileName) {
+		return "", fmt.Errorf("thumbs: can't watermark %s, unsupported image format", filepath.Base(fileName))
+	} else if err == image.ErrFormat {
+		log.Debugf("thumbs: can't watermark %s, unsupported format", filepath.Base(fileName))
+		return fileName, nil
+	} else if err != nil {
+		return "", fmt.Errorf("thumbs: can't read %s (%s)", filepath.Base(fileName), err)
+	} else if width < WatermarkMinSize && height < WatermarkMinSize {
+		return fileName, nil
+	}
+
+	img, err := Open(fileName)
+
+	if err != nil {
+		return "", fmt.Errorf("thumbs: can't open %s (%s)", filepath.Base(fileName), err)
+	}
+
+	result, err := Watermark(img)
+
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(watermarkName), os.ModePerm); err != nil {
+		return "", err
+	}
+
+	if err := Save(result, watermarkName, JpegQuality); err != nil {
+		return "", err
+	}
+
+	return watermarkName, nil
+}
+
+// Watermark returns a copy of the image with the configured watermark applied.
+func Watermark(img image.Image) (*image.NRGBA, error) {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	var mark image.Image
+
+	if WatermarkImage != "" {
+		overlay, err := imaging.Open(WatermarkImage)
+
+		if err != nil {
+			return nil, fmt.Errorf("thumbs: can't open watermark %s (%s)", WatermarkImage, err)
+		}
+
+		mark = imaging.Resize(overlay, width/5, 0, imaging.Lanczos)
+	} else {
+		text := watermarkText(WatermarkText)
+		lineHeight := height / 25
+
+		if lineHeight < text.Bounds().Dy() {
+			lineHeight = text.Bounds().Dy()
+		}
+
+		mark = imaging.Resize(text, 0, lineHeight, imaging.Linear)
+	}
+
+	margin := width
+
+	if height < margin {
+		margin = height
+	}
+
+	margin /= 40
+
+	pt := watermarkPoint(image.Rect(0, 0, width, height), mark.Bounds(), margin)
+
+	return imaging.Overlay(img, mark, pt, WatermarkOpacity), nil
+}
+
+// watermarkText renders the text with a shadow so that it remains readable on bright and dark backgrounds.
+func watermarkText(text string) *image.NRGBA {
+	face := basicfont.Face7x13
+	textWidth := font.MeasureString(face, text).Ceil()
+	result := image.NewNRGBA(image.Rect(0, 0, textWidth+2, face.Height+2))
+
+	d := &font.Drawer{Dst: result, Face: face}
+
+	for _, c := range []struct {
+		color  color.Color
+		offset int
+	}{{color.Black, 1}, {color.White, 0}} {
+		d.Src = image.NewUniform(c.color)
+		d.Dot = fixed.P(c.offset, face.Ascent+c.offset)
+		d.DrawString(text)
+	}
+
+	return result
+}
+
+// watermarkPoint returns the top left position of the watermark depending on the configured anchor.
+func watermarkPoint(dst, mark image.Rectangle, margin int) image.Point {
+	anchor, ok := WatermarkAnchors[WatermarkPosition]
+
+	if !ok {
+		anchor = imaging.BottomRight
+	}
+
+	left, top := margin, margin
+	right, bottom := dst.Dx()-mark.Dx()-margin, dst.Dy()-mark.Dy()-margin
+
+	switch anchor {
+	case imaging.TopLeft:
+		return image.Pt(left, top)
+	case imaging.TopRight:
+		return image.Pt(right, top)
+	case imaging.BottomLeft:
+		return image.Pt(left, bottom)
+	case imaging.Center:
+		return image.Pt((dst.Dx()-mark.Dx())/2, (dst.Dy()-mark.Dy())/2)
+	default:
+		return image.Pt(right, bottom)
+	}
+}
+
+// undecodable returns true if the file is an image in a format that can be encoded, but not decoded.
+func undecodable(fileName string) bool {
+	switch fs.FileType(strings.TrimPrefix(strings.ToLower(filepath.Ext(fileName)), ".")) {
+	case fs.TypeWebP, fs.TypeAvif:
+		return true
+	default:
+		return false
+	}
+}
+
+// imageSize returns the image dimensions without decoding the whole file.
+func imageSize(fileName string) (width, height int, err error) {
+	file, err := os.Open(fileName)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	defer file.Close()
+
+	cfg, _, err := image.DecodeConfig(file)
+
+	return cfg.Width, cfg.Height, err
+}
@@ -0,0 +1,144 @@
+package thumb
+
+import (
+	"image"
+	"image/color"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatermark(t *testing.T) {
+	WatermarkText = "PhotoPrism"
+
+	defer func() {
+		WatermarkText = ""
+		WatermarkPosition = "bottom-right"
+	}()
+
+	img := imaging.New(1000, 800, color.NRGBA{0, 0, 255, 255})
+
+	t.Run("bottom-right", func(t *testing.T) {
+		WatermarkPosition = "bottom-right"
+
+		result, err := Watermark(img)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, img.Bounds(), result.Bounds())
+		assert.Equal(t, color.NRGBA{0, 0, 255, 255}, result.NRGBAAt(10, 10))
+		assert.True(t, changed(result, image.Rect(500, 700, 1000, 800)))
+	})
+	t.Run("top-left", func(t *testing.T) {
+		WatermarkPosition = "top-left"
+
+		result, err := Watermark(img)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.True(t, changed(result, image.Rect(0, 0, 500, 100)))
+		assert.False(t, changed(result, image.Rect(500, 700, 1000, 800)))
+	})
+}
+
+// changed returns true if any pixel in the rectangle differs from the blue background.
+func changed(img *image.NRGBA, r image.Rectangle) bool {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if img.NRGBAAt(x, y) != (color.NRGBA{0, 0, 255, 255}) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func TestWatermarkPath(t *testing.T) {
+	WatermarkText = "PhotoPrism"
+	defer func() { WatermarkText = "" }()
+
+	key := WatermarkKey()
+
+	t.Run("thumbnail", func(t *testing.T) {
+		result := WatermarkPath("/cache/thumbnails/a/b/c/abc_720x720_fit.jpg", "abc", "/cache/thumbnails")
+		assert.Equal(t, "/cache/thumbnails/watermarks/"+key+"/a/b/c/abc_720x720_fit.jpg", result)
+	})
+	t.Run("original", func(t *testing.T) {
+		result := WatermarkPath("/photos/originals/2020/cat.tiff", "abc", "/cache/thumbnails")
+		assert.Equal(t, "/cache/thumbnails/watermarks/"+key+"/a/b/c/abc_cat.jpg", result)
+	})
+	t.Run("settings changed", func(t *testing.T) {
+		WatermarkText = "Other"
+		assert.NotEqual(t, key, WatermarkKey())
+	})
+}
+
+func TestWatermarked(t *testing.T) {
+	thumbPath, err := ioutil.TempDir("", "watermark")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(thumbPath)
+
+	small := filepath.Join(thumbPath, "small.jpg")
+	large := filepath.Join(thumbPath, "large.jpg")
+
+	assert.Nil(t, imaging.Save(imaging.New(200, 100, color.White), small))
+	assert.Nil(t, imaging.Save(imaging.New(800, 600, color.White), large))
+
+	t.Run("disabled", func(t *testing.T) {
+		result, err := Watermarked(large, "abc", thumbPath)
+
+		assert.Nil(t, err)
+		assert.Equal(t, large, result)
+	})
+
+	WatermarkText = "PhotoPrism"
+	defer func() { WatermarkText = "" }()
+
+	t.Run("too small", func(t *testing.T) {
+		result, err := Watermarked(small, "abc", thumbPath)
+
+		assert.Nil(t, err)
+		assert.Equal(t, small, result)
+	})
+	t.Run("large", func(t *testing.T) {
+		result, err := Watermarked(large, "abc", thumbPath)
+
+		assert.Nil(t, err)
+		assert.True(t, strings.Contains(result, "/watermarks/"))
+		assert.FileExists(t, result)
+	})
+	t.Run("video", func(t *testing.T) {
+		video := filepath.Join(thumbPath, "video.mp4")
+
+		assert.Nil(t, ioutil.WriteFile(video, []byte("not an image"), os.ModePerm))
+
+		result, err := Watermarked(video, "abc", thumbPath)
+
+		assert.Nil(t, err)
+		assert.Equal(t, video, result)
+	})
+	t.Run("webp", func(t *testing.T) {
+		webp := filepath.Join(thumbPath, "large.webp")
+
+		assert.Nil(t, ioutil.WriteFile(webp, []byte("RIFF\x00\x00\x00\x00WEBPVP8 "), os.ModePerm))
+
+		result, err := Watermarked(webp, "abc", thumbPath)
+
+		assert.Error(t, err)
+		assert.Equal(t, "", result)
+	})
+}
@@ -0,0 +1,51 @@
+package workers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/mutex"
+	"github.com/photoprism/photoprism/internal/photoprism"
+)
+
+// eventsDate is the last day on which events were detected.
+var eventsDate string
+
+// Events represents a worker that groups photos into events and trips.
+type Events struct {
+	conf *config.Config
+}
+
+// NewEvents returns a new event detection worker.
+func NewEvents(conf *config.Config) *Events {
+	return &Events{conf: conf}
+}
+
+// Start detects events once per day and publishes an "events.updated" event when done.
+func (e *Events) Start() (err error) {
+	today := time.Now().Format("2006-01-02")
+
+	if err := mutex.Events.Start(); err != nil {
+		event.Error(fmt.Sprintf("events: %s", err.Error()))
+		return err
+	}
+
+	defer mutex.Events.Stop()
+
+	// Checked while running, so that the date isn't accessed concurrently.
+	if eventsDate == today {
+		return nil
+	}
+
+	if err := photoprism.NewEvents(e.conf).Start(); err != nil {
+		return err
+	}
+
+	eventsDate = today
+
+	event.Publish("events.updated", event.Data{})
+
+	return nil
+}
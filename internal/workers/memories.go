@@ -0,0 +1,67 @@
+package workers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/mutex"
+	"github.com/photoprism/photoprism/internal/query"
+)
+
+// memoriesDate is the last day for which memories were published.
+var memoriesDate string
+
+// Memories represents a worker that notifies clients about photos taken on the same day in previous years.
+type Memories struct {
+	conf *config.Config
+}
+
+// NewMemories returns a new memories worker.
+func NewMemories(conf *config.Config) *Memories {
+	return &Memories{conf: conf}
+}
+
+// Start publishes a "memories.today" event once per day if photos were taken on this day in previous years.
+func (m *Memories) Start() (err error) {
+	today := time.Now().Format("2006-01-02")
+
+	if err := mutex.Memories.Start(); err != nil {
+		event.Error(fmt.Sprintf("memories: %s", err.Error()))
+		return err
+	}
+
+	defer mutex.Memories.Stop()
+
+	// Checked while running, so that the date isn't accessed concurrently.
+	if memoriesDate == today {
+		return nil
+	}
+
+	result, err := query.New(m.conf.Db()).Memories(form.Memories{Count: 1})
+
+	if err != nil {
+		return err
+	}
+
+	memoriesDate = today
+
+	if count := result.Count(); count > 0 {
+		var years []int
+
+		for _, memory := range result.Day {
+			years = append(years, memory.YearsAgo)
+		}
+
+		log.Infof("memories: %d photos taken on this day in previous years", count)
+
+		event.Publish("memories.today", event.Data{
+			"date":  result.Date,
+			"years": years,
+		})
+	}
+
+	return nil
+}
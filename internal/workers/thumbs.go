@@ -0,0 +1,48 @@
+package workers
+
+import (
+	"fmt"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/mutex"
+	"github.com/photoprism/photoprism/internal/thumb"
+)
+
+// Thumbs represents a thumbnail cache worker.
+type Thumbs struct {
+	conf *config.Config
+}
+
+// NewThumbs returns a new thumbnail cache worker.
+func NewThumbs(conf *config.Config) *Thumbs {
+	return &Thumbs{conf: conf}
+}
+
+// Start evicts least recently used thumbnails if the cache exceeds its quota.
+func (t *Thumbs) Start() (err error) {
+	quota := t.conf.ThumbQuota()
+
+	if quota == 0 {
+		return nil
+	}
+
+	if err := mutex.Thumbs.Start(); err != nil {
+		event.Error(fmt.Sprintf("thumbs: %s", err.Error()))
+		return err
+	}
+
+	defer mutex.Thumbs.Stop()
+
+	removed, freed, err := thumb.Evict(t.conf.ThumbnailsPath(), quota)
+
+	if err != nil {
+		return err
+	}
+
+	if removed > 0 {
+		log.Infof("thumbs: evicted %d files, %d bytes freed", removed, freed)
+	}
+
+	return nil
+}
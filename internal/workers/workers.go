@@ -23,10 +23,16 @@ func Start(conf *config.Config) {
 				ticker.Stop()
 				mutex.Share.Cancel()
 				mutex.Sync.Cancel()
+				mutex.Thumbs.Cancel()
+				mutex.Memories.Cancel()
+				mutex.Events.Cancel()
 				return
 			case <-ticker.C:
 				StartShare(conf)
 				StartSync(conf)
+				StartThumbs(conf)
+				StartMemories(conf)
+				StartEvents(conf)
 			}
 		}
 	}()
@@ -60,3 +66,39 @@ func StartSync(conf *config.Config) {
 		}()
 	}
 }
+
+// StartThumbs runs the thumbnail cache worker once.
+func StartThumbs(conf *config.Config) {
+	if !mutex.Thumbs.Busy() {
+		go func() {
+			t := NewThumbs(conf)
+			if err := t.Start(); err != nil {
+				log.Error(err)
+			}
+		}()
+	}
+}
+
+// StartMemories runs the memories worker once.
+func StartMemories(conf *config.Config) {
+	if !mutex.Memories.Busy() {
+		go func() {
+			m := NewMemories(conf)
+			if err := m.Start(); err != nil {
+				log.Error(err)
+			}
+		}()
+	}
+}
+
+// StartEvents runs the event detection worker once.
+func StartEvents(conf *config.Config) {
+	if !mutex.Events.Busy() {
+		go func() {
+			e := NewEvents(conf)
+			if err := e.Start(); err != nil {
+				log.Error(err)
+			}
+		}()
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Package blurhash encodes images as compact placeholder strings.
+
+Additional information can be found in our Developer Guide:
+
+https://github.com/photoprism/photoprism/wiki
+
+...and in the BlurHash specification:
+
+https://github.com/woltapp/blurhash/blob/master/Algorithm.md
+
+*/
+package blurhash
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strings"
+)
+
+const characters = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// Encode returns the BlurHash of an image using the given number of components (1-9) per axis.
+func Encode(img image.Image, xComponents, yComponents int) (string, error) {
+	if xComponents < 1 || xComponents > 9 || yComponents < 1 || yComponents > 9 {
+		return "", fmt.Errorf("blurhash: invalid number of components (%dx%d)", xComponents, yComponents)
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if width == 0 || height == 0 {
+		return "", fmt.Errorf("blurhash: image is empty")
+	}
+
+	// Convert pixels to linear RGB once.
+	pixels := make([][3]float64, width*height)
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			r, g, b, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			pixels[y*width+x] = [3]float64{linear(r >> 8), linear(g >> 8), linear(b >> 8)}
+		}
+	}
+
+	factors := make([][3]float64, 0, xComponents*yComponents)
+
+	for j := 0; j < yComponents; j++ {
+		for i := 0; i < xComponents; i++ {
+			factors = append(factors, factor(pixels, width, height, i, j))
+		}
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(encode83((xComponents-1)+(yComponents-1)*9, 1))
+
+	maxValue := 1.0
+
+	if len(factors) > 1 {
+		var actualMax float64
+
+		for _, f := range factors[1:] {
+			for _, v := range f {
+				actualMax = math.Max(actualMax, math.Abs(v))
+			}
+		}
+
+		quantisedMax := int(math.Max(0, math.Min(82, math.Floor(actualMax*166-0.5))))
+		maxValue = float64(quantisedMax+1) / 166
+		sb.WriteString(encode83(quantisedMax, 1))
+	} else {
+		sb.WriteString(encode83(0, 1))
+	}
+
+	sb.WriteString(encode83(encodeDC(factors[0]), 4))
+
+	for _, f := range factors[1:] {
+		sb.WriteString(encode83(encodeAC(f, maxValue), 2))
+	}
+
+	return sb.String(), nil
+}
+
+// factor returns the normalized cosine transform component i, j.
+func factor(pixels [][3]float64, width, height, i, j int) (result [3]float64) {
+	cosX := make([]float64, width)
+
+	for x := range cosX {
+		cosX[x] = math.Cos(math.Pi * float64(i) * float64(x) / float64(width))
+	}
+
+	for y := 0; y < height; y++ {
+		cosY := math.Cos(math.Pi * float64(j) * float64(y) / float64(height))
+
+		for x := 0; x < width; x++ {
+			basis := cosX[x] * cosY
+			p := pixels[y*width+x]
+			result[0] += basis * p[0]
+			result[1] += basis * p[1]
+			result[2] += basis * p[2]
+		}
+	}
+
+	normalisation := 2.0
+
+	if i == 0 && j == 0 {
+		normalisation = 1
+	}
+
+	scale := normalisation / float64(width*height)
+
+	for c := range result {
+		result[c] *= scale
+	}
+
+	return result
+}
+
+// linear converts an 8 bit sRGB value to linear RGB.
+func linear(value uint32) float64 {
+	v := float64(value) / 255
+
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// srgb converts a linear RGB value to 8 bit sRGB.
+func srgb(value float64) int {
+	v := math.Max(0, math.Min(1, value))
+
+	if v <= 0.0031308 {
+		return int(v*12.92*255 + 0.5)
+	}
+
+	return int((1.055*math.Pow(v, 1/2.4)-0.055)*255 + 0.5)
+}
+
+func encodeDC(f [3]float64) int {
+	return srgb(f[0])<<16 + srgb(f[1])<<8 + srgb(f[2])
+}
+
+func encodeAC(f [3]float64, maxValue float64) int {
+	quant := func(v float64) int {
+		return int(math.Max(0, math.Min(18, math.Floor(signPow(v/maxValue, 0.5)*9+9.5))))
+	}
+
+	return quant(f[0])*19*19 + quant(f[1])*19 + quant(f[2])
+}
+
+func signPow(v, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(v), exp), v)
+}
+
+// encode83 encodes a value as base 83 string of the given length.
+func encode83(value, length int) string {
+	result := make([]byte, length)
+
+	for i := length - 1; i >= 0; i-- {
+		result[i] = characters[value%83]
+		value /= 83
+	}
+
+	return string(result)
+}
@@ -0,0 +1,62 @@
+package blurhash
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncode(t *testing.T) {
+	t.Run("white", func(t *testing.T) {
+		img := &image.NRGBA{Pix: fill(4, 4, 255), Stride: 16, Rect: image.Rect(0, 0, 4, 4)}
+		result, err := Encode(img, 4, 3)
+
+		assert.Nil(t, err)
+		assert.Len(t, result, 28)
+		assert.Equal(t, "L", result[:1])
+		assert.Equal(t, "TSUA", result[2:6])
+	})
+	t.Run("gradient", func(t *testing.T) {
+		img := image.NewGray(image.Rect(0, 0, 32, 32))
+
+		for x := 0; x < 32; x++ {
+			for y := 0; y < 32; y++ {
+				img.SetGray(x, y, color.Gray{Y: uint8(x * 8)})
+			}
+		}
+
+		result, err := Encode(img, 4, 4)
+
+		assert.Nil(t, err)
+		assert.Len(t, result, 36)
+		assert.NotEqual(t, "0", result[1:2])
+	})
+	t.Run("invalid components", func(t *testing.T) {
+		_, err := Encode(image.NewGray(image.Rect(0, 0, 4, 4)), 0, 10)
+
+		assert.Error(t, err)
+	})
+	t.Run("empty", func(t *testing.T) {
+		_, err := Encode(image.NewGray(image.Rect(0, 0, 0, 0)), 4, 3)
+
+		assert.Error(t, err)
+	})
+}
+
+func fill(width, height int, value uint8) []byte {
+	result := make([]byte, width*height*4)
+
+	for i := range result {
+		result[i] = value
+	}
+
+	return result
+}
+
+func TestEncode83(t *testing.T) {
+	assert.Equal(t, "L", encode83(21, 1))
+	assert.Equal(t, "fQ", encode83(3429, 2))
+	assert.Equal(t, "TSUA", encode83(16777215, 4))
+}
@@ -0,0 +1,153 @@
+package colors
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// MaxColorDistance is the distance of unknown colors, which is larger than the distance of any two known colors.
+const MaxColorDistance = 200
+
+// LuminanceWeight is the weight of luminance differences compared to color differences.
+const LuminanceWeight = 3
+
+// Palette contains the 3x3 color and luminance maps of an image, luminance is optional.
+type Palette struct {
+	Colors    Colors
+	Luminance LightMap
+}
+
+// ParsePalette parses a palette from hex encoded colors and luminance separated by a colon like "6B3300FFF:88FF00FF0",
+// or from a comma separated list of RGB colors like "#2196F3,#F5F5F5".
+func ParsePalette(s string) (p Palette, err error) {
+	s = strings.TrimSpace(s)
+
+	if strings.HasPrefix(s, "#") {
+		return parseRGB(s)
+	}
+
+	parts := strings.SplitN(s, ":", 2)
+
+	if p.Colors, err = parseColors(parts[0]); err != nil {
+		return p, err
+	}
+
+	if len(parts) == 2 {
+		if p.Luminance, err = parseLightMap(parts[1]); err != nil {
+			return p, err
+		}
+	}
+
+	return p, nil
+}
+
+// NewPalette returns the palette of a file based on its hex encoded colors and luminance.
+func NewPalette(colorsHex, luminanceHex string) (p Palette, err error) {
+	if luminanceHex == "" {
+		return ParsePalette(colorsHex)
+	}
+
+	return ParsePalette(colorsHex + ":" + luminanceHex)
+}
+
+// parseColors parses 9 hex encoded color indexes.
+func parseColors(s string) (result Colors, err error) {
+	values, err := parseHexMap(s)
+
+	for _, v := range values {
+		result = append(result, Color(v))
+	}
+
+	return result, err
+}
+
+// parseLightMap parses 9 hex encoded luminance values.
+func parseLightMap(s string) (result LightMap, err error) {
+	values, err := parseHexMap(s)
+
+	for _, v := range values {
+		result = append(result, Luminance(v))
+	}
+
+	return result, err
+}
+
+func parseHexMap(s string) (result []uint8, err error) {
+	if len(s) != 9 {
+		return result, fmt.Errorf("palette must contain 9 hex digits")
+	}
+
+	for _, c := range s {
+		v, err := strconv.ParseUint(string(c), 16, 8)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid palette %s", s)
+		}
+
+		result = append(result, uint8(v))
+	}
+
+	return result, nil
+}
+
+// parseRGB maps a list of RGB colors to the 3x3 color map, colors are repeated if there are less than 9.
+func parseRGB(s string) (p Palette, err error) {
+	var list Colors
+
+	for _, hex := range strings.Split(s, ",") {
+		c, err := colorful.Hex(strings.TrimSpace(hex))
+
+		if err != nil {
+			return p, fmt.Errorf("invalid color %s", hex)
+		}
+
+		list = append(list, Colorful(c))
+	}
+
+	for i := 0; i < 9; i++ {
+		p.Colors = append(p.Colors, list[i%len(list)])
+	}
+
+	return p, nil
+}
+
+// Distance returns the perceptual distance between two colors from 0 to about 150.
+func (c Color) Distance(other Color) int {
+	if c == other {
+		return 0
+	}
+
+	a, errA := colorful.Hex(ColorExamples[c])
+	b, errB := colorful.Hex(ColorExamples[other])
+
+	if errA != nil || errB != nil {
+		return MaxColorDistance
+	}
+
+	return int(math.Round(a.DistanceLab(b) * 100))
+}
+
+// Distance returns the sum of color and weighted luminance distances of all cells, lower values are more similar.
+func (p Palette) Distance(other Palette) (result int) {
+	for i, c := range p.Colors {
+		if i < len(other.Colors) {
+			result += c.Distance(other.Colors[i])
+		} else {
+			result += MaxColorDistance
+		}
+	}
+
+	if len(p.Luminance) != len(other.Luminance) {
+		return result
+	}
+
+	for i, l := range p.Luminance {
+		result += LuminanceWeight * int(math.Abs(float64(l)-float64(other.Luminance[i])))
+	}
+
+	return result
+}
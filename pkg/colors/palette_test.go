@@ -0,0 +1,50 @@
+package colors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePalette(t *testing.T) {
+	t.Run("colors and luminance", func(t *testing.T) {
+		p, err := ParsePalette("6B3300FFF:88FF00FF0")
+
+		assert.Nil(t, err)
+		assert.Equal(t, Colors{Blue, Yellow, White, White, Black, Black, Pink, Pink, Pink}, p.Colors)
+		assert.Equal(t, LightMap{8, 8, 15, 15, 0, 0, 15, 15, 0}, p.Luminance)
+	})
+	t.Run("colors only", func(t *testing.T) {
+		p, err := ParsePalette("666666666")
+
+		assert.Nil(t, err)
+		assert.Len(t, p.Colors, 9)
+		assert.Empty(t, p.Luminance)
+	})
+	t.Run("rgb", func(t *testing.T) {
+		p, err := ParsePalette("#2196F3, #F5F5F5")
+
+		assert.Nil(t, err)
+		assert.Equal(t, Colors{Blue, White, Blue, White, Blue, White, Blue, White, Blue}, p.Colors)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ParsePalette("6B33")
+		assert.Error(t, err)
+
+		_, err = ParsePalette("6B3300FFX")
+		assert.Error(t, err)
+
+		_, err = ParsePalette("#XYZ")
+		assert.Error(t, err)
+	})
+}
+
+func TestPalette_Distance(t *testing.T) {
+	blue, _ := ParsePalette("666666666:888888888")
+	teal, _ := ParsePalette("888888888:888888888")
+	white, _ := ParsePalette("333333333:FFFFFFFFF")
+
+	assert.Equal(t, 0, blue.Distance(blue))
+	assert.Less(t, blue.Distance(teal), blue.Distance(white))
+	assert.Equal(t, blue.Distance(white), white.Distance(blue))
+}
@@ -18,6 +18,8 @@ const (
 	TypeBitmap   FileType = "bmp"  // BMP image file.
 	TypeRaw      FileType = "raw"  // RAW image file.
 	TypeHEIF     FileType = "heif" // High Efficiency Image File Format
+	TypeWebP     FileType = "webp" // Google WebP image file.
+	TypeAvif     FileType = "avif" // AV1 Image File Format
 	TypeMov      FileType = "mov"  // Video files.
 	TypeMP4      FileType = "mp4"
 	TypeAvi      FileType = "avi"
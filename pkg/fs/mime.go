@@ -7,6 +7,9 @@ import (
 
 const (
 	MimeTypeJpeg = "image/jpeg"
+	MimeTypePng  = "image/png"
+	MimeTypeWebP = "image/webp"
+	MimeTypeAvif = "image/avif"
 )
 
 // MimeType returns the mime type of a file, empty string if unknown.
@@ -78,3 +78,21 @@ func Range(token string, levelUp int) (min, max string) {
 
 	return parent.Prev().ChildBeginAtLevel(lvl).ToToken(), parent.Next().ChildBeginAtLevel(lvl).ToToken()
 }
+
+// Parent returns the token of the parent cell at the given level, or an empty string if the token is invalid.
+func Parent(token string, level int) string {
+	c := gs2.CellIDFromToken(token)
+
+	if !c.IsValid() || level < 0 || level > c.Level() {
+		return ""
+	}
+
+	return c.Parent(level).ToToken()
+}
+
+// PrefixLength returns the number of token characters that are the same for all cells within a cell
+// at the given level, so that tokens can be grouped by prefix.
+func PrefixLength(level int) int {
+	// Tokens are hex encoded, cell ids start with 3 face bits followed by 2 bits per level.
+	return (3 + 2*level + 3) / 4
+}
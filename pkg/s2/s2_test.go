@@ -152,3 +152,23 @@ func TestRange(t *testing.T) {
 		assert.Equal(t, "", max)
 	})
 }
+
+func TestParent(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		token := TokenLevel(52.5, 13.4, 21)
+		parent := Parent(token, 10)
+
+		assert.Equal(t, TokenLevel(52.5, 13.4, 10), parent)
+		assert.Equal(t, parent[:PrefixLength(10)], token[:PrefixLength(10)])
+	})
+	t.Run("invalid", func(t *testing.T) {
+		assert.Equal(t, "", Parent("4799e370ca5q", 10))
+		assert.Equal(t, "", Parent(TokenLevel(52.5, 13.4, 10), 12))
+	})
+}
+
+func TestPrefixLength(t *testing.T) {
+	assert.Equal(t, 1, PrefixLength(0))
+	assert.Equal(t, 6, PrefixLength(10))
+	assert.Equal(t, 12, PrefixLength(21))
+}
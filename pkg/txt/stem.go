@@ -0,0 +1,31 @@
+package txt
+
+import (
+	"strings"
+)
+
+// StemSuffixes contains common inflection suffixes of the languages covered by the stopword list,
+// longer suffixes must come first.
+var StemSuffixes = []string{
+	"ations", "ungen", "ation", "mente", "ement", "ments", "ings", "ment",
+	"heit", "keit", "ing", "ern", "ung", "ies", "es", "ed", "en", "er", "ly", "e", "s",
+}
+
+// Stem returns the lowercase stem of a word by removing common suffixes, the stem has at least 3 characters.
+func Stem(word string) string {
+	w := strings.ToLower(word)
+
+	for _, suffix := range StemSuffixes {
+		if !strings.HasSuffix(w, suffix) || len([]rune(w))-len([]rune(suffix)) < 3 {
+			continue
+		}
+
+		if suffix == "ies" {
+			return strings.TrimSuffix(w, suffix) + "y"
+		}
+
+		return strings.TrimSuffix(w, suffix)
+	}
+
+	return w
+}
@@ -0,0 +1,24 @@
+package txt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStem(t *testing.T) {
+	t.Run("plural", func(t *testing.T) {
+		assert.Equal(t, "bicycl", Stem("Bicycles"))
+		assert.Equal(t, "bicycl", Stem("bicycle"))
+		assert.Equal(t, "city", Stem("cities"))
+	})
+	t.Run("german", func(t *testing.T) {
+		assert.Equal(t, "wander", Stem("Wanderungen"))
+		assert.Equal(t, "hund", Stem("Hunde"))
+	})
+	t.Run("short", func(t *testing.T) {
+		assert.Equal(t, "cat", Stem("cats"))
+		assert.Equal(t, "sea", Stem("sea"))
+		assert.Equal(t, "bus", Stem("bus"))
+	})
+}
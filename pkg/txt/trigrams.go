@@ -0,0 +1,56 @@
+package txt
+
+import (
+	"sort"
+	"strings"
+)
+
+// Trigrams returns the unique and sorted trigrams of a lowercase word padded with spaces,
+// so that the first and last characters are weighted as well.
+func Trigrams(word string) (results []string) {
+	if word == "" {
+		return results
+	}
+
+	w := []rune("  " + strings.ToLower(word) + " ")
+	found := make(map[string]bool)
+
+	for i := 0; i+3 <= len(w); i++ {
+		t := string(w[i : i+3])
+
+		if found[t] {
+			continue
+		}
+
+		found[t] = true
+		results = append(results, t)
+	}
+
+	sort.Strings(results)
+
+	return results
+}
+
+// Similarity returns the ratio of shared trigrams between two words from 0 to 1.
+func Similarity(a, b string) float64 {
+	ta, tb := Trigrams(a), Trigrams(b)
+
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+
+	shared := 0
+	found := make(map[string]bool, len(ta))
+
+	for _, t := range ta {
+		found[t] = true
+	}
+
+	for _, t := range tb {
+		if found[t] {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(ta)+len(tb)-shared)
+}
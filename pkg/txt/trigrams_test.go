@@ -0,0 +1,27 @@
+package txt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrigrams(t *testing.T) {
+	assert.Equal(t, []string{"  c", " ca", "at ", "cat"}, Trigrams("Cat"))
+	assert.Empty(t, Trigrams(""))
+}
+
+func TestSimilarity(t *testing.T) {
+	t.Run("equal", func(t *testing.T) {
+		assert.Equal(t, 1.0, Similarity("bicycle", "Bicycle"))
+	})
+	t.Run("typo", func(t *testing.T) {
+		assert.Greater(t, Similarity("bicycle", "bicylce"), 0.3)
+	})
+	t.Run("different", func(t *testing.T) {
+		assert.Less(t, Similarity("bicycle", "mountain"), 0.1)
+	})
+	t.Run("empty", func(t *testing.T) {
+		assert.Equal(t, 0.0, Similarity("", "cat"))
+	})
+}